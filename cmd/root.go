@@ -16,6 +16,9 @@ to reflect status, updates, and structure.`,
 }
 
 func init() {
+	// Global flags
+	rootCmd.PersistentFlags().StringVar(&commands.RemoteName, "remote", "", "Jira remote to use (see 'jit remote')")
+
 	// Ticket Creation Commands
 	epicCmd := commands.GetEpicCmd()
 	epicCmd.GroupID = "ticket-creation"
@@ -29,6 +32,14 @@ func init() {
 	subtaskCmd.GroupID = "ticket-creation"
 	rootCmd.AddCommand(subtaskCmd)
 
+	editCmd := commands.GetEditCmd()
+	editCmd.GroupID = "ticket-creation"
+	rootCmd.AddCommand(editCmd)
+
+	applyCmd := commands.GetApplyCmd()
+	applyCmd.GroupID = "ticket-creation"
+	rootCmd.AddCommand(applyCmd)
+
 	// Context Management Commands
 	trackCmd := commands.GetTrackCmd()
 	trackCmd.GroupID = "context-management"
@@ -43,6 +54,22 @@ func init() {
 	statusCmd.GroupID = "status-workflow"
 	rootCmd.AddCommand(statusCmd)
 
+	timerCmd := commands.GetTimerCmd()
+	timerCmd.GroupID = "status-workflow"
+	rootCmd.AddCommand(timerCmd)
+
+	worklogCmd := commands.GetWorklogCmd()
+	worklogCmd.GroupID = "status-workflow"
+	rootCmd.AddCommand(worklogCmd)
+
+	sprintCmd := commands.GetSprintCmd()
+	sprintCmd.GroupID = "status-workflow"
+	rootCmd.AddCommand(sprintCmd)
+
+	releaseCmd := commands.GetReleaseCmd()
+	releaseCmd.GroupID = "status-workflow"
+	rootCmd.AddCommand(releaseCmd)
+
 	cleanupCmd := commands.GetCleanupCmd()
 	cleanupCmd.GroupID = "status-workflow"
 	rootCmd.AddCommand(cleanupCmd)
@@ -52,10 +79,26 @@ func init() {
 	logCmd.GroupID = "view-navigation"
 	rootCmd.AddCommand(logCmd)
 
+	searchCmd := commands.GetSearchCmd()
+	searchCmd.GroupID = "view-navigation"
+	rootCmd.AddCommand(searchCmd)
+
 	linkCmd := commands.GetLinkCmd()
 	linkCmd.GroupID = "view-navigation"
 	rootCmd.AddCommand(linkCmd)
 
+	relateCmd := commands.GetRelateCmd()
+	relateCmd.GroupID = "view-navigation"
+	rootCmd.AddCommand(relateCmd)
+
+	refCmd := commands.GetRefCmd()
+	refCmd.GroupID = "view-navigation"
+	rootCmd.AddCommand(refCmd)
+
+	historyCmd := commands.GetHistoryCmd()
+	historyCmd.GroupID = "view-navigation"
+	rootCmd.AddCommand(historyCmd)
+
 	openCmd := commands.GetOpenCmd()
 	openCmd.GroupID = "view-navigation"
 	rootCmd.AddCommand(openCmd)
@@ -65,11 +108,43 @@ func init() {
 	commentCmd.GroupID = "collaboration"
 	rootCmd.AddCommand(commentCmd)
 
+	commentsCmd := commands.GetCommentsCmd()
+	commentsCmd.GroupID = "collaboration"
+	rootCmd.AddCommand(commentsCmd)
+
+	assignCmd := commands.GetAssignCmd()
+	assignCmd.GroupID = "collaboration"
+	rootCmd.AddCommand(assignCmd)
+
+	attachCmd := commands.GetAttachCmd()
+	attachCmd.GroupID = "collaboration"
+	rootCmd.AddCommand(attachCmd)
+
+	attachmentsCmd := commands.GetAttachmentsCmd()
+	attachmentsCmd.GroupID = "collaboration"
+	rootCmd.AddCommand(attachmentsCmd)
+
 	// Setup & Utility Commands
 	initCmd := commands.GetInitCmd()
 	initCmd.GroupID = "setup-utility"
 	rootCmd.AddCommand(initCmd)
 
+	authCmd := commands.GetAuthCmd()
+	authCmd.GroupID = "setup-utility"
+	rootCmd.AddCommand(authCmd)
+
+	remoteCmd := commands.GetRemoteCmd()
+	remoteCmd.GroupID = "setup-utility"
+	rootCmd.AddCommand(remoteCmd)
+
+	metaCmd := commands.GetMetaCmd()
+	metaCmd.GroupID = "setup-utility"
+	rootCmd.AddCommand(metaCmd)
+
+	serveCmd := commands.GetServeCmd()
+	serveCmd.GroupID = "setup-utility"
+	rootCmd.AddCommand(serveCmd)
+
 	versionCmd := commands.GetVersionCmd()
 	versionCmd.GroupID = "setup-utility"
 	rootCmd.AddCommand(versionCmd)
@@ -7,8 +7,14 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/lunchboxsushi/jit/internal/network"
 )
 
+// DefaultTimeout bounds a request to the provider, response included, when
+// the config does not set one
+const DefaultTimeout = 30 * time.Second
+
 // OpenAIProvider implements the Provider interface for OpenAI
 type OpenAIProvider struct {
 	config      *Config
@@ -63,6 +69,9 @@ func NewOpenAIProvider(config *Config) (Provider, error) {
 	if config.Temperature == 0 {
 		config.Temperature = 0.7
 	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
 
 	// Determine base URL
 	baseURL := config.BaseURL
@@ -70,10 +79,13 @@ func NewOpenAIProvider(config *Config) (Provider, error) {
 		baseURL = "https://api.openai.com/v1"
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	// Create HTTP client; the transport carries the proxy, TLS and connection
+	// timeouts, and the client bounds the whole request
+	transport := config.Transport
+	if transport == nil {
+		transport = network.DefaultTransport()
 	}
+	client := &http.Client{Transport: transport, Timeout: config.Timeout}
 
 	// Create template manager
 	templateMgr := NewTemplateManager("./templates/ai")
@@ -2,7 +2,9 @@ package ai
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
@@ -34,6 +36,8 @@ type Config struct {
 	Temperature float64
 	APIKey      string
 	BaseURL     string
+	Transport   http.RoundTripper // Sends requests; defaults to network.DefaultTransport
+	Timeout     time.Duration     // Limit on each request; defaults to DefaultTimeout
 }
 
 // NewProvider creates a new AI provider based on configuration
@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/lunchboxsushi/jit/internal/ui"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// States of a plan entry after 'jit apply'
+const (
+	applyCreated = "created"
+	applyExists  = "exists"
+	applyPlanned = "would create"
+	applyFailed  = "failed"
+	applySkipped = "skipped"
+)
+
+var (
+	applyDryRunFlag bool
+	applyFormatFlag string
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <plan-file>",
+	Short: "Create a hierarchy of tickets from a plan file",
+	Long: `Create the epics, tasks and subtasks described in a plan file, parents
+first, using Jira's bulk create.
+
+Plans are YAML (.yml, .yaml) or a markdown outline (.md) in which "# "
+headings are epics, "## " headings tasks and "### " headings subtasks, with
+optional "priority: High" style field lines under each heading.
+
+Applying is idempotent: entries that already exist in Jira, with the same
+title under the same parent, are reused rather than created again, and an
+entry with a key refers to an existing ticket. Created tickets are tracked
+locally.
+
+Examples:
+  jit apply plan.yml              # Create everything in the plan
+  jit apply plan.md --dry-run     # Show what would be created`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		plan, err := loadPlan(ctx, args[0])
+		if err != nil {
+			HandleError(err, "Failed to read plan")
+			return
+		}
+
+		results, err := applyPlan(cmd.Context(), ctx, plan, applyDryRunFlag)
+		printPlanResults(plan, results)
+		if err != nil {
+			HandleError(err, "Failed to apply plan")
+			return
+		}
+
+		if applyDryRunFlag {
+			return
+		}
+
+		if err := trackPlanTickets(cmd.Context(), ctx, results); err != nil {
+			PrintWarning(fmt.Sprintf("Failed to track plan tickets: %v", err))
+		}
+
+		failed := 0
+		for _, result := range results {
+			if result.State == applyFailed || result.State == applySkipped {
+				failed++
+			}
+		}
+		if failed > 0 {
+			PrintWarning(fmt.Sprintf("%d plan entries were not created", failed))
+			return
+		}
+		PrintSuccess("Plan applied")
+	},
+}
+
+func init() {
+	applyCmd.Flags().BoolVar(&applyDryRunFlag, "dry-run", false, "Show what would be created without creating it")
+	applyCmd.Flags().StringVar(&applyFormatFlag, "format", "", "Plan format: yaml or markdown (default: from the file extension)")
+}
+
+// planResult records what applying a plan did with one entry
+type planResult struct {
+	Key   string
+	State string
+	Err   error
+}
+
+// loadPlan reads and parses a plan file
+func loadPlan(ctx *CommandContext, path string) (*types.Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(applyFormatFlag)
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".md", ".markdown":
+			format = "markdown"
+		default:
+			format = "yaml"
+		}
+	}
+
+	editor := ui.NewEditor()
+	editor.CustomFields = ctx.TicketService.CustomFieldNames()
+
+	switch format {
+	case "yaml", "yml":
+		return editor.ParsePlanYAML(string(data))
+	case "markdown", "md":
+		return editor.ParsePlanMarkdown(string(data))
+	}
+	return nil, fmt.Errorf("unknown plan format %q (use yaml or markdown)", applyFormatFlag)
+}
+
+// applyPlan creates a plan's entries level by level so every parent exists
+// before its children. Entries that already exist are looked up rather than
+// created; on a dry run nothing is created.
+func applyPlan(cmdCtx context.Context, ctx *CommandContext, plan *types.Plan, dryRun bool) (map[*types.PlanEntry]*planResult, error) {
+	project := plan.Project
+	if project == "" {
+		project = ctx.Config.Jira.Project
+	}
+
+	results := make(map[*types.PlanEntry]*planResult)
+	for _, level := range plan.Levels() {
+		var pending []*types.PlanEntry
+		var tickets []*types.Ticket
+
+		for _, entry := range level {
+			result := &planResult{}
+			results[entry] = result
+
+			if entry.Key != "" {
+				result.Key, result.State = entry.Key, applyExists
+				continue
+			}
+
+			parentKey := ""
+			if entry.Parent != nil {
+				parent := results[entry.Parent]
+				if parent.Key == "" {
+					// The parent is still to be created, so nothing exists under it
+					if parent.State == applyPlanned {
+						result.State = applyPlanned
+					} else {
+						result.State = applySkipped
+					}
+					continue
+				}
+				parentKey = parent.Key
+			}
+
+			existing, err := ctx.TicketService.FindTicket(cmdCtx, project, entry.Type, entry.Title, parentKey)
+			if err != nil {
+				return results, fmt.Errorf("failed to look up %q: %v", entry.Title, err)
+			}
+			if existing != nil {
+				result.Key, result.State = existing.Key, applyExists
+				continue
+			}
+
+			if dryRun {
+				result.State = applyPlanned
+				continue
+			}
+			pending = append(pending, entry)
+			tickets = append(tickets, planTicket(project, entry, parentKey))
+		}
+
+		if len(tickets) == 0 {
+			continue
+		}
+
+		created, err := ctx.TicketService.CreateTickets(cmdCtx, tickets)
+		for i, entry := range pending {
+			result := results[entry]
+			switch {
+			case created[i].Key != "":
+				result.Key, result.State = created[i].Key, applyCreated
+			case created[i].Err != nil:
+				result.State, result.Err = applyFailed, created[i].Err
+			default:
+				result.State = applySkipped
+			}
+		}
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// planTicket builds the ticket to create for a plan entry
+func planTicket(project string, entry *types.PlanEntry, parentKey string) *types.Ticket {
+	ticket := types.NewTicket("", entry.Title, entry.Type)
+	ticket.Description = entry.Description
+	ticket.Priority = entry.Priority
+	ticket.Metadata.Project = project
+	ticket.Metadata.Assignee = entry.Assignee
+	ticket.Metadata.Labels = entry.Labels
+	ticket.Relationships.ParentKey = parentKey
+	for name, value := range entry.Fields {
+		ticket.JiraData.CustomFields[name] = value
+	}
+	return ticket
+}
+
+// printPlanResults prints each plan entry with its key and what happened to it
+func printPlanResults(plan *types.Plan, results map[*types.PlanEntry]*planResult) {
+	for _, entry := range plan.Entries() {
+		result, ok := results[entry]
+		if !ok {
+			continue
+		}
+
+		key := result.Key
+		if key == "" {
+			key = "-"
+		}
+		indent := strings.Repeat("  ", entry.Depth())
+		fmt.Printf("%s%-12s %s [%s] (%s)", indent, key, entry.Title, entry.Type, result.State)
+		if result.Err != nil {
+			fmt.Printf(": %v", result.Err)
+		}
+		fmt.Println()
+	}
+}
+
+// trackPlanTickets saves every ticket the plan refers to locally
+func trackPlanTickets(cmdCtx context.Context, ctx *CommandContext, results map[*types.PlanEntry]*planResult) error {
+	var keys []string
+	for _, result := range results {
+		if result.Key != "" {
+			keys = append(keys, result.Key)
+		}
+	}
+
+	for start := 0; start < len(keys); start += jira.MaxBulkCreate {
+		end := start + jira.MaxBulkCreate
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		tickets, err := ctx.TicketService.Search(fmt.Sprintf("key in (%s)", strings.Join(keys[start:end], ",")), nil).Collect(cmdCtx)
+		if err != nil {
+			return err
+		}
+		for _, ticket := range tickets {
+			if err := ctx.Storage.SaveTicket(ticket); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetApplyCmd returns the apply command
+func GetApplyCmd() *cobra.Command {
+	return applyCmd
+}
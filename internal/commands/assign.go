@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/utils"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// assignCompletionTimeout bounds the user search behind shell completion
+const assignCompletionTimeout = 3 * time.Second
+
+var (
+	assignUnassignFlag bool
+)
+
+var assignCmd = &cobra.Command{
+	Use:   "assign [ticket-key] <user>",
+	Short: "Assign a ticket to a user",
+	Long: `Assign a Jira ticket to a user. If no ticket is specified, uses current focus.
+
+The user may be an email, a display name, an account ID (a username on
+Jira Server) or "me". Users are looked up in Jira and remembered locally, so
+known users resolve offline and complete in the shell.
+
+Examples:
+  jit assign me                         # Assign current focus to yourself
+  jit assign jane@example.com           # Assign current focus by email
+  jit assign SRE-1234 "Jane Doe"        # Assign a specific ticket by name
+  jit assign --unassign SRE-1234        # Remove the assignee`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if assignUnassignFlag {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
+	ValidArgsFunction: completeAssignee,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		// The user is always the last argument
+		var who string
+		if !assignUnassignFlag {
+			who, args = args[len(args)-1], args[:len(args)-1]
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		var user *types.User
+		if !assignUnassignFlag {
+			user, err = ctx.TicketService.Users().Resolve(cmd.Context(), who)
+			if err != nil {
+				HandleError(err, "Failed to find user")
+				return
+			}
+		}
+
+		if err := ctx.TicketService.AssignTicket(cmd.Context(), ticketKey, user); err != nil {
+			HandleError(err, "Failed to assign ticket")
+			return
+		}
+
+		// Keep the local copy in step
+		if ctx.Storage.Exists(ticketKey) {
+			if ticket, err := ctx.Storage.LoadTicket(ticketKey); err == nil {
+				ticket.Metadata.Assignee, ticket.Metadata.AssigneeName = "", ""
+				if user != nil {
+					ticket.Metadata.Assignee, ticket.Metadata.AssigneeName = user.ID, user.DisplayName
+				}
+				if err := ctx.Storage.SaveTicket(ticket); err != nil {
+					PrintWarning(fmt.Sprintf("Failed to update local ticket: %v", err))
+				}
+			}
+		}
+
+		if user == nil {
+			PrintSuccess(fmt.Sprintf("Unassigned %s", ticketKey))
+			return
+		}
+		PrintSuccess(fmt.Sprintf("Assigned %s to %s", ticketKey, user.String()))
+	},
+}
+
+func init() {
+	assignCmd.Flags().BoolVar(&assignUnassignFlag, "unassign", false, "Remove the assignee")
+}
+
+// completeAssignee completes users from the local directory, searching Jira
+// for more when the user has typed enough to narrow it down
+func completeAssignee(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 || assignUnassignFlag {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, err := InitializeCommand()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	directory := ctx.TicketService.Users()
+
+	if len(strings.TrimSpace(toComplete)) >= 2 {
+		searchCtx, cancel := context.WithTimeout(context.Background(), assignCompletionTimeout)
+		directory.Search(searchCtx, toComplete)
+		cancel()
+	}
+
+	type candidate struct {
+		completion string
+		score      int
+	}
+	var candidates []candidate
+	for _, user := range directory.Known() {
+		score := utils.FuzzyScore(toComplete, user.DisplayName, user.Email)
+		if score == 0 {
+			continue
+		}
+
+		// Emails complete without quoting; hidden emails fall back to the name
+		value := user.Email
+		if value == "" {
+			value = user.DisplayName
+		}
+		candidates = append(candidates, candidate{value + "\t" + user.String(), score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	completions := []string{"me\tYourself"}
+	for _, c := range candidates {
+		completions = append(completions, c.completion)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// GetAssignCmd returns the assign command
+func GetAssignCmd() *cobra.Command {
+	return assignCmd
+}
@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	attachTicketFlag       string
+	attachmentsDownloadDir string
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <file>...",
+	Short: "Attach files to a Jira ticket",
+	Long: `Upload files as attachments to a Jira ticket. Uses current focus unless
+a ticket is given with --ticket.
+
+Images referenced by local path in descriptions written with 'jit epic',
+'jit task', 'jit subtask' and 'jit edit' are uploaded automatically.
+
+Examples:
+  jit attach screenshot.png              # Attach to current focus
+  jit attach logs.txt trace.json         # Attach several files
+  jit attach -t SRE-1234 screenshot.png  # Attach to a specific ticket`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		var ticketArgs []string
+		if attachTicketFlag != "" {
+			ticketArgs = []string{attachTicketFlag}
+		}
+		ticketKey, ok := ticketKeyOrFocus(ctx, ticketArgs)
+		if !ok {
+			return
+		}
+
+		for _, path := range args {
+			attachment, err := ctx.TicketService.AttachFile(cmd.Context(), ticketKey, path)
+			if err != nil {
+				HandleError(err, "Failed to attach file")
+				return
+			}
+			PrintSuccess(fmt.Sprintf("Attached %s to %s (%s)", attachment.Filename, ticketKey, formatSize(attachment.Size)))
+		}
+	},
+}
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments [ticket-key]",
+	Short: "List or download a ticket's attachments",
+	Long: `List the files attached to a Jira ticket, or download them all into a
+directory. If no ticket is specified, uses current focus.
+
+Examples:
+  jit attachments                   # List attachments of current focus
+  jit attachments SRE-1234          # List attachments of a specific ticket
+  jit attachments --download out/   # Download them into out/`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		if attachmentsDownloadDir != "" {
+			paths, err := ctx.TicketService.DownloadAttachments(cmd.Context(), ticketKey, attachmentsDownloadDir)
+			for _, path := range paths {
+				fmt.Printf("  %s\n", path)
+			}
+			if err != nil {
+				HandleError(err, "Failed to download attachments")
+				return
+			}
+			PrintSuccess(fmt.Sprintf("Downloaded %d attachment(s) from %s", len(paths), ticketKey))
+			return
+		}
+
+		attachments, err := ctx.TicketService.GetAttachments(cmd.Context(), ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to fetch attachments")
+			return
+		}
+
+		if len(attachments) == 0 {
+			fmt.Printf("%s has no attachments.\n", ticketKey)
+			return
+		}
+
+		fmt.Printf("Attachments of %s:\n", ticketKey)
+		for _, attachment := range attachments {
+			fmt.Printf("  %-32s %8s  %s  %s\n",
+				attachment.Filename,
+				formatSize(attachment.Size),
+				attachment.Created.Local().Format("2006-01-02"),
+				attachment.Author)
+		}
+	},
+}
+
+func init() {
+	attachCmd.Flags().StringVarP(&attachTicketFlag, "ticket", "t", "", "Ticket to attach to (defaults to current focus)")
+	attachmentsCmd.Flags().StringVar(&attachmentsDownloadDir, "download", "", "Download all attachments into this directory")
+}
+
+// uploadDescriptionImages uploads the images a ticket's description
+// references on disk and rewrites the description to embed them from Jira
+func uploadDescriptionImages(cmdCtx context.Context, ctx *CommandContext, ticket *types.Ticket) error {
+	baseDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %v", err)
+	}
+
+	description, uploaded, err := ctx.TicketService.UploadMarkdownImages(cmdCtx, ticket.Key, ticket.Description, baseDir)
+	for _, path := range uploaded {
+		PrintInfo(fmt.Sprintf("Uploaded %s to %s", filepath.Base(path), ticket.Key))
+	}
+	if err != nil {
+		return err
+	}
+
+	ticket.Description = description
+	return nil
+}
+
+// formatSize renders a file size for humans
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+// GetAttachCmd returns the attach command
+func GetAttachCmd() *cobra.Command {
+	return attachCmd
+}
+
+// GetAttachmentsCmd returns the attachments command
+func GetAttachmentsCmd() *cobra.Command {
+	return attachmentsCmd
+}
@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+// authLoginTimeout bounds how long login waits for the browser redirect
+const authLoginTimeout = 5 * time.Minute
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage Jira authentication",
+	Long: `Log in to Jira Cloud with OAuth 2.0 instead of an API token.
+
+OAuth requires an app registered in the Atlassian developer console with a
+callback URL of http://127.0.0.1:<port>/callback, configured under jira.oauth:
+
+  jira:
+    oauth:
+      client_id: "..."
+      client_secret: "${JIRA_OAUTH_SECRET}"
+      callback_port: 8731
+
+Tokens are stored in the data directory, readable only by you, and are
+refreshed automatically when they expire.
+
+Examples:
+  jit auth login            # Authorize jit in the browser
+  jit auth status           # Show how jit authenticates
+  jit auth logout           # Forget the stored tokens`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to Jira with OAuth 2.0",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		oauth := ctx.JiraClient.OAuth()
+		if oauth == nil {
+			fmt.Println("OAuth is not configured.")
+			fmt.Println("Add jira.oauth.client_id to your config to use 'jit auth login'.")
+			return
+		}
+
+		listener, err := oauth.Listen()
+		if err != nil {
+			HandleError(err, "Failed to start login")
+			return
+		}
+
+		loginCtx, cancel := context.WithTimeout(cmd.Context(), authLoginTimeout)
+		defer cancel()
+
+		token, err := oauth.Login(loginCtx, listener, func(authURL string) error {
+			fmt.Println("Opening your browser to authorize jit...")
+			if err := openBrowser(authURL); err != nil {
+				fmt.Println("Could not open a browser. Visit this URL to continue:")
+			} else {
+				fmt.Println("If the browser did not open, visit:")
+			}
+			fmt.Printf("  %s\n", authURL)
+			return nil
+		})
+		if err != nil {
+			HandleError(err, "Login failed")
+			return
+		}
+
+		PrintSuccess(fmt.Sprintf("Logged in to %s", token.SiteURL))
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show how jit authenticates with Jira",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		oauth := ctx.JiraClient.OAuth()
+		var token *jira.OAuthToken
+		if oauth != nil {
+			if token, err = oauth.Token(cmd.Context()); err != nil {
+				HandleError(err, "OAuth session is not usable")
+				return
+			}
+		}
+
+		switch {
+		case token != nil:
+			fmt.Printf("Method:  OAuth 2.0\n")
+			fmt.Printf("Site:    %s\n", token.SiteURL)
+			fmt.Printf("Scopes:  %s\n", token.Scope)
+			if !token.Expiry.IsZero() {
+				fmt.Printf("Expires: %s\n", token.Expiry.Local().Format("2006-01-02 15:04"))
+			}
+		case oauth != nil && ctx.Config.Jira.Token == "":
+			fmt.Println("Not logged in. Run 'jit auth login'.")
+			return
+		default:
+			if oauth != nil {
+				fmt.Println("OAuth is configured but not logged in; using the API token.")
+			}
+			fmt.Printf("Method:  API token (%s)\n", ctx.JiraClient.Flavor(cmd.Context()))
+			fmt.Printf("Site:    %s\n", ctx.Config.Jira.URL)
+		}
+
+		user, err := ctx.JiraClient.GetCurrentUser(cmd.Context())
+		if err != nil {
+			HandleError(err, "Failed to reach Jira")
+			return
+		}
+		fmt.Printf("User:    %s\n", user.DisplayName)
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Forget the stored OAuth tokens",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		oauth := ctx.JiraClient.OAuth()
+		if oauth == nil {
+			fmt.Println("OAuth is not configured; nothing to log out of.")
+			return
+		}
+
+		if err := oauth.Logout(); err != nil {
+			HandleError(err, "Failed to log out")
+			return
+		}
+
+		PrintSuccess("Logged out of Jira")
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+}
+
+// GetAuthCmd returns the auth command
+func GetAuthCmd() *cobra.Command {
+	return authCmd
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -12,17 +13,22 @@ import (
 
 var (
 	commentMessageFlag bool
+	commentEditFlag    string
+	commentDeleteFlag  string
 )
 
 var commentCmd = &cobra.Command{
 	Use:   "comment [ticket-key]",
 	Short: "Add comment to Jira ticket",
-	Long: `Add a comment to a Jira ticket. If no ticket is specified, uses current focus.
+	Long: `Add a comment to a Jira ticket, or edit or delete one of its comments.
+If no ticket is specified, uses current focus.
 	
 Examples:
   jit comment                    # Add comment to current focus
   jit comment SRE-1234          # Add comment to specific ticket
-  jit comment -m "Quick note"   # Inline comment`,
+  jit comment -m "Quick note"   # Inline comment
+  jit comment --edit 10042      # Edit a comment (IDs from 'jit comments')
+  jit comment --delete 10042    # Delete a comment`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		// Initialize command context
@@ -71,6 +77,21 @@ Examples:
 			return
 		}
 
+		if commentDeleteFlag != "" {
+			deleteComment(cmd.Context(), ctx, ticketKey, commentDeleteFlag)
+			return
+		}
+
+		// An edit starts from the comment's current body
+		var existing *types.Comment
+		if commentEditFlag != "" {
+			existing, err = findComment(cmd.Context(), ctx, ticketKey, commentEditFlag)
+			if err != nil {
+				HandleError(err, "Failed to find comment")
+				return
+			}
+		}
+
 		// Get comment content
 		var commentBody string
 		if commentMessageFlag {
@@ -83,7 +104,7 @@ Examples:
 			commentBody = strings.Join(args[1:], " ")
 		} else {
 			// Open editor for comment
-			commentBody, err = getCommentFromEditor(ticket)
+			commentBody, err = getCommentFromEditor(ticket, existing)
 			if err != nil {
 				HandleError(err, "Failed to get comment from editor")
 				return
@@ -96,6 +117,11 @@ Examples:
 			return
 		}
 
+		if existing != nil {
+			editComment(cmd.Context(), ctx, ticketKey, existing.ID, commentBody)
+			return
+		}
+
 		// AI enrichment (if provider available)
 		if ctx.AIProvider != nil {
 			enrichedComment, err := ctx.EnrichCommentWithAI(commentBody, ticketKey)
@@ -120,10 +146,82 @@ Examples:
 
 func init() {
 	commentCmd.Flags().BoolVarP(&commentMessageFlag, "message", "m", false, "Add inline comment (requires comment text)")
+	commentCmd.Flags().StringVar(&commentEditFlag, "edit", "", "Edit the comment with this ID")
+	commentCmd.Flags().StringVar(&commentDeleteFlag, "delete", "", "Delete the comment with this ID")
+	commentCmd.MarkFlagsMutuallyExclusive("edit", "delete")
+}
+
+// findComment fetches a ticket's comments and returns the one with an ID
+func findComment(cmdCtx context.Context, ctx *CommandContext, ticketKey, commentID string) (*types.Comment, error) {
+	comments, err := ctx.TicketService.GetComments(cmdCtx, ticketKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		if comment.ID == commentID {
+			return comment, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no comment %s (see 'jit comments %s')", ticketKey, commentID, ticketKey)
 }
 
-// getCommentFromEditor opens an editor to get comment content
-func getCommentFromEditor(ticket *types.Ticket) (string, error) {
+// editComment replaces a comment's body in Jira and in the stored comments
+func editComment(cmdCtx context.Context, ctx *CommandContext, ticketKey, commentID, commentBody string) {
+	fmt.Printf("Updating comment %s on %s...\n", commentID, ticketKey)
+
+	updated, err := ctx.TicketService.EditComment(cmdCtx, ticketKey, commentID, commentBody)
+	if err != nil {
+		HandleError(err, "Failed to edit comment")
+		return
+	}
+
+	updateStoredComments(ctx, ticketKey, func(comments []*types.Comment) []*types.Comment {
+		for i, comment := range comments {
+			if comment.ID == commentID {
+				comments[i] = updated
+			}
+		}
+		return comments
+	})
+
+	PrintSuccess(fmt.Sprintf("Comment %s on %s updated", commentID, ticketKey))
+}
+
+// deleteComment deletes a comment in Jira and from the stored comments
+func deleteComment(cmdCtx context.Context, ctx *CommandContext, ticketKey, commentID string) {
+	if err := ctx.TicketService.DeleteComment(cmdCtx, ticketKey, commentID); err != nil {
+		HandleError(err, "Failed to delete comment")
+		return
+	}
+
+	updateStoredComments(ctx, ticketKey, func(comments []*types.Comment) []*types.Comment {
+		var kept []*types.Comment
+		for _, comment := range comments {
+			if comment.ID != commentID {
+				kept = append(kept, comment)
+			}
+		}
+		return kept
+	})
+
+	PrintSuccess(fmt.Sprintf("Comment %s deleted from %s", commentID, ticketKey))
+}
+
+// updateStoredComments applies a change to the comments stored for a
+// ticket, if any have been stored
+func updateStoredComments(ctx *CommandContext, ticketKey string, change func([]*types.Comment) []*types.Comment) {
+	comments, err := ctx.Storage.LoadComments(ticketKey)
+	if err == nil && comments != nil {
+		err = ctx.Storage.SaveComments(ticketKey, change(comments))
+	}
+	if err != nil {
+		PrintWarning(fmt.Sprintf("Failed to update stored comments: %v", err))
+	}
+}
+
+// getCommentFromEditor opens an editor to get comment content, starting
+// from an existing comment's body when one is given
+func getCommentFromEditor(ticket *types.Ticket, existing *types.Comment) (string, error) {
 	// Create temporary file for editing
 	tempFile, err := os.CreateTemp("", "jit-comment-*.md")
 	if err != nil {
@@ -138,6 +236,9 @@ Add your comment below this line:
 ---
 
 `, ticket.Key, ticket.Title)
+	if existing != nil {
+		template += existing.Body + "\n"
+	}
 
 	// Write template to temp file
 	if err := os.WriteFile(tempFile.Name(), []byte(template), 0644); err != nil {
@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commentsSinceFlag  string
+	commentsAuthorFlag string
+	commentsLimitFlag  int
+)
+
+var commentsCmd = &cobra.Command{
+	Use:   "comments [ticket-key]",
+	Short: "List the comments on a ticket",
+	Long: `List the comments on a Jira ticket, oldest first, with their IDs for
+'jit comment --edit' and '--delete'. If no ticket is specified, uses current
+focus.
+
+Comments on tracked tickets are stored locally and shown from there when
+Jira cannot be reached.
+
+Examples:
+  jit comments                    # Comments on current focus
+  jit comments SRE-1234           # Comments on a specific ticket
+  jit comments --since 7d         # Comments from the last week
+  jit comments --author me -n 5   # Your last five comments`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		var since time.Time
+		if commentsSinceFlag != "" {
+			if since, err = parseSince(commentsSinceFlag, time.Now()); err != nil {
+				HandleError(err, "Invalid --since")
+				return
+			}
+		}
+
+		comments, err := fetchComments(cmd.Context(), ctx, ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to fetch comments")
+			return
+		}
+
+		comments, err = filterComments(cmd.Context(), ctx, comments, since, commentsAuthorFlag)
+		if err != nil {
+			HandleError(err, "Failed to filter comments")
+			return
+		}
+		if commentsLimitFlag > 0 && len(comments) > commentsLimitFlag {
+			comments = comments[len(comments)-commentsLimitFlag:]
+		}
+
+		if len(comments) == 0 {
+			fmt.Printf("No comments on %s.\n", ticketKey)
+			return
+		}
+
+		fmt.Printf("Comments on %s:\n", ticketKey)
+		for _, comment := range comments {
+			printComment(comment)
+		}
+	},
+}
+
+func init() {
+	commentsCmd.Flags().StringVar(&commentsSinceFlag, "since", "", "Only comments posted since a date (2024-03-01) or for a duration (12h, 7d, 2w)")
+	commentsCmd.Flags().StringVar(&commentsAuthorFlag, "author", "", "Only comments by this user (name, account ID or \"me\")")
+	commentsCmd.Flags().IntVarP(&commentsLimitFlag, "limit", "n", 0, "Only the most recent N comments")
+}
+
+// fetchComments fetches a ticket's comments and stores them when the ticket
+// is tracked. When Jira cannot be reached, stored comments are used instead.
+func fetchComments(cmdCtx context.Context, ctx *CommandContext, ticketKey string) ([]*types.Comment, error) {
+	comments, err := ctx.TicketService.GetComments(cmdCtx, ticketKey)
+	if err != nil {
+		stored, loadErr := ctx.Storage.LoadComments(ticketKey)
+		if loadErr != nil || stored == nil {
+			return nil, err
+		}
+		PrintWarning(fmt.Sprintf("%v; showing stored comments", err))
+		return stored, nil
+	}
+
+	if ctx.Storage.Exists(ticketKey) {
+		if err := ctx.Storage.SaveComments(ticketKey, comments); err != nil {
+			PrintWarning(fmt.Sprintf("Failed to store comments: %v", err))
+		}
+	}
+	return comments, nil
+}
+
+// filterComments keeps the comments posted since a time by an author; a zero
+// time or empty author matches every comment
+func filterComments(cmdCtx context.Context, ctx *CommandContext, comments []*types.Comment, since time.Time, author string) ([]*types.Comment, error) {
+	author = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(author), "@"))
+
+	authorID := ""
+	if strings.EqualFold(author, "me") {
+		me, err := ctx.TicketService.Users().Me(cmdCtx)
+		if err != nil {
+			return nil, err
+		}
+		authorID = me.ID
+	}
+
+	var filtered []*types.Comment
+	for _, comment := range comments {
+		if comment.Created.Before(since) {
+			continue
+		}
+		switch {
+		case author == "":
+		case authorID != "":
+			if comment.AuthorID != authorID {
+				continue
+			}
+		case !strings.EqualFold(comment.AuthorID, author) &&
+			!strings.Contains(strings.ToLower(comment.Author), strings.ToLower(author)):
+			continue
+		}
+		filtered = append(filtered, comment)
+	}
+	return filtered, nil
+}
+
+// parseSince reads a date (2024-03-01), a timestamp (RFC 3339) or a duration
+// before now such as 90m, 12h, 7d or 2w
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	// Days and weeks are not units time.ParseDuration knows
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if !strings.HasSuffix(value, suffix) {
+			continue
+		}
+		if count, err := strconv.Atoi(strings.TrimSuffix(value, suffix)); err == nil && count >= 0 {
+			return now.Add(-time.Duration(count) * unit), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not a date or duration (use 2024-03-01, 12h, 7d or 2w)", value)
+}
+
+// printComment prints a comment's header and its markdown body, indented
+func printComment(comment *types.Comment) {
+	header := fmt.Sprintf("[%s] %s  %s", comment.ID, comment.Author, comment.Created.Local().Format("2006-01-02 15:04"))
+	if comment.Edited() {
+		header += " " + StatusToDo.Render("(edited)")
+	}
+	fmt.Printf("\n%s\n", header)
+
+	for _, line := range strings.Split(strings.TrimSpace(comment.Body), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+// GetCommentsCmd returns the comments command
+func GetCommentsCmd() *cobra.Command {
+	return commentsCmd
+}
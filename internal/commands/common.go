@@ -3,15 +3,30 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/lunchboxsushi/jit/internal/ai"
 	"github.com/lunchboxsushi/jit/internal/config"
 	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/lunchboxsushi/jit/internal/network"
 	"github.com/lunchboxsushi/jit/internal/storage"
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
 
+// RemoteName is the remote chosen with the global --remote flag
+var RemoteName string
+
+// selectedRemote returns the remote named by --remote or JIT_REMOTE; empty
+// selects the configured default
+func selectedRemote() string {
+	if RemoteName != "" {
+		return RemoteName
+	}
+	return os.Getenv("JIT_REMOTE")
+}
+
 // CommandContext holds the common context for commands
 type CommandContext struct {
 	Config         *types.Config
@@ -30,15 +45,37 @@ func InitializeCommand() (*CommandContext, error) {
 		return nil, fmt.Errorf("configuration error: %v\nRun 'jit init' to create a configuration file", err)
 	}
 
-	// Initialize storage
-	storageInstance, err := storage.NewJSONStorage(cfg.App.DataDir)
+	// Select the Jira remote: --remote, then JIT_REMOTE, then default_remote
+	cfg, err = cfg.ForRemote(selectedRemote())
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize storage, separate for each remote
+	storageInstance, err := storage.NewJSONStorage(storage.RemoteDataDir(cfg.App.DataDir, cfg.ActiveRemote))
 	if err != nil {
 		return nil, fmt.Errorf("storage error: %v", err)
 	}
 
+	// Every outbound client shares one transport with the proxy and TLS settings
+	transport, err := network.NewTransport(&cfg.Network)
+	if err != nil {
+		return nil, fmt.Errorf("network configuration error: %v", err)
+	}
+
+	requestTimeout := network.RequestTimeout(&cfg.Network)
+
 	// Initialize Jira services
-	jiraClient := jira.NewClient(&cfg.Jira)
+	jiraClient := jira.NewClientWithTransport(&cfg.Jira, transport)
+	jiraClient.SetRequestTimeout(requestTimeout)
+	if cfg.Jira.OAuth != nil {
+		oauthClient := jira.NewOAuthClient(cfg.Jira.OAuth, cfg.Jira.URL, storageInstance)
+		oauthClient.SetTransport(transport, requestTimeout)
+		jiraClient.SetOAuthClient(oauthClient)
+	}
 	ticketService := jira.NewTicketService(jiraClient)
+	ticketService.SetMetadataService(jira.NewMetadataService(jiraClient, storageInstance))
+	ticketService.SetUserDirectory(jira.NewUserDirectory(jiraClient, storageInstance))
 	contextManager := storage.NewContextManager(storageInstance)
 
 	// Initialize AI provider
@@ -51,6 +88,8 @@ func InitializeCommand() (*CommandContext, error) {
 			Temperature: 0.7, // Default temperature
 			APIKey:      cfg.AI.APIKey,
 			BaseURL:     "", // Use default OpenAI URL
+			Transport:   transport,
+			Timeout:     cfg.AI.Timeout,
 		}
 
 		aiProvider, err = ai.NewProvider(aiConfig)
@@ -103,6 +142,17 @@ func (ctx *CommandContext) CreateTicketInJira(ticket *types.Ticket, ticketType s
 		return fmt.Errorf("failed to create %s in Jira: %v\nTip: Use --no-create to save locally only", ticketType, err)
 	}
 
+	// Upload local images the description references, then embed them
+	before := *createdTicket
+	if err := uploadDescriptionImages(context.Background(), ctx, createdTicket); err != nil {
+		PrintWarning(fmt.Sprintf("Failed to upload images: %v", err))
+	} else if createdTicket.Description != before.Description {
+		if _, err := ctx.TicketService.UpdateTicket(context.Background(), &before, createdTicket); err != nil {
+			PrintWarning(fmt.Sprintf("Failed to embed uploaded images: %v", err))
+			createdTicket.Description = before.Description
+		}
+	}
+
 	// Save the created ticket locally
 	if err := ctx.Storage.SaveTicket(createdTicket); err != nil {
 		return fmt.Errorf("failed to save %s locally: %v", ticketType, err)
@@ -172,89 +222,54 @@ func (ctx *CommandContext) TrackTicketWithChildren(ticketKey string, fetchChildr
 		return fmt.Errorf("failed to add to recent tickets: %v", err)
 	}
 
-	// Fetch children if requested and ticket is an epic
-	if fetchChildren && ticket.Type == types.TicketTypeEpic {
-		if err := ctx.trackEpicChildren(ticketKey); err != nil {
-			return fmt.Errorf("failed to track epic children: %v", err)
-		}
-	}
-
-	// Fetch subtasks if requested and ticket is a task
-	if fetchChildren && ticket.Type == types.TicketTypeTask {
-		if err := ctx.trackTaskSubtasks(ticketKey); err != nil {
-			return fmt.Errorf("failed to track task subtasks: %v", err)
+	// Fetch the epic's or task's descendants if requested
+	if fetchChildren {
+		if err := ctx.trackHierarchy(context.Background(), ticket); err != nil {
+			return fmt.Errorf("failed to track the whole hierarchy of %s: %v", ticketKey, err)
 		}
 	}
 
 	return nil
 }
 
-// trackEpicChildren tracks all children of an epic
-func (ctx *CommandContext) trackEpicChildren(epicKey string) error {
-	fmt.Printf("Fetching children of epic %s...\n", epicKey)
-
-	children, err := ctx.TicketService.GetEpicChildren(context.Background(), epicKey)
-	if err != nil {
-		return fmt.Errorf("failed to fetch epic children: %v", err)
+// trackHierarchy saves the children and subtasks of an epic or task. The
+// parts that could be fetched are saved even when others fail; the failures
+// are reported together at the end.
+func (ctx *CommandContext) trackHierarchy(cmdCtx context.Context, ticket *types.Ticket) error {
+	if !ticket.IsEpic() && !ticket.IsTask() {
+		return nil
 	}
 
-	if len(children) == 0 {
+	fmt.Printf("Fetching children of %s %s...\n", strings.ToLower(ticket.Type), ticket.Key)
+	hierarchy := ctx.TicketService.FetchHierarchy(cmdCtx, ticket, func(progress jira.HierarchyProgress) {
+		fmt.Printf("   [%d/%d] subtask searches done, %d subtasks found\n", progress.Batches, progress.TotalBatches, progress.Subtasks)
+	})
+
+	tickets := hierarchy.Tickets()
+	if len(tickets) == 0 && hierarchy.Err() == nil {
 		fmt.Println("   No children found")
 		return nil
 	}
 
-	fmt.Printf("   Found %d children\n", len(children))
-
-	// Save each child
-	for i, child := range children {
-		fmt.Printf("   [%d/%d] Saving %s (%s)...\n", i+1, len(children), child.Key, child.Title)
+	fmt.Printf("   Found %d children and %d subtasks\n", len(hierarchy.Children), len(hierarchy.Subtasks))
 
+	var problems []string
+	saved := 0
+	for _, child := range tickets {
 		if err := ctx.Storage.SaveTicket(child); err != nil {
-			return fmt.Errorf("failed to save child ticket %s: %v", child.Key, err)
-		}
-
-		// If this child is a task, fetch its subtasks too
-		if child.Type == types.TicketTypeTask {
-			if err := ctx.trackTaskSubtasks(child.Key); err != nil {
-				return fmt.Errorf("failed to track subtasks of %s: %v", child.Key, err)
-			}
+			problems = append(problems, fmt.Sprintf("failed to save %s: %v", child.Key, err))
+			continue
 		}
-
-		// Small delay to be respectful to the API
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	return nil
-}
-
-// trackTaskSubtasks tracks all subtasks of a task
-func (ctx *CommandContext) trackTaskSubtasks(taskKey string) error {
-	fmt.Printf("   Fetching subtasks of task %s...\n", taskKey)
-
-	subtasks, err := ctx.TicketService.GetTaskSubtasks(context.Background(), taskKey)
-	if err != nil {
-		return fmt.Errorf("failed to fetch task subtasks: %v", err)
+		saved++
 	}
+	fmt.Printf("   Saved %d of %d\n", saved, len(tickets))
 
-	if len(subtasks) == 0 {
-		fmt.Println("      No subtasks found")
-		return nil
+	if err := hierarchy.Err(); err != nil {
+		problems = append([]string{err.Error()}, problems...)
 	}
-
-	fmt.Printf("      Found %d subtasks\n", len(subtasks))
-
-	// Save each subtask
-	for i, subtask := range subtasks {
-		fmt.Printf("      [%d/%d] Saving %s (%s)...\n", i+1, len(subtasks), subtask.Key, subtask.Title)
-
-		if err := ctx.Storage.SaveTicket(subtask); err != nil {
-			return fmt.Errorf("failed to save subtask %s: %v", subtask.Key, err)
-		}
-
-		// Small delay to be respectful to the API
-		time.Sleep(100 * time.Millisecond)
+	if len(problems) > 0 {
+		return fmt.Errorf("%s\nRun 'jit track %s' again to retry", strings.Join(problems, "\n"), ticket.Key)
 	}
-
 	return nil
 }
 
@@ -45,6 +45,7 @@ func CreateTicket(cmd *cobra.Command, options CreateOptions, flags CreateFlags)
 
 	// Open editor with template
 	editor := ui.NewEditor()
+	editor.CustomFields = ctx.TicketService.CustomFieldNames()
 	templatePath := filepath.Join("templates", options.TemplateName)
 	if err := editor.EditTemplate(templatePath, tempFile.Name()); err != nil {
 		return fmt.Errorf("failed to open editor: %v", err)
@@ -57,10 +58,11 @@ func CreateTicket(cmd *cobra.Command, options CreateOptions, flags CreateFlags)
 	}
 
 	// Parse markdown content
-	title, description, err := editor.ParseMarkdownTicket(content)
+	parsed, err := editor.ParseNewTicket(content)
 	if err != nil {
 		return fmt.Errorf("failed to parse markdown: %v", err)
 	}
+	title, description := parsed.Title, parsed.Description
 
 	// AI enrichment (if enabled and provider available)
 	if !flags.NoEnrich && ctx.AIProvider != nil {
@@ -87,17 +89,18 @@ func CreateTicket(cmd *cobra.Command, options CreateOptions, flags CreateFlags)
 		Status:      "To Do",
 		Priority:    "Medium",
 		Metadata: types.TicketMetadata{
-			Project:  ctx.Config.Jira.Project,
-			Assignee: ctx.Config.Jira.Username, // Set default assignee to current user
-			Created:  time.Now(),
-			Updated:  time.Now(),
-			Labels:   []string{},
+			Project:     ctx.Config.Jira.Project,
+			Created:     time.Now(),
+			Updated:     time.Now(),
+			Labels:      []string{},
+			FixVersions: parsed.FixVersions,
+			Components:  parsed.Components,
 		},
 		Relationships: types.TicketRelationships{
 			Children: []string{},
 		},
 		JiraData: types.JiraData{
-			CustomFields: make(map[string]interface{}),
+			CustomFields: editedCustomFields(nil, parsed.CustomFields),
 		},
 		LocalData: types.LocalData{
 			LastSync:     time.Now(),
@@ -106,8 +109,15 @@ func CreateTicket(cmd *cobra.Command, options CreateOptions, flags CreateFlags)
 		},
 	}
 
-	// Set assignee
-	ticket.Metadata.Assignee = ctx.Config.Jira.Username
+	// Assign tickets created in Jira to the current user
+	if !flags.NoCreate {
+		if me, err := ctx.TicketService.Users().Me(cmd.Context()); err == nil {
+			ticket.Metadata.Assignee = me.ID
+			ticket.Metadata.AssigneeName = me.DisplayName
+		} else {
+			PrintWarning(fmt.Sprintf("Creating unassigned: %v", err))
+		}
+	}
 
 	// Set relationships
 	if options.SetRelationships != nil {
@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/lunchboxsushi/jit/internal/ui"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editLocalFlag bool
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [ticket-key]",
+	Short: "Edit a ticket and push the changes to Jira",
+	Long: `Edit a ticket's title, description, priority, assignee, labels, fix versions
+and components in your editor.
+
+The ticket opens as the same markdown used when creating tickets, with the
+other fields in a block at the top. Only the fields you change are sent to
+Jira. If no ticket is specified, uses current focus.
+
+Changes that cannot be pushed are kept locally and marked as local changes;
+editing the ticket again pushes them along with any new edits.
+
+Examples:
+  jit edit                  # Edit current focus
+  jit edit SRE-1234         # Edit a specific ticket
+  jit edit --local          # Save the edit without pushing it`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		var ticketKey string
+		if len(args) > 0 {
+			ticketKey = strings.ToUpper(args[0])
+		} else {
+			ticketKey, err = ctx.ContextManager.GetCurrentFocus()
+			if err != nil {
+				HandleError(err, "Failed to get current focus")
+				return
+			}
+			if ticketKey == "" {
+				fmt.Println("No ticket specified and no current focus.")
+				fmt.Println("Use 'jit focus <ticket>' to set focus or specify a ticket key.")
+				return
+			}
+		}
+
+		if !ctx.Storage.Exists(ticketKey) {
+			fmt.Printf("Ticket %s not found in local storage.\n", ticketKey)
+			fmt.Printf("Use 'jit track %s' to track it first.\n", ticketKey)
+			return
+		}
+
+		ticket, err := ctx.Storage.LoadTicket(ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to load ticket")
+			return
+		}
+
+		edit, err := editTicketInEditor(ticket, ctx.TicketService.CustomFieldNames())
+		if err != nil {
+			HandleError(err, "Failed to edit ticket")
+			return
+		}
+
+		edited := *ticket
+		edited.Title = edit.Title
+		edited.Description = edit.Description
+		edited.Priority = edit.Priority
+		if err := resolveEditedAssignee(cmd.Context(), ctx, &edited, edit.Assignee); err != nil {
+			HandleError(err, "Failed to resolve assignee")
+			return
+		}
+		edited.Metadata.Labels = edit.Labels
+		if edited.Metadata.Labels == nil {
+			edited.Metadata.Labels = []string{}
+		}
+		edited.Metadata.FixVersions = edit.FixVersions
+		edited.Metadata.Components = edit.Components
+		edited.JiraData.CustomFields = editedCustomFields(ticket.JiraData.CustomFields, edit.CustomFields)
+
+		// Tickets that only exist locally have nothing to push to
+		localOnly := editLocalFlag || strings.HasPrefix(ticketKey, "LOCAL-")
+
+		changed := jira.DiffTickets(ticket, &edited)
+		if len(changed) == 0 && (localOnly || !ticket.LocalData.LocalChanges) {
+			fmt.Println("No changes.")
+			return
+		}
+		if len(changed) > 0 {
+			edited.Metadata.Updated = time.Now()
+		}
+
+		if localOnly {
+			saveEditedTicket(ctx, &edited, true)
+			PrintSuccess(fmt.Sprintf("Saved %s locally (%s)", ticketKey, strings.Join(changed, ", ")))
+			return
+		}
+
+		// Upload local images before their references reach Jira
+		if err := uploadDescriptionImages(cmd.Context(), ctx, &edited); err != nil {
+			PrintWarning(fmt.Sprintf("Failed to upload images: %v", err))
+		}
+
+		// Diff against Jira when earlier edits were never pushed
+		baseline := ticket
+		if ticket.LocalData.LocalChanges {
+			remote, err := ctx.TicketService.GetTicket(cmd.Context(), ticketKey)
+			if err != nil {
+				saveEditedTicket(ctx, &edited, true)
+				HandleError(err, "Failed to fetch ticket from Jira; changes saved locally")
+				return
+			}
+			baseline = remote
+		}
+
+		fmt.Printf("Updating %s in Jira...\n", ticketKey)
+		pushed, err := ctx.TicketService.UpdateTicket(cmd.Context(), baseline, &edited)
+		if err != nil {
+			saveEditedTicket(ctx, &edited, true)
+			HandleError(err, "Failed to update Jira; changes saved locally")
+			return
+		}
+
+		edited.LocalData.LastSync = time.Now()
+		saveEditedTicket(ctx, &edited, false)
+
+		if len(pushed) == 0 {
+			PrintSuccess(fmt.Sprintf("%s already matches Jira", ticketKey))
+			return
+		}
+		PrintSuccess(fmt.Sprintf("Updated %s in Jira (%s)", ticketKey, strings.Join(pushed, ", ")))
+	},
+}
+
+func init() {
+	editCmd.Flags().BoolVar(&editLocalFlag, "local", false, "Save changes locally without pushing them to Jira")
+}
+
+// editTicketInEditor opens the ticket in the user's editor, offering the
+// given custom fields, and parses the result
+func editTicketInEditor(ticket *types.Ticket, customFields []string) (*ui.TicketEdit, error) {
+	tempFile, err := os.CreateTemp("", "jit-edit-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	editor := ui.NewEditor()
+	editor.CustomFields = customFields
+	content, err := editor.RenderTicket(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(tempFile.Name(), []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write ticket: %v", err)
+	}
+
+	if err := editor.EditFile(tempFile.Name()); err != nil {
+		return nil, fmt.Errorf("failed to open editor: %v", err)
+	}
+
+	edited, err := editor.ReadFile(tempFile.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	return editor.ParseTicket(edited)
+}
+
+// editedCustomFields applies edited custom field values to a copy of the
+// ticket's; fields left without a value are removed
+func editedCustomFields(current, edited map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(current))
+	for name, value := range current {
+		fields[name] = value
+	}
+	for name, value := range edited {
+		if value == nil {
+			delete(fields, name)
+		} else {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// resolveEditedAssignee sets the assignee named in the editor, which may be
+// the current display name, an email or a name to look up
+func resolveEditedAssignee(cmdCtx context.Context, ctx *CommandContext, ticket *types.Ticket, who string) error {
+	switch {
+	case who == ticket.Metadata.AssigneeName || who == ticket.Metadata.Assignee:
+		return nil
+	case who == "":
+		ticket.Metadata.Assignee = ""
+		ticket.Metadata.AssigneeName = ""
+		return nil
+	}
+
+	user, err := ctx.TicketService.Users().Resolve(cmdCtx, who)
+	if err != nil {
+		return err
+	}
+	ticket.Metadata.Assignee = user.ID
+	ticket.Metadata.AssigneeName = user.DisplayName
+	return nil
+}
+
+// saveEditedTicket stores the edited ticket, recording whether it has changes Jira lacks
+func saveEditedTicket(ctx *CommandContext, ticket *types.Ticket, localChanges bool) {
+	ticket.LocalData.LocalChanges = localChanges
+	if err := ctx.Storage.SaveTicket(ticket); err != nil {
+		HandleError(err, "Failed to save ticket locally")
+	}
+}
+
+// GetEditCmd returns the edit command
+func GetEditCmd() *cobra.Command {
+	return editCmd
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// historyValueWidth truncates long values such as descriptions
+const historyValueWidth = 60
+
+var (
+	historyFieldFlag string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [ticket-key]",
+	Short: "Show a ticket's change history",
+	Long: `Show the changes made to a Jira ticket, oldest first: status
+transitions, assignee changes and edits to other fields. If no ticket is
+specified, uses current focus.
+
+Examples:
+  jit history                     # History of current focus
+  jit history SRE-1234            # History of a specific ticket
+  jit history --field status      # Only status changes`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		history, err := ctx.TicketService.GetHistory(cmd.Context(), ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to fetch history")
+			return
+		}
+
+		printed := 0
+		for _, entry := range history {
+			changes := filterFieldChanges(entry.Changes, historyFieldFlag)
+			if len(changes) == 0 {
+				continue
+			}
+
+			if printed == 0 {
+				fmt.Printf("History of %s:\n", ticketKey)
+			}
+			printed++
+
+			fmt.Printf("\n%s  %s\n", entry.Created.Local().Format("2006-01-02 15:04"), entry.Author)
+			for _, change := range changes {
+				fmt.Printf("  %-14s %s\n", strings.Title(change.Field), formatFieldChange(change))
+			}
+		}
+
+		if printed == 0 {
+			fmt.Printf("No changes to %s.\n", ticketKey)
+		}
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyFieldFlag, "field", "", "Only show changes to this field (e.g. status, assignee)")
+}
+
+// filterFieldChanges keeps the changes to field, or all changes when field is empty
+func filterFieldChanges(changes []types.FieldChange, field string) []types.FieldChange {
+	if field == "" {
+		return changes
+	}
+
+	var filtered []types.FieldChange
+	for _, change := range changes {
+		if strings.EqualFold(change.Field, field) {
+			filtered = append(filtered, change)
+		}
+	}
+	return filtered
+}
+
+// formatFieldChange renders a change as "old → new", colouring statuses
+func formatFieldChange(change types.FieldChange) string {
+	from, to := historyValue(change.From), historyValue(change.To)
+	if strings.EqualFold(change.Field, "status") {
+		if change.From != "" {
+			from = GetStatusColor(change.From).Render(from)
+		}
+		if change.To != "" {
+			to = GetStatusColor(change.To).Render(to)
+		}
+	}
+	return fmt.Sprintf("%s → %s", from, to)
+}
+
+// historyValue renders a changed value on one line, truncating long ones
+func historyValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "(none)"
+	}
+	if runes := []rune(value); len(runes) > historyValueWidth {
+		return string(runes[:historyValueWidth-1]) + "…"
+	}
+	return value
+}
+
+// GetHistoryCmd returns the history command
+func GetHistoryCmd() *cobra.Command {
+	return historyCmd
+}
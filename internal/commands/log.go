@@ -1,10 +1,12 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/lunchboxsushi/jit/internal/storage"
 	"github.com/lunchboxsushi/jit/pkg/types"
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,8 @@ var (
 	logStatusFlag string
 	logJSONFlag   bool
 	logOrphanFlag bool
+	logSprintFlag string
+	logFieldsFlag []string
 )
 
 var logCmd = &cobra.Command{
@@ -25,15 +29,37 @@ Examples:
   jit log                    # Show current context tree
   jit log --all             # Show all tracked tickets
   jit log --status "In Progress"  # Filter by status
+  jit log --sprint current  # Filter by the active sprint
+  jit log --fields story_points,team  # Show custom fields
   jit log --json            # Output as JSON`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// For testing, use dummy data
-		tickets := GenerateTestTickets()
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
 
-		// Set current focus for testing (only one should be active)
-		currentEpic := ""
-		currentTask := ""
-		currentSubtask := "PROJ-103"
+		tickets, err := loadTrackedTickets(ctx.Storage)
+		if err != nil {
+			HandleError(err, "Failed to load tickets")
+			return
+		}
+
+		focus, err := ctx.ContextManager.GetCurrentContext()
+		if err != nil {
+			HandleError(err, "Failed to load context")
+			return
+		}
+		currentEpic := focus.CurrentEpic
+		currentTask := focus.CurrentTask
+		currentSubtask := focus.CurrentSubtask
+
+		// Fields are stored under their configured names
+		for _, name := range logFieldsFlag {
+			if _, ok := ctx.Config.Jira.CustomFields[name]; !ok {
+				PrintWarning(fmt.Sprintf("%s is not configured under jira.custom_fields", name))
+			}
+		}
 
 		if len(tickets) == 0 {
 			if logJSONFlag {
@@ -49,6 +75,11 @@ Examples:
 			tickets = filterTicketsByStatus(tickets, logStatusFlag)
 		}
 
+		// Filter by sprint if specified
+		if logSprintFlag != "" {
+			tickets = filterTicketsBySprint(tickets, logSprintFlag)
+		}
+
 		// Sort tickets by type and key
 		sortTickets(tickets)
 
@@ -66,6 +97,27 @@ func init() {
 	logCmd.Flags().StringVar(&logStatusFlag, "status", "", "Filter by status (e.g., 'In Progress', 'Done')")
 	logCmd.Flags().BoolVar(&logJSONFlag, "json", false, "Output as JSON")
 	logCmd.Flags().BoolVar(&logOrphanFlag, "orphan", false, "Show orphaned tasks")
+	logCmd.Flags().StringVar(&logSprintFlag, "sprint", "", "Filter by sprint ID, name or 'current'")
+	logCmd.Flags().StringSliceVar(&logFieldsFlag, "fields", nil, "Custom fields to show, by configured name")
+}
+
+// loadTrackedTickets loads every ticket in storage, skipping any that
+// cannot be read
+func loadTrackedTickets(store storage.Storage) ([]*types.Ticket, error) {
+	keys, err := store.ListTickets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tickets: %v", err)
+	}
+
+	var tickets []*types.Ticket
+	for _, key := range keys {
+		ticket, err := store.LoadTicket(key)
+		if err != nil {
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
 }
 
 // filterTicketsByStatus filters tickets by status
@@ -79,6 +131,33 @@ func filterTicketsByStatus(tickets []*types.Ticket, status string) []*types.Tick
 	return filtered
 }
 
+// filterTicketsBySprint keeps the tickets recorded in the sprint selected by
+// query, along with their parents so the tree stays connected
+func filterTicketsBySprint(tickets []*types.Ticket, query string) []*types.Ticket {
+	byKey := make(map[string]*types.Ticket, len(tickets))
+	for _, ticket := range tickets {
+		byKey[ticket.Key] = ticket
+	}
+
+	keep := make(map[string]bool)
+	for _, ticket := range tickets {
+		if ticket.Metadata.Sprint == nil || !ticket.Metadata.Sprint.Matches(query) {
+			continue
+		}
+		for t := ticket; t != nil && !keep[t.Key]; t = byKey[t.Relationships.ParentKey] {
+			keep[t.Key] = true
+		}
+	}
+
+	var filtered []*types.Ticket
+	for _, ticket := range tickets {
+		if keep[ticket.Key] {
+			filtered = append(filtered, ticket)
+		}
+	}
+	return filtered
+}
+
 // sortTickets sorts tickets by type (epic, task, subtask) and then by key
 func sortTickets(tickets []*types.Ticket) {
 	sort.Slice(tickets, func(i, j int) bool {
@@ -247,9 +326,59 @@ func formatTicketText(ticket *types.Ticket, isFocused bool) string {
 	parts = append(parts, "-")
 	parts = append(parts, ticket.Title)
 
+	// Unfinished blockers
+	if blockers := formatBlockers(ticket); blockers != "" {
+		parts = append(parts, blockers)
+	}
+
+	// Requested custom fields
+	if fields := formatCustomFields(ticket, logFieldsFlag); fields != "" {
+		parts = append(parts, fields)
+	}
+
 	return strings.Join(parts, " ")
 }
 
+// formatCustomFields renders the named custom fields that have values on a ticket
+func formatCustomFields(ticket *types.Ticket, names []string) string {
+	var fields []string
+	for _, name := range names {
+		if value := formatCustomFieldValue(ticket.JiraData.CustomFields[name]); value != "" {
+			fields = append(fields, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return TreeColor.Render(strings.Join(fields, " "))
+}
+
+// formatCustomFieldValue renders a custom field value on one line
+func formatCustomFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, formatCustomFieldValue(item))
+		}
+		return strings.Join(items, ",")
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	}
+	return fmt.Sprint(value)
+}
+
+// formatBlockers describes the unfinished tickets blocking a ticket
+func formatBlockers(ticket *types.Ticket) string {
+	blockers := ticket.BlockedBy()
+	if len(blockers) == 0 {
+		return ""
+	}
+	return StatusBlocked.Render(fmt.Sprintf("⊘ blocked by %s", strings.Join(blockers, ", ")))
+}
+
 // displayJSON displays tickets in JSON format
 func displayJSON(tickets []*types.Ticket, currentEpic, currentTask, currentSubtask string) {
 	fmt.Println("{")
@@ -272,6 +401,20 @@ func displayJSON(tickets []*types.Ticket, currentEpic, currentTask, currentSubta
 			fmt.Printf(",\n      \"parent_key\": \"%s\"", ticket.Relationships.ParentKey)
 		}
 
+		if blockers := ticket.BlockedBy(); len(blockers) > 0 {
+			fmt.Printf(",\n      \"blocked_by\": [\"%s\"]", strings.Join(blockers, "\", \""))
+		}
+
+		if sprint := ticket.Metadata.Sprint; sprint != nil {
+			fmt.Printf(",\n      \"sprint\": \"%s\"", sprint.Name)
+		}
+
+		if len(ticket.JiraData.CustomFields) > 0 {
+			if fields, err := json.Marshal(ticket.JiraData.CustomFields); err == nil {
+				fmt.Printf(",\n      \"custom_fields\": %s", fields)
+			}
+		}
+
 		if i < len(tickets)-1 {
 			fmt.Printf("\n    },\n")
 		} else {
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/internal/storage"
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newLogStorage stores tickets in a temporary data directory and returns them
+// as log loads them
+func newLogStorage(t *testing.T, tickets ...*types.Ticket) []*types.Ticket {
+	store, err := storage.NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	for _, ticket := range tickets {
+		if err := store.SaveTicket(ticket); err != nil {
+			t.Fatalf("Failed to save %s: %v", ticket.Key, err)
+		}
+	}
+
+	loaded, err := loadTrackedTickets(store)
+	if err != nil {
+		t.Fatalf("Failed to load tickets: %v", err)
+	}
+	if len(loaded) != len(tickets) {
+		t.Fatalf("Expected %d stored tickets, loaded %d", len(tickets), len(loaded))
+	}
+	sortTickets(loaded)
+	return loaded
+}
+
+// newLogTicket creates a ticket under parent, which may be empty
+func newLogTicket(key, ticketType, parent string) *types.Ticket {
+	ticket := types.NewTicket(key, key+" title", ticketType)
+	ticket.Relationships.ParentKey = parent
+	return ticket
+}
+
+func ticketKeys(tickets []*types.Ticket) string {
+	var keys []string
+	for _, ticket := range tickets {
+		keys = append(keys, ticket.Key)
+	}
+	return strings.Join(keys, ",")
+}
+
+func TestFilterStoredTicketsBySprint(t *testing.T) {
+	active := &types.Sprint{ID: 7, Name: "Sprint 7", State: types.SprintActive}
+	future := &types.Sprint{ID: 8, Name: "Sprint 8", State: types.SprintFuture}
+
+	epic := newLogTicket("OPS-1", types.TicketTypeEpic, "")
+	task := newLogTicket("OPS-2", types.TicketTypeTask, "OPS-1")
+	subtask := newLogTicket("OPS-3", types.TicketTypeSubtask, "OPS-2")
+	subtask.Metadata.Sprint = active
+	other := newLogTicket("OPS-4", types.TicketTypeTask, "OPS-1")
+	other.Metadata.Sprint = future
+	unplanned := newLogTicket("OPS-5", types.TicketTypeTask, "")
+
+	tickets := newLogStorage(t, epic, task, subtask, other, unplanned)
+
+	tests := map[string]string{
+		"current":  "OPS-1,OPS-2,OPS-3",
+		"Sprint 8": "OPS-1,OPS-4",
+		"8":        "OPS-1,OPS-4",
+		"Sprint 9": "",
+	}
+	for query, want := range tests {
+		if got := ticketKeys(filterTicketsBySprint(tickets, query)); got != want {
+			t.Errorf("--sprint %q: expected %q, got %q", query, want, got)
+		}
+	}
+}
+
+func TestFormatBlockersOnStoredTickets(t *testing.T) {
+	blocked := newLogTicket("OPS-1", types.TicketTypeTask, "")
+	blocked.Relationships.Links = []types.TicketLink{
+		{Type: "Blocks", Direction: types.LinkInward, Relation: "is blocked by", Key: "OPS-2", Status: "In Progress"},
+		{Type: "Blocks", Direction: types.LinkInward, Relation: "is blocked by", Key: "OPS-3", Status: "Done", Done: true},
+		{Type: "Blocks", Direction: types.LinkOutward, Relation: "blocks", Key: "OPS-4"},
+	}
+	free := newLogTicket("OPS-5", types.TicketTypeTask, "")
+
+	tickets := newLogStorage(t, blocked, free)
+
+	if got := formatTicketText(tickets[0], false); !strings.Contains(got, "blocked by OPS-2") || strings.Contains(got, "OPS-3") {
+		t.Errorf("Expected %s to show only its unfinished blocker, got %q", tickets[0].Key, got)
+	}
+	if got := formatBlockers(tickets[1]); got != "" {
+		t.Errorf("Expected no blockers on %s, got %q", tickets[1].Key, got)
+	}
+}
+
+func TestFormatCustomFieldsOnStoredTickets(t *testing.T) {
+	estimated := newLogTicket("OPS-1", types.TicketTypeTask, "")
+	estimated.JiraData.CustomFields = map[string]interface{}{
+		"story_points": 5,
+		"team":         "Identity",
+		"components":   []interface{}{"api", "web"},
+		"notes":        "two\n  lines",
+	}
+	plain := newLogTicket("OPS-2", types.TicketTypeTask, "")
+
+	tickets := newLogStorage(t, estimated, plain)
+
+	got := formatCustomFields(tickets[0], []string{"story_points", "components", "notes", "missing"})
+	for _, want := range []string{"story_points=5", "components=api,web", "notes=two lines"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "missing") || strings.Contains(got, "team") {
+		t.Errorf("Expected only requested fields with values, got %q", got)
+	}
+	if got := formatCustomFields(tickets[1], []string{"story_points"}); got != "" {
+		t.Errorf("Expected nothing for a ticket without fields, got %q", got)
+	}
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Inspect cached Jira project metadata",
+	Long: `Show the issue types, priorities and fields jit has discovered for a project.
+
+Metadata is fetched from Jira on first use and cached locally. Use
+'jit meta refresh' after changing issue types or priorities in Jira.
+
+Examples:
+  jit meta                  # Show metadata for the configured project
+  jit meta show PROJ        # Show metadata for another project
+  jit meta refresh          # Rebuild the cache for the configured project`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runMetaShow(args)
+	},
+}
+
+var metaShowCmd = &cobra.Command{
+	Use:   "show [project]",
+	Short: "Show cached metadata for a project",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runMetaShow(args)
+	},
+}
+
+var metaRefreshCmd = &cobra.Command{
+	Use:   "refresh [project]",
+	Short: "Rebuild the metadata cache from Jira",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		project := metaProject(ctx, args)
+		fmt.Printf("Refreshing metadata for %s...\n", project)
+
+		meta, err := ctx.TicketService.Metadata().Refresh(context.Background(), project)
+		if err != nil {
+			HandleError(err, "Failed to refresh metadata")
+			return
+		}
+
+		PrintSuccess(fmt.Sprintf("Cached %d issue types, %d priorities and %d fields for %s",
+			len(meta.IssueTypes), len(meta.Priorities), len(meta.Fields), project))
+	},
+}
+
+func init() {
+	metaCmd.AddCommand(metaShowCmd)
+	metaCmd.AddCommand(metaRefreshCmd)
+}
+
+// runMetaShow prints the metadata for the requested or configured project
+func runMetaShow(args []string) {
+	ctx, err := InitializeCommand()
+	if err != nil {
+		HandleError(err, "Failed to initialize")
+		return
+	}
+
+	project := metaProject(ctx, args)
+	meta, err := ctx.TicketService.Metadata().Project(context.Background(), project)
+	if err != nil {
+		HandleError(err, "Failed to load metadata")
+		return
+	}
+
+	printProjectMetadata(meta, ctx.Config.Jira.EpicLinkField)
+	printCustomFieldMapping(meta, ctx.Config.Jira.CustomFields)
+}
+
+// metaProject returns the project named in args or the configured default
+func metaProject(ctx *CommandContext, args []string) string {
+	if len(args) > 0 {
+		return strings.ToUpper(args[0])
+	}
+	return ctx.Config.Jira.Project
+}
+
+// printProjectMetadata prints a summary of project metadata
+func printProjectMetadata(meta *jira.ProjectMetadata, epicLinkField string) {
+	fmt.Printf("Project %s (fetched %s)\n", meta.Project, meta.FetchedAt.Format("2006-01-02 15:04"))
+
+	style := "company-managed"
+	if meta.IsTeamManaged() {
+		style = "team-managed"
+	}
+	fmt.Printf("Style: %s, epics linked through %s\n", style, meta.EpicParentField(epicLinkField))
+
+	fmt.Println("\nIssue types:")
+	for _, issueType := range meta.IssueTypes {
+		suffix := ""
+		if issueType.Subtask {
+			suffix = " (subtask)"
+		}
+		fmt.Printf("  %-8s %s%s\n", issueType.ID, issueType.Name, suffix)
+	}
+
+	fmt.Println("\nPriorities:")
+	for _, priority := range meta.Priorities {
+		fmt.Printf("  %-8s %s\n", priority.ID, priority.Name)
+	}
+
+	customFields := 0
+	for _, field := range meta.Fields {
+		if field.Custom {
+			customFields++
+		}
+	}
+	fmt.Printf("\nFields: %d (%d custom)\n", len(meta.Fields), customFields)
+}
+
+// printCustomFieldMapping shows which field each configured custom field
+// name maps to, and its type
+func printCustomFieldMapping(meta *jira.ProjectMetadata, mapping map[string]string) {
+	if len(mapping) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(mapping))
+	for name := range mapping {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nCustom fields:")
+	for _, name := range names {
+		field, ok := meta.Field(mapping[name])
+		if !ok {
+			fmt.Printf("  %-16s %s (not found)\n", name, mapping[name])
+			continue
+		}
+
+		fieldType := "unknown"
+		if field.Schema != nil {
+			fieldType = field.Schema.Type
+			if field.Schema.Items != "" {
+				fieldType += " of " + field.Schema.Items
+			}
+		}
+		fmt.Printf("  %-16s %-20s %s (%s)\n", name, field.ID, field.Name, fieldType)
+	}
+}
+
+// GetMetaCmd returns the meta command
+func GetMetaCmd() *cobra.Command {
+	return metaCmd
+}
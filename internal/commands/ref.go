@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/internal/utils"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	refTicketFlag string
+	refTitleFlag  string
+)
+
+var refCmd = &cobra.Command{
+	Use:   "ref",
+	Short: "Link tickets to pull requests and URLs",
+	Long: `Attach pull requests, branches and other URLs to a ticket as Jira remote
+links, so the ticket points back to the code. Links are added to the current
+focus unless --ticket names another ticket. Adding a URL that is already
+linked updates its title instead of adding it twice.
+
+Examples:
+  jit ref add https://github.com/acme/api/pull/42   # Link a URL to current focus
+  jit ref add https://docs.acme.dev/mfa --title "MFA design"
+  jit ref pr                                        # Link this branch's PR
+  jit ref ls --ticket SRE-1234                      # List a ticket's links`,
+}
+
+var refAddCmd = &cobra.Command{
+	Use:   "add <url>",
+	Short: "Link a URL to a ticket",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := refTicketKey(ctx)
+		if !ok {
+			return
+		}
+
+		link := &types.RemoteLink{TicketKey: ticketKey, URL: args[0], Title: refTitleFlag}
+		if err := validateRefURL(link.URL); err != nil {
+			HandleError(err, "Invalid URL")
+			return
+		}
+
+		addRef(cmd, ctx, link)
+	},
+}
+
+var refPRCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Link the current branch's pull request to a ticket",
+	Long: `Link the pull request of the current git branch to a ticket. The
+repository is found from the branch's upstream remote, or origin. On GitHub,
+the pull request is looked up with the gh CLI when it is installed; otherwise,
+or when the branch has no pull request yet, the branch itself is linked.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := refTicketKey(ctx)
+		if !ok {
+			return
+		}
+
+		repo, err := utils.CurrentGitRepo()
+		if err != nil {
+			HandleError(err, "Failed to read git repository")
+			return
+		}
+
+		link := branchRef(repo)
+		if pr, ok := githubPullRequest(repo); ok {
+			link = pr
+		}
+		link.TicketKey = ticketKey
+		if refTitleFlag != "" {
+			link.Title = refTitleFlag
+		}
+
+		addRef(cmd, ctx, link)
+	},
+}
+
+var refListCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List a ticket's links",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := refTicketKey(ctx)
+		if !ok {
+			return
+		}
+
+		links, err := ctx.TicketService.RemoteLinks(cmd.Context(), ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to fetch links")
+			return
+		}
+
+		if len(links) == 0 {
+			fmt.Printf("%s has no links. Use 'jit ref add <url>' to add one.\n", ticketKey)
+			return
+		}
+
+		fmt.Printf("Links of %s:\n", ticketKey)
+		for _, link := range links {
+			title := link.Title
+			if link.Relationship != "" {
+				title = fmt.Sprintf("%s (%s)", title, link.Relationship)
+			}
+			fmt.Printf("  %-8s %s\n", link.ID, title)
+			if link.URL != link.Title {
+				fmt.Printf("  %-8s %s\n", "", link.URL)
+			}
+		}
+	},
+}
+
+func init() {
+	refCmd.PersistentFlags().StringVarP(&refTicketFlag, "ticket", "t", "", "Ticket key (defaults to current focus)")
+	refAddCmd.Flags().StringVar(&refTitleFlag, "title", "", "Link title (defaults to the URL)")
+	refPRCmd.Flags().StringVar(&refTitleFlag, "title", "", "Link title (defaults to the pull request or branch name)")
+
+	refCmd.AddCommand(refAddCmd)
+	refCmd.AddCommand(refPRCmd)
+	refCmd.AddCommand(refListCmd)
+}
+
+// refTicketKey returns the ticket named by --ticket, or the current focus
+func refTicketKey(ctx *CommandContext) (string, bool) {
+	if refTicketFlag != "" {
+		return ticketKeyOrFocus(ctx, []string{refTicketFlag})
+	}
+	return ticketKeyOrFocus(ctx, nil)
+}
+
+// addRef adds a remote link and reports it
+func addRef(cmd *cobra.Command, ctx *CommandContext, link *types.RemoteLink) {
+	added, err := ctx.TicketService.AddRemoteLink(cmd.Context(), link)
+	if err != nil {
+		HandleError(err, "Failed to add link")
+		return
+	}
+	PrintSuccess(fmt.Sprintf("Linked %s to %s", added.TicketKey, added.Title))
+	if added.Title != added.URL {
+		fmt.Printf("  %s\n", added.URL)
+	}
+}
+
+// validateRefURL checks that a link target is an absolute web address
+func validateRefURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", raw)
+	}
+	return nil
+}
+
+// branchRef links the repository's current branch
+func branchRef(repo *utils.GitRepo) *types.RemoteLink {
+	return &types.RemoteLink{
+		URL:          repo.BranchURL(),
+		Title:        fmt.Sprintf("Branch %s in %s", repo.Branch, repo.Path),
+		Relationship: "branch",
+	}
+}
+
+// githubPullRequest asks the gh CLI for the current branch's pull request.
+// It reports false when the repository is not on GitHub, gh is missing, or
+// the branch has no pull request.
+func githubPullRequest(repo *utils.GitRepo) (*types.RemoteLink, bool) {
+	if !repo.IsGitHub() {
+		return nil, false
+	}
+	if _, err := exec.LookPath("gh"); err != nil {
+		return nil, false
+	}
+
+	out, err := exec.Command("gh", "pr", "view", "--json", "number,title,url").Output()
+	if err != nil {
+		return nil, false
+	}
+
+	var pr struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		URL    string `json:"url"`
+	}
+	if err := json.Unmarshal(out, &pr); err != nil || pr.URL == "" {
+		return nil, false
+	}
+
+	return &types.RemoteLink{
+		URL:          pr.URL,
+		Title:        strings.TrimSpace(fmt.Sprintf("PR #%d: %s", pr.Number, pr.Title)),
+		Relationship: "pull request",
+	}, true
+}
+
+// GetRefCmd returns the ref command
+func GetRefCmd() *cobra.Command {
+	return refCmd
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	relateRemoveFlag bool
+)
+
+var relateCmd = &cobra.Command{
+	Use:   "relate [ticket] [relation ticket]",
+	Short: "Link tickets (blocks, relates to, duplicates)",
+	Long: `Create, remove or list issue links between tickets.
+
+The relation is a phrase of one of the site's link types, read from the
+first ticket to the second: "blocks", "is blocked by", "relates to",
+"duplicates", "clones" and so on. Hyphens may replace spaces. When the first
+ticket is left out, the current focus is used.
+
+Examples:
+  jit relate                          # List links of current focus
+  jit relate SRE-1                    # List links of SRE-1
+  jit relate SRE-1 blocks SRE-2       # SRE-1 blocks SRE-2
+  jit relate is-blocked-by SRE-3      # Current focus is blocked by SRE-3
+  jit relate SRE-1 relates SRE-4      # Relate two tickets
+  jit relate --remove SRE-1 blocks SRE-2`,
+	Args: cobra.MaximumNArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		// Default the source ticket to the current focus
+		if len(args) == 0 || len(args) == 2 {
+			focus, err := ctx.ContextManager.GetCurrentFocus()
+			if err != nil {
+				HandleError(err, "Failed to get current focus")
+				return
+			}
+			if focus == "" {
+				fmt.Println("No ticket specified and no current focus.")
+				fmt.Println("Use 'jit focus <ticket>' to set focus or specify a ticket key.")
+				return
+			}
+			args = append([]string{focus}, args...)
+		}
+
+		from := strings.ToUpper(args[0])
+		if len(args) == 1 {
+			showLinks(cmd.Context(), ctx, from)
+			return
+		}
+
+		relation, to := args[1], strings.ToUpper(args[2])
+		if relateRemoveFlag {
+			removed, err := ctx.TicketService.UnlinkTickets(cmd.Context(), from, relation, to)
+			if err != nil {
+				HandleError(err, "Failed to remove link")
+				return
+			}
+			PrintSuccess(fmt.Sprintf("Removed %d link(s) between %s and %s", removed, from, to))
+		} else {
+			phrase, err := ctx.TicketService.LinkTickets(cmd.Context(), from, relation, to)
+			if err != nil {
+				HandleError(err, "Failed to link tickets")
+				return
+			}
+			PrintSuccess(fmt.Sprintf("%s %s %s", from, phrase, to))
+		}
+
+		// Keep the links of locally tracked tickets current
+		for _, key := range []string{from, to} {
+			if err := refreshLocalLinks(cmd.Context(), ctx, key); err != nil {
+				PrintWarning(fmt.Sprintf("Failed to refresh links of %s: %v", key, err))
+			}
+		}
+	},
+}
+
+func init() {
+	relateCmd.Flags().BoolVarP(&relateRemoveFlag, "remove", "d", false, "Remove the link instead of creating it")
+}
+
+// showLinks prints a ticket's links as stored in Jira
+func showLinks(cmdCtx context.Context, ctx *CommandContext, key string) {
+	ticket, err := ctx.TicketService.GetTicket(cmdCtx, key)
+	if err != nil {
+		HandleError(err, "Failed to fetch ticket")
+		return
+	}
+
+	if len(ticket.Relationships.Links) == 0 {
+		fmt.Printf("%s has no links.\n", key)
+		return
+	}
+
+	fmt.Printf("Links of %s:\n", key)
+	for _, link := range ticket.Relationships.Links {
+		status := GetStatusColor(link.Status).Render(fmt.Sprintf("<%s>", link.Status))
+		fmt.Printf("  %-16s %s %s %s\n", link.Relation, link.Key, status, link.Title)
+	}
+}
+
+// refreshLocalLinks updates the stored links of a ticket if it is tracked
+func refreshLocalLinks(cmdCtx context.Context, ctx *CommandContext, key string) error {
+	if !ctx.Storage.Exists(key) {
+		return nil
+	}
+
+	local, err := ctx.Storage.LoadTicket(key)
+	if err != nil {
+		return err
+	}
+
+	remote, err := ctx.TicketService.GetTicket(cmdCtx, key)
+	if err != nil {
+		return err
+	}
+
+	local.Relationships.Links = remote.Relationships.Links
+	return ctx.Storage.SaveTicket(local)
+}
+
+// GetRelateCmd returns the relate command
+func GetRelateCmd() *cobra.Command {
+	return relateCmd
+}
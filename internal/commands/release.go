@@ -0,0 +1,406 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var releaseAllFlag bool
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "See what ships in the project's versions",
+	Long: `List the project's versions, show the tickets fixed in one, and write
+release notes for it.
+
+Versions may be given by name or ID. Tickets are put in a version with the
+fix_versions key in the editor front matter of 'jit edit' or when creating
+them. Release notes are written from tracked tickets, grouped by epic.
+
+Examples:
+  jit release list                     # List unarchived versions
+  jit release show 1.4.0               # List the tickets fixed in 1.4.0
+  jit release notes 1.4.0 > NOTES.md   # Write markdown release notes`,
+}
+
+var releaseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the project's versions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		versions, err := ctx.TicketService.Versions(cmd.Context(), "")
+		if err != nil {
+			HandleError(err, "Failed to fetch versions")
+			return
+		}
+
+		shown := 0
+		for _, version := range versions {
+			if version.Archived && !releaseAllFlag {
+				continue
+			}
+			if shown == 0 {
+				fmt.Printf("Versions of %s:\n", ctx.Config.Jira.Project)
+			}
+			shown++
+
+			marker := " "
+			if version.Released {
+				marker = "✓"
+			}
+			fmt.Printf("  %s %-20s %-10s %s\n", marker, version.Name, versionState(&version), version.ReleaseDate)
+		}
+
+		if shown == 0 {
+			fmt.Printf("%s has no versions.\n", ctx.Config.Jira.Project)
+		}
+	},
+}
+
+var releaseShowCmd = &cobra.Command{
+	Use:   "show <version>",
+	Short: "List the tickets fixed in a version",
+	Long: `List the tickets fixed in a version. Tracked tickets are marked and
+refreshed locally, except those with unpushed local edits.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		version, err := ctx.TicketService.FindVersion(cmd.Context(), "", args[0])
+		if err != nil {
+			HandleError(err, "Failed to find version")
+			return
+		}
+
+		tickets, err := loadVersionTickets(cmd.Context(), ctx, version)
+		if err != nil {
+			HandleError(err, "Failed to fetch version tickets")
+			return
+		}
+
+		printVersionHeader(version)
+		if len(tickets) == 0 {
+			fmt.Println("\nNo tickets in this version.")
+			return
+		}
+
+		fmt.Println()
+		for _, ticket := range tickets {
+			marker := " "
+			if ctx.Storage.Exists(ticket.Key) {
+				marker = "*"
+			}
+			fmt.Printf("  %s %-12s %-8s %-14s %s\n", marker, ticket.Key, ticket.Type, ticket.Status, ticket.Title)
+		}
+		fmt.Println("\n* tracked locally")
+	},
+}
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes <version>",
+	Short: "Write markdown release notes for a version",
+	Long: `Write markdown release notes for a version to stdout, from the tracked
+tickets fixed in it, grouped by their epic. The version's tickets are read
+from Jira, or from the local copies when Jira cannot be reached; tickets
+with unpushed local edits are shown as edited. Local copies are not
+changed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		var tickets []*types.Ticket
+		version, err := ctx.TicketService.FindVersion(cmd.Context(), "", args[0])
+		if err == nil {
+			var fetched []*types.Ticket
+			if fetched, err = ctx.TicketService.VersionTickets("", version.Name).Collect(cmd.Context()); err == nil {
+				tickets = trackedCopies(ctx, version.Name, fetched)
+			}
+		} else {
+			version = &types.Version{Name: args[0]}
+		}
+
+		if err != nil {
+			PrintWarning(fmt.Sprintf("Using local tickets only: %v", err))
+			if tickets, err = trackedVersionTickets(ctx, version.Name); err != nil {
+				HandleError(err, "Failed to load tracked tickets")
+				return
+			}
+		}
+
+		fmt.Print(renderReleaseNotes(ctx, version, tickets))
+	},
+}
+
+func init() {
+	releaseListCmd.Flags().BoolVar(&releaseAllFlag, "all", false, "Include archived versions")
+
+	releaseCmd.AddCommand(releaseListCmd)
+	releaseCmd.AddCommand(releaseShowCmd)
+	releaseCmd.AddCommand(releaseNotesCmd)
+}
+
+// loadVersionTickets fetches the tickets fixed in a version and records the
+// version on tracked tickets
+func loadVersionTickets(cmdCtx context.Context, ctx *CommandContext, version *types.Version) ([]*types.Ticket, error) {
+	tickets, err := ctx.TicketService.VersionTickets("", version.Name).Collect(cmdCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordVersion(ctx, version, tickets); err != nil {
+		PrintWarning(fmt.Sprintf("Failed to record version on tracked tickets: %v", err))
+	}
+	return tickets, nil
+}
+
+// recordVersion refreshes tracked tickets that are in a version and removes
+// the version from tracked tickets that have left it. Local state is kept,
+// and tickets with unpushed edits are left alone so the edits are not lost.
+func recordVersion(ctx *CommandContext, version *types.Version, tickets []*types.Ticket) error {
+	inVersion := make(map[string]bool, len(tickets))
+	for _, ticket := range tickets {
+		inVersion[ticket.Key] = true
+		if !ctx.Storage.Exists(ticket.Key) {
+			continue
+		}
+		err := ctx.Storage.UpdateTicket(ticket.Key, func(current *types.Ticket) (*types.Ticket, error) {
+			if current == nil || current.LocalData.LocalChanges {
+				return nil, nil
+			}
+			ticket.LocalData = current.LocalData
+			ticket.LocalData.LastSync = time.Now()
+			return ticket, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	keys, err := ctx.Storage.ListTickets()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if inVersion[key] {
+			continue
+		}
+		err := ctx.Storage.UpdateTicket(key, func(current *types.Ticket) (*types.Ticket, error) {
+			if current == nil || current.LocalData.LocalChanges || !current.HasFixVersion(version.Name) {
+				return nil, nil
+			}
+
+			var kept []string
+			for _, name := range current.Metadata.FixVersions {
+				if !strings.EqualFold(name, version.Name) {
+					kept = append(kept, name)
+				}
+			}
+			current.Metadata.FixVersions = kept
+			return current, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trackedCopies picks the tracked tickets among those Jira lists in a
+// version, using the local copy of tickets with unpushed edits, without
+// writing anything
+func trackedCopies(ctx *CommandContext, name string, fetched []*types.Ticket) []*types.Ticket {
+	var tickets []*types.Ticket
+	for _, ticket := range fetched {
+		if !ctx.Storage.Exists(ticket.Key) {
+			continue
+		}
+		if local, err := ctx.Storage.LoadTicket(ticket.Key); err == nil && local.LocalData.LocalChanges {
+			if local.HasFixVersion(name) {
+				tickets = append(tickets, local)
+			}
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets
+}
+
+// trackedVersionTickets loads the tracked tickets fixed in the named version
+func trackedVersionTickets(ctx *CommandContext, name string) ([]*types.Ticket, error) {
+	keys, err := ctx.Storage.ListTickets()
+	if err != nil {
+		return nil, err
+	}
+
+	var tickets []*types.Ticket
+	for _, key := range keys {
+		ticket, err := ctx.Storage.LoadTicket(key)
+		if err != nil {
+			continue
+		}
+		if ticket.HasFixVersion(name) {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// renderReleaseNotes writes markdown release notes for tickets, a section
+// per epic in key order and the tickets outside any epic last
+func renderReleaseNotes(ctx *CommandContext, version *types.Version, tickets []*types.Ticket) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Release %s\n", version.Name)
+	switch {
+	case version.ReleaseDate == "":
+	case version.Released:
+		fmt.Fprintf(&b, "\nReleased %s\n", version.ReleaseDate)
+	default:
+		fmt.Fprintf(&b, "\nPlanned for %s\n", version.ReleaseDate)
+	}
+	if description := strings.TrimSpace(version.Description); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+
+	if len(tickets) == 0 {
+		b.WriteString("\nNo tracked tickets are fixed in this version.\n")
+		return b.String()
+	}
+
+	// Group tickets under the epic they roll up to
+	groups := make(map[string][]*types.Ticket)
+	epics := make(map[string]*types.Ticket)
+	for _, ticket := range tickets {
+		if ticket.IsEpic() {
+			epics[ticket.Key] = ticket
+			if _, ok := groups[ticket.Key]; !ok {
+				groups[ticket.Key] = nil
+			}
+			continue
+		}
+
+		epic := findEpic(ctx, ticket)
+		key := ""
+		if epic != nil {
+			key = epic.Key
+			epics[key] = epic
+		}
+		groups[key] = append(groups[key], ticket)
+	}
+
+	var epicKeys []string
+	for key := range groups {
+		if key != "" {
+			epicKeys = append(epicKeys, key)
+		}
+	}
+	sort.Strings(epicKeys)
+
+	for _, key := range epicKeys {
+		epic := epics[key]
+		fmt.Fprintf(&b, "\n## %s (%s)\n\n", epic.Title, epic.Key)
+		writeReleaseNoteItems(&b, epic, groups[key])
+	}
+	if others := groups[""]; len(others) > 0 {
+		b.WriteString("\n## Other changes\n\n")
+		writeReleaseNoteItems(&b, nil, others)
+	}
+
+	return b.String()
+}
+
+// writeReleaseNoteItems writes a section's tickets as a markdown list. An
+// epic fixed in the version with no tickets of its own is listed by itself.
+func writeReleaseNoteItems(b *strings.Builder, epic *types.Ticket, tickets []*types.Ticket) {
+	if len(tickets) == 0 && epic != nil {
+		tickets = []*types.Ticket{epic}
+	}
+
+	sortTickets(tickets)
+	for _, ticket := range tickets {
+		fmt.Fprintf(b, "- %s (%s)\n", ticket.Title, ticketReference(ticket))
+	}
+}
+
+// ticketReference renders a ticket key as a markdown link when its URL is known
+func ticketReference(ticket *types.Ticket) string {
+	if ticket.JiraData.URL == "" {
+		return ticket.Key
+	}
+	return fmt.Sprintf("[%s](%s)", ticket.Key, ticket.JiraData.URL)
+}
+
+// findEpic walks a ticket's tracked parents up to its epic, if it has one
+func findEpic(ctx *CommandContext, ticket *types.Ticket) *types.Ticket {
+	seen := map[string]bool{ticket.Key: true}
+	for key := ticket.Relationships.ParentKey; key != "" && !seen[key]; {
+		seen[key] = true
+		parent, err := ctx.Storage.LoadTicket(key)
+		if err != nil {
+			return nil
+		}
+		if parent.IsEpic() {
+			return parent
+		}
+		key = parent.Relationships.ParentKey
+	}
+	return nil
+}
+
+// printVersionHeader prints a version's name, state, dates and description
+func printVersionHeader(version *types.Version) {
+	fmt.Printf("%s (%s)\n", version.Name, versionState(version))
+	if version.StartDate != "" || version.ReleaseDate != "" {
+		fmt.Printf("Dates:       %s → %s\n", orDash(version.StartDate), orDash(version.ReleaseDate))
+	}
+	if description := strings.TrimSpace(version.Description); description != "" {
+		fmt.Printf("Description: %s\n", description)
+	}
+}
+
+// versionState describes whether a version is released or archived
+func versionState(version *types.Version) string {
+	switch {
+	case version.Archived:
+		return "archived"
+	case version.Released:
+		return "released"
+	default:
+		return "unreleased"
+	}
+}
+
+// orDash stands in a dash for an unset value
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// GetReleaseCmd returns the release command
+func GetReleaseCmd() *cobra.Command {
+	return releaseCmd
+}
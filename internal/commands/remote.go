@@ -0,0 +1,207 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/internal/config"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteProjectFlag  string
+	remoteUsernameFlag string
+	remoteTokenFlag    string
+	remoteFlavorFlag   string
+	remoteDefaultFlag  bool
+)
+
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Manage the Jira sites jit works with",
+	Long: `Work with several Jira sites, the way git works with several remotes. The
+jira section of the config is the remote named "default"; further sites are
+added by name under remotes. Every command takes --remote to pick a site,
+falling back to JIT_REMOTE and then to default_remote.
+
+Each named remote keeps its own tickets, focus, caches and logins under
+remotes/<name> in the data directory, so keys from different sites never
+collide. The default remote keeps using the data directory itself.
+
+Examples:
+  jit remote                                           # List remotes
+  jit remote add work https://work.atlassian.net --project SRE
+  jit --remote work track SRE-1234                     # Use a remote once
+  jit remote default work                              # Use it by default
+  jit remote remove work`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listRemotes()
+	},
+}
+
+var remoteListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List remotes",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		listRemotes()
+	},
+}
+
+var remoteAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add a Jira site as a named remote",
+	Long: `Add a Jira site as a named remote. The username defaults to the one in the
+jira section, and the token to ${JIT_<NAME>_TOKEN}, read from the environment
+when the config is loaded so the secret stays out of the file.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, url := args[0], strings.TrimRight(args[1], "/")
+
+		cfg, err := config.Load()
+		if err != nil {
+			HandleError(err, "Configuration error")
+			return
+		}
+		if _, exists := cfg.Remotes[name]; exists {
+			HandleError(fmt.Errorf("remote %s already exists; remove it first to change it", name), "Failed to add remote")
+			return
+		}
+
+		remote := types.JiraConfig{
+			URL:      url,
+			Username: remoteUsernameFlag,
+			Token:    remoteTokenFlag,
+			Project:  strings.ToUpper(remoteProjectFlag),
+			Flavor:   remoteFlavorFlag,
+		}
+		if remote.Username == "" {
+			remote.Username = cfg.Jira.Username
+		}
+		tokenVar := ""
+		if remote.Token == "" {
+			tokenVar = remoteTokenVariable(name)
+			remote.Token = "${" + tokenVar + "}"
+		}
+
+		if err := config.ValidateRemote(name, remote); err != nil {
+			HandleError(err, "Invalid remote")
+			return
+		}
+		if err := config.AddRemote(name, remote); err != nil {
+			HandleError(err, "Failed to add remote")
+			return
+		}
+		if remoteDefaultFlag {
+			if err := config.SetDefaultRemote(name); err != nil {
+				HandleError(err, "Failed to set default remote")
+				return
+			}
+		}
+
+		PrintSuccess(fmt.Sprintf("Added remote %s: %s (%s)", name, remote.URL, remote.Project))
+		if tokenVar != "" {
+			PrintInfo(fmt.Sprintf("Set %s to the API token for %s", tokenVar, remote.URL))
+		}
+		if !remoteDefaultFlag {
+			fmt.Printf("Use it with 'jit --remote %s ...', or make it the default with 'jit remote default %s'\n", name, name)
+		}
+	},
+}
+
+var remoteRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a named remote",
+	Long: `Remove a named remote from the config. Its tickets and logins stay in the
+data directory under remotes/<name>, and come back if the remote is added
+again.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if name == types.DefaultRemoteName {
+			HandleError(fmt.Errorf("the default remote is the jira section of the config"), "Cannot remove remote")
+			return
+		}
+
+		if err := config.RemoveRemote(name); err != nil {
+			HandleError(err, "Failed to remove remote")
+			return
+		}
+		PrintSuccess(fmt.Sprintf("Removed remote %s", name))
+	},
+}
+
+var remoteDefaultCmd = &cobra.Command{
+	Use:   "default <name>",
+	Short: "Set the remote used without --remote",
+	Long: `Set the remote commands use when neither --remote nor JIT_REMOTE names
+one. Use "default" to go back to the jira section.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.SetDefaultRemote(args[0]); err != nil {
+			HandleError(err, "Failed to set default remote")
+			return
+		}
+		PrintSuccess(fmt.Sprintf("Default remote is now %s", args[0]))
+	},
+}
+
+func init() {
+	remoteAddCmd.Flags().StringVarP(&remoteProjectFlag, "project", "p", "", "Project key (required)")
+	remoteAddCmd.Flags().StringVar(&remoteUsernameFlag, "username", "", "Username (defaults to the jira section's)")
+	remoteAddCmd.Flags().StringVar(&remoteTokenFlag, "token", "", "API token, or a ${VAR} reference (defaults to ${JIT_<NAME>_TOKEN})")
+	remoteAddCmd.Flags().StringVar(&remoteFlavorFlag, "flavor", "", "Jira flavor: cloud, server or auto")
+	remoteAddCmd.Flags().BoolVar(&remoteDefaultFlag, "default", false, "Make it the default remote")
+	remoteAddCmd.MarkFlagRequired("project")
+
+	remoteCmd.AddCommand(remoteListCmd)
+	remoteCmd.AddCommand(remoteAddCmd)
+	remoteCmd.AddCommand(remoteRemoveCmd)
+	remoteCmd.AddCommand(remoteDefaultCmd)
+}
+
+// listRemotes prints the remotes, marking the one in use with *
+func listRemotes() {
+	cfg, err := config.Load()
+	if err != nil {
+		HandleError(err, "Configuration error")
+		return
+	}
+
+	_, active, err := cfg.Remote(selectedRemote())
+	if err != nil {
+		HandleError(err, "Unknown remote")
+		return
+	}
+
+	names := cfg.RemoteNames()
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	for _, name := range names {
+		remote, _, _ := cfg.Remote(name)
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+		fmt.Printf("%s %-*s  %s (%s)\n", marker, width, name, remote.URL, remote.Project)
+	}
+}
+
+// remoteTokenVariable names the environment variable a remote's token is
+// read from by default, e.g. JIT_WORK_TOKEN
+func remoteTokenVariable(name string) string {
+	name = strings.NewReplacer("-", "_").Replace(strings.ToUpper(name))
+	return "JIT_" + name + "_TOKEN"
+}
+
+// GetRemoteCmd returns the remote command
+func GetRemoteCmd() *cobra.Command {
+	return remoteCmd
+}
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/lunchboxsushi/jit/internal/utils"
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// searchTitleWidth truncates long titles in the results table
+const searchTitleWidth = 60
+
+var (
+	searchLimitFlag  int
+	searchFilterFlag string
+	searchTrackFlag  bool
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <jql|shorthand>...",
+	Short: "Search Jira with JQL or shorthands",
+	Long: `Search Jira and list the matching tickets, most recently updated first.
+
+The search is JQL, or shorthands that expand to JQL for the configured
+project:
+
+  mine, unassigned, reported    assigned to or reported by you, or unassigned
+  open, done                    by status category
+  sprint                        in an open sprint
+  updated:7d, created:2w        changed or created within an age (m, h, d, w)
+  project:KEY, type:Bug, status:"In Review", label:auth, text:"login page"
+
+--filter ranks the results with the same fuzzy matching as 'jit focus'.
+--track asks which results to pull into local storage, along with their
+parents and children; answer with numbers (1,3-5), "all", or text to
+narrow the list.
+
+Examples:
+  jit search mine open                        # Your open tickets
+  jit search mine updated:7d --track          # Track some of last week's tickets
+  jit search 'status:"In Review" label:auth'  # Shorthands with a quoted value
+  jit search 'assignee = currentUser() AND priority = High'`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		jql, err := jira.ExpandJQL(strings.Join(args, " "), ctx.Config.Jira.Project)
+		if err != nil {
+			HandleError(err, "Invalid search")
+			return
+		}
+		PrintInfo(fmt.Sprintf("JQL: %s", jql))
+
+		tickets, err := ctx.TicketService.Search(jql, &jira.SearchOptions{Limit: searchLimitFlag}).Collect(cmd.Context())
+		if err != nil {
+			HandleError(err, "Search failed")
+			return
+		}
+
+		if searchFilterFlag != "" {
+			tickets = rankTickets(tickets, searchFilterFlag)
+		}
+		if len(tickets) == 0 {
+			fmt.Println("No tickets found.")
+			return
+		}
+
+		printSearchTable(ctx, tickets)
+		if len(tickets) == searchLimitFlag {
+			fmt.Printf("\nShowing the first %d results; use --limit for more.\n", searchLimitFlag)
+		}
+
+		if !searchTrackFlag {
+			return
+		}
+
+		selected, err := selectSearchResults(ctx, tickets)
+		if err != nil {
+			HandleError(err, "Selection failed")
+			return
+		}
+
+		for _, ticket := range selected {
+			if err := trackSearchResult(cmd.Context(), ctx, ticket); err != nil {
+				HandleError(err, fmt.Sprintf("Failed to track %s", ticket.Key))
+				return
+			}
+		}
+		PrintSuccess(fmt.Sprintf("Tracked %d ticket(s)", len(selected)))
+	},
+}
+
+func init() {
+	searchCmd.Flags().IntVarP(&searchLimitFlag, "limit", "n", 50, "Maximum number of results")
+	searchCmd.Flags().StringVar(&searchFilterFlag, "filter", "", "Rank results by fuzzy match on key and title, dropping non-matches")
+	searchCmd.Flags().BoolVar(&searchTrackFlag, "track", false, "Choose results to track locally with their hierarchy")
+}
+
+// rankTickets keeps the tickets matching query, best match first, using the
+// fuzzy ranking 'jit focus' uses
+func rankTickets(tickets []*types.Ticket, query string) []*types.Ticket {
+	byKey := make(map[string]*types.Ticket, len(tickets))
+	infos := make([]utils.TicketInfo, 0, len(tickets))
+	for _, ticket := range tickets {
+		byKey[ticket.Key] = ticket
+		infos = append(infos, utils.TicketInfo{Key: ticket.Key, Title: ticket.Title, Type: ticket.Type})
+	}
+
+	var ranked []*types.Ticket
+	for _, result := range utils.FuzzySearch(query, infos) {
+		ranked = append(ranked, byKey[result.Key])
+	}
+	return ranked
+}
+
+// printSearchTable prints search results as a numbered table; tracked
+// tickets are marked with *
+func printSearchTable(ctx *CommandContext, tickets []*types.Ticket) {
+	fmt.Printf("\n    %-3s %-12s %-8s %-14s %-18s %s\n", "", "KEY", "TYPE", "STATUS", "ASSIGNEE", "TITLE")
+	for i, ticket := range tickets {
+		marker := " "
+		if ctx.Storage.Exists(ticket.Key) {
+			marker = "*"
+		}
+		fmt.Printf("  %s %3d %-12s %-8s %-14s %-18s %s\n", marker, i+1, ticket.Key,
+			truncate(ticket.Type, 8), truncate(ticket.Status, 14),
+			truncate(ticket.Metadata.AssigneeName, 18), truncate(ticket.Title, searchTitleWidth))
+	}
+}
+
+// truncate shortens text to width runes, marking the cut with an ellipsis
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// selectSearchResults asks which results to track. Text that is not a
+// selection narrows the list by fuzzy match and asks again.
+func selectSearchResults(ctx *CommandContext, tickets []*types.Ticket) ([]*types.Ticket, error) {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print("\nTrack which tickets? (e.g. 1,3-5, all, or text to filter): ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %v", err)
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return nil, fmt.Errorf("no selection made")
+		}
+
+		if indexes, ok := parseSelection(input, len(tickets)); ok {
+			selected := make([]*types.Ticket, 0, len(indexes))
+			for _, index := range indexes {
+				selected = append(selected, tickets[index])
+			}
+			return selected, nil
+		}
+
+		narrowed := rankTickets(tickets, input)
+		if len(narrowed) == 0 {
+			fmt.Printf("Nothing matches '%s'.\n", input)
+			continue
+		}
+		tickets = narrowed
+		printSearchTable(ctx, tickets)
+	}
+}
+
+// parseSelection reads "all" or a list of numbers and ranges such as
+// "1,3-5" into zero-based indexes below count. It reports false for input
+// that is not a selection.
+func parseSelection(input string, count int) ([]int, bool) {
+	if strings.EqualFold(input, "all") {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, true
+	}
+
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, part := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, false
+		}
+		last, err := strconv.Atoi(to)
+		if err != nil || first < 1 || last > count || first > last {
+			return nil, false
+		}
+
+		for n := first; n <= last; n++ {
+			if !seen[n] {
+				seen[n] = true
+				indexes = append(indexes, n-1)
+			}
+		}
+	}
+	return indexes, len(indexes) > 0
+}
+
+// trackSearchResult saves a ticket with its hierarchy: untracked parents
+// first, so the local tree links up, then the ticket and its children
+func trackSearchResult(cmdCtx context.Context, ctx *CommandContext, ticket *types.Ticket) error {
+	var parents []*types.Ticket
+	for key := ticket.Relationships.ParentKey; key != "" && !ctx.Storage.Exists(key); {
+		parent, err := ctx.TicketService.GetTicket(cmdCtx, key)
+		if err != nil {
+			return fmt.Errorf("failed to fetch parent %s: %v", key, err)
+		}
+		parents = append(parents, parent)
+		key = parent.Relationships.ParentKey
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		if err := ctx.Storage.SaveTicket(parents[i]); err != nil {
+			return fmt.Errorf("failed to save parent %s: %v", parents[i].Key, err)
+		}
+		fmt.Printf("Saved %s (%s)\n", parents[i].Key, parents[i].Title)
+	}
+
+	if err := ctx.Storage.SaveTicket(ticket); err != nil {
+		return fmt.Errorf("failed to save ticket %s: %v", ticket.Key, err)
+	}
+	fmt.Printf("Saved %s (%s)\n", ticket.Key, ticket.Title)
+
+	return ctx.trackHierarchy(cmdCtx, ticket)
+}
+
+// GetSearchCmd returns the search command
+func GetSearchCmd() *cobra.Command {
+	return searchCmd
+}
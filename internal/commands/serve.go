@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddrFlag   string
+	serveSecretFlag string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run local services for jit",
+	Long: `Run long-lived local services, such as a Jira webhook receiver.
+
+Examples:
+  jit serve webhooks --addr :8080   # Keep tracked tickets fresh from Jira webhooks`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var serveWebhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Receive Jira webhooks and update tracked tickets",
+	Long: `Listen for Jira webhooks and apply them to the local cache, so tracked
+tickets stay fresh without 'jit track'.
+
+Issue created, updated and deleted events and comment created events are
+handled. Updates replace a tracked ticket's Jira fields and keep its local
+state; tickets with unpushed local edits are left alone. New issues are
+tracked when their parent is.
+
+Every webhook must carry the shared secret, either as an HMAC-SHA256
+signature in the X-Hub-Signature header (Jira Cloud webhooks registered with
+a secret) or as a "secret" query parameter on the webhook URL. The secret is
+taken from --secret or 'webhook_secret' in the jira config.
+
+Examples:
+  jit serve webhooks                        # Listen on :8080
+  jit serve webhooks --addr 127.0.0.1:9000  # Listen on another address`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		secret := serveSecretFlag
+		if secret == "" {
+			secret = ctx.Config.Jira.WebhookSecret
+		}
+		if secret == "" {
+			HandleError(fmt.Errorf("no webhook secret; set 'webhook_secret' in the jira config or pass --secret"), "Failed to start webhook receiver")
+			return
+		}
+
+		handler := jira.NewWebhookHandler(ctx.TicketService, ctx.Storage, secret)
+		handler.Logf = func(format string, args ...interface{}) {
+			fmt.Printf("%s %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, args...))
+		}
+
+		if err := serveWebhooks(serveAddrFlag, handler); err != nil {
+			HandleError(err, "Webhook receiver failed")
+		}
+	},
+}
+
+func init() {
+	serveWebhooksCmd.Flags().StringVar(&serveAddrFlag, "addr", ":8080", "Address to listen on")
+	serveWebhooksCmd.Flags().StringVar(&serveSecretFlag, "secret", "", "Shared secret webhooks must carry (default: webhook_secret from config)")
+	serveCmd.AddCommand(serveWebhooksCmd)
+}
+
+// serveWebhooks runs the webhook receiver until interrupted
+func serveWebhooks(addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	PrintInfo(fmt.Sprintf("Listening for Jira webhooks on %s (Ctrl+C to stop)", addr))
+
+	select {
+	case err := <-errs:
+		return err
+	case <-stop.Done():
+	}
+
+	// Let webhooks being applied finish
+	shutdown, done := context.WithTimeout(context.Background(), 10*time.Second)
+	defer done()
+	if err := server.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	PrintInfo("Webhook receiver stopped")
+	return nil
+}
+
+// GetServeCmd returns the serve command
+func GetServeCmd() *cobra.Command {
+	return serveCmd
+}
@@ -0,0 +1,329 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sprintBoardFlag int
+	sprintAllFlag   bool
+	sprintToFlag    string
+)
+
+var sprintCmd = &cobra.Command{
+	Use:   "sprint",
+	Short: "Work with the project's sprints",
+	Long: `List sprints, show what is in them and plan tickets into them.
+
+Sprints are read from the project's scrum board, or from the board set by
+jira.board_id or --board. Sprints may be given by ID, by name, or as
+"current" for the active sprint. Tracked tickets remember their sprint, so
+'jit log --sprint current' can filter by it.
+
+Examples:
+  jit sprint current                 # Summarize the active sprint
+  jit sprint list                    # List active and future sprints
+  jit sprint show "Sprint 8"         # List the tickets in a sprint
+  jit sprint add                     # Move current focus into the active sprint
+  jit sprint add SRE-1234 --to 42    # Move a ticket into sprint 42`,
+}
+
+var sprintListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the board's sprints",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		board, ok := sprintBoard(cmd.Context(), ctx)
+		if !ok {
+			return
+		}
+
+		states := []string{types.SprintActive, types.SprintFuture}
+		if sprintAllFlag {
+			states = nil
+		}
+		sprints, err := ctx.TicketService.Sprints(cmd.Context(), board.ID, states...)
+		if err != nil {
+			HandleError(err, "Failed to fetch sprints")
+			return
+		}
+
+		if len(sprints) == 0 {
+			fmt.Printf("%s has no open sprints.\n", board.Name)
+			return
+		}
+
+		fmt.Printf("Sprints on %s:\n", board.Name)
+		for _, sprint := range sprints {
+			marker := " "
+			if sprint.IsActive() {
+				marker = "●"
+			}
+			fmt.Printf("  %s %-6d %-28s %-8s %s\n", marker, sprint.ID, sprint.Name, sprint.State, formatSprintDates(sprint))
+		}
+	},
+}
+
+var sprintShowCmd = &cobra.Command{
+	Use:   "show [sprint]",
+	Short: "List the tickets in a sprint",
+	Long: `List the tickets in a sprint, the active one unless a sprint is given.
+Tracked tickets are marked and their sprint is recorded locally.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		query := types.SprintCurrent
+		if len(args) > 0 {
+			query = args[0]
+		}
+
+		sprint, tickets, ok := loadSprint(cmd.Context(), ctx, query)
+		if !ok {
+			return
+		}
+
+		printSprintHeader(sprint)
+		if len(tickets) == 0 {
+			fmt.Println("\nNo tickets in this sprint.")
+			return
+		}
+
+		fmt.Println()
+		for _, ticket := range tickets {
+			marker := " "
+			if ctx.Storage.Exists(ticket.Key) {
+				marker = "*"
+			}
+			fmt.Printf("  %s %-12s %-14s %s", marker, ticket.Key, ticket.Status, ticket.Title)
+			if ticket.Metadata.AssigneeName != "" {
+				fmt.Printf("  (%s)", ticket.Metadata.AssigneeName)
+			}
+			fmt.Println()
+		}
+		fmt.Println("\n* tracked locally")
+	},
+}
+
+var sprintCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Summarize the active sprint",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		sprint, tickets, ok := loadSprint(cmd.Context(), ctx, types.SprintCurrent)
+		if !ok {
+			return
+		}
+
+		printSprintHeader(sprint)
+		if !sprint.EndDate.IsZero() {
+			days := int(math.Ceil(time.Until(sprint.EndDate).Hours() / 24))
+			if days < 0 {
+				days = 0
+			}
+			fmt.Printf("Remaining: %d day(s)\n", days)
+		}
+
+		// Count tickets by status
+		counts := make(map[string]int)
+		var statuses []string
+		tracked := 0
+		for _, ticket := range tickets {
+			if counts[ticket.Status] == 0 {
+				statuses = append(statuses, ticket.Status)
+			}
+			counts[ticket.Status]++
+			if ctx.Storage.Exists(ticket.Key) {
+				tracked++
+			}
+		}
+
+		fmt.Printf("Tickets:   %d (%d tracked)\n", len(tickets), tracked)
+		for _, status := range statuses {
+			fmt.Printf("  %-14s %d\n", status, counts[status])
+		}
+	},
+}
+
+var sprintAddCmd = &cobra.Command{
+	Use:   "add [ticket-key]",
+	Short: "Move a ticket into a sprint",
+	Long: `Move a ticket into a sprint, the active one unless --to names another.
+If no ticket is specified, uses current focus.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		board, ok := sprintBoard(cmd.Context(), ctx)
+		if !ok {
+			return
+		}
+
+		sprint, err := ctx.TicketService.FindSprint(cmd.Context(), board.ID, sprintToFlag)
+		if err != nil {
+			HandleError(err, "Failed to find sprint")
+			return
+		}
+
+		if err := ctx.TicketService.MoveToSprint(cmd.Context(), sprint, ticketKey); err != nil {
+			HandleError(err, "Failed to move ticket")
+			return
+		}
+
+		// Keep the local copy in step
+		if ctx.Storage.Exists(ticketKey) {
+			if ticket, err := ctx.Storage.LoadTicket(ticketKey); err == nil {
+				ticket.Metadata.Sprint = sprint
+				if err := ctx.Storage.SaveTicket(ticket); err != nil {
+					PrintWarning(fmt.Sprintf("Failed to update local ticket: %v", err))
+				}
+			}
+		}
+
+		PrintSuccess(fmt.Sprintf("Moved %s to %s", ticketKey, sprint.Name))
+	},
+}
+
+func init() {
+	sprintCmd.PersistentFlags().IntVar(&sprintBoardFlag, "board", 0, "Board ID (defaults to jira.board_id or the project's scrum board)")
+	sprintListCmd.Flags().BoolVar(&sprintAllFlag, "all", false, "Include closed sprints")
+	sprintAddCmd.Flags().StringVar(&sprintToFlag, "to", types.SprintCurrent, "Sprint ID or name")
+
+	sprintCmd.AddCommand(sprintListCmd)
+	sprintCmd.AddCommand(sprintShowCmd)
+	sprintCmd.AddCommand(sprintAddCmd)
+	sprintCmd.AddCommand(sprintCurrentCmd)
+}
+
+// sprintBoard finds the board to read sprints from
+func sprintBoard(cmdCtx context.Context, ctx *CommandContext) (*types.Board, bool) {
+	board, err := ctx.TicketService.Board(cmdCtx, ctx.Config.Jira.Project, sprintBoardFlag)
+	if err != nil {
+		HandleError(err, "Failed to find board")
+		return nil, false
+	}
+	return board, true
+}
+
+// loadSprint fetches the sprint selected by query and its tickets, and
+// records the sprint on tracked tickets
+func loadSprint(cmdCtx context.Context, ctx *CommandContext, query string) (*types.Sprint, []*types.Ticket, bool) {
+	board, ok := sprintBoard(cmdCtx, ctx)
+	if !ok {
+		return nil, nil, false
+	}
+
+	sprint, err := ctx.TicketService.FindSprint(cmdCtx, board.ID, query)
+	if err != nil {
+		HandleError(err, "Failed to find sprint")
+		return nil, nil, false
+	}
+
+	tickets, err := ctx.TicketService.SprintTickets(cmdCtx, sprint)
+	if err != nil {
+		HandleError(err, "Failed to fetch sprint tickets")
+		return nil, nil, false
+	}
+
+	if err := recordSprint(ctx, sprint, tickets); err != nil {
+		PrintWarning(fmt.Sprintf("Failed to record sprint on tracked tickets: %v", err))
+	}
+
+	return sprint, tickets, true
+}
+
+// recordSprint sets the sprint on tracked tickets that are in it and clears
+// it from tracked tickets that have left it
+func recordSprint(ctx *CommandContext, sprint *types.Sprint, tickets []*types.Ticket) error {
+	inSprint := make(map[string]bool, len(tickets))
+	for _, ticket := range tickets {
+		inSprint[ticket.Key] = true
+	}
+
+	keys, err := ctx.Storage.ListTickets()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		ticket, err := ctx.Storage.LoadTicket(key)
+		if err != nil {
+			continue
+		}
+
+		current := ticket.Metadata.Sprint
+		switch {
+		case inSprint[key]:
+			if current != nil && *current == *sprint {
+				continue
+			}
+			ticket.Metadata.Sprint = sprint
+		case current != nil && current.ID == sprint.ID:
+			ticket.Metadata.Sprint = nil
+		default:
+			continue
+		}
+
+		if err := ctx.Storage.SaveTicket(ticket); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printSprintHeader prints a sprint's name, dates and goal
+func printSprintHeader(sprint *types.Sprint) {
+	fmt.Printf("%s (%s)\n", sprint.Name, sprint.State)
+	if dates := formatSprintDates(sprint); dates != "" {
+		fmt.Printf("Dates:     %s\n", dates)
+	}
+	if goal := strings.TrimSpace(sprint.Goal); goal != "" {
+		fmt.Printf("Goal:      %s\n", goal)
+	}
+}
+
+// formatSprintDates renders a sprint's start and end dates
+func formatSprintDates(sprint *types.Sprint) string {
+	if sprint.StartDate.IsZero() || sprint.EndDate.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s → %s", sprint.StartDate.Local().Format("Jan 2"), sprint.EndDate.Local().Format("Jan 2"))
+}
+
+// GetSprintCmd returns the sprint command
+func GetSprintCmd() *cobra.Command {
+	return sprintCmd
+}
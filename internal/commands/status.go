@@ -1,58 +1,136 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"time"
 
+	"github.com/lunchboxsushi/jit/internal/jira"
+	"github.com/lunchboxsushi/jit/pkg/types"
 	"github.com/spf13/cobra"
 )
 
+var (
+	statusResolutionFlag string
+	statusCommentFlag    string
+)
+
 var statusCmd = &cobra.Command{
 	Use:   "status [status]",
 	Short: "Change status of current focus item",
-	Long: `Change the status of the currently focused ticket.
-	
-Available statuses:
-  todo, to-do, todo     - Set status to "To Do"
-  progress, in-progress - Set status to "In Progress" 
-  done, completed       - Set status to "Done"
-  blocked              - Set status to "Blocked"
+	Long: `Move the currently focused ticket through its Jira workflow.
+
+Without arguments, shows the current status and the transitions available.
+The status can be a workflow status name, a transition name, or an alias:
+  todo, to-do           - "To Do"
+  progress, start       - "In Progress"
+  review                - "In Review"
+  done, completed       - "Done"
+  blocked               - "Blocked"
+
+Aliases can be overridden per project with jira.status_aliases in the config.
 
 Examples:
-  jit status done       # Mark current focus as done
-  jit status progress   # Start working on current focus
-  jit status blocked    # Mark current focus as blocked`,
-	Args: cobra.ExactArgs(1),
+  jit status                          # Show status and available transitions
+  jit status progress                 # Start working on current focus
+  jit status done -r "Won't Do"       # Close with a specific resolution
+  jit status blocked -m "Waiting on infra"`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		status := normalizeStatus(args[0])
-		if status == "" {
-			fmt.Println("Invalid status. Use: todo, progress, done, or blocked")
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		ticketKey, err := ctx.ContextManager.GetCurrentFocus()
+		if err != nil {
+			HandleError(err, "Failed to get current focus")
+			return
+		}
+		if ticketKey == "" {
+			fmt.Println("No current focus.")
+			fmt.Println("Use 'jit focus <ticket>' to set focus first.")
+			return
+		}
+
+		ticket, err := ctx.Storage.LoadTicket(ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to load ticket")
+			return
+		}
+
+		project := ticket.Metadata.Project
+		if project == "" {
+			project = ctx.Config.Jira.Project
+		}
+
+		if len(args) == 0 {
+			showStatus(cmd.Context(), ctx, ticket)
 			return
 		}
 
-		// TODO: Implement status change logic
-		// For now, just show what would happen
-		fmt.Printf("Would change current focus status to: %s\n", status)
-		fmt.Println("(Status command not yet implemented)")
+		opts := &jira.TransitionOptions{
+			Resolution: statusResolutionFlag,
+			Comment:    statusCommentFlag,
+		}
+
+		fmt.Printf("Transitioning %s...\n", ticket.Key)
+		transition, err := ctx.TicketService.TransitionTicket(cmd.Context(), ticket.Key, project, args[0], opts)
+		var commentErr *jira.CommentError
+		if err != nil && !errors.As(err, &commentErr) {
+			HandleError(err, "Failed to change status")
+			return
+		}
+
+		// Reflect the new status locally
+		now := time.Now()
+		ticket.Status = transition.To.Name
+		ticket.Metadata.Updated = now
+		ticket.LocalData.LastSync = now
+		if err := ctx.Storage.SaveTicket(ticket); err != nil {
+			HandleError(err, "Failed to save ticket locally")
+			return
+		}
+
+		PrintSuccess(fmt.Sprintf("%s is now %s", ticket.Key, GetStatusColor(ticket.Status).Render(ticket.Status)))
+		if commentErr != nil {
+			PrintWarning(fmt.Sprintf("The comment was not posted: %v", commentErr.Err))
+		}
 	},
 }
 
-// normalizeStatus converts various status inputs to standard status values
-func normalizeStatus(input string) string {
-	status := strings.ToLower(strings.TrimSpace(input))
-
-	switch status {
-	case "todo", "to-do", "to do":
-		return "To Do"
-	case "progress", "in-progress", "in progress":
-		return "In Progress"
-	case "done", "completed", "complete":
-		return "Done"
-	case "blocked", "block":
-		return "Blocked"
-	default:
-		return ""
+func init() {
+	statusCmd.Flags().StringVarP(&statusResolutionFlag, "resolution", "r", "", "Resolution to set when the transition asks for one")
+	statusCmd.Flags().StringVarP(&statusCommentFlag, "message", "m", "", "Comment to add with the transition")
+}
+
+// showStatus prints the ticket's current status and the transitions available from it
+func showStatus(reqCtx context.Context, ctx *CommandContext, ticket *types.Ticket) {
+	transitions, err := ctx.TicketService.GetTransitions(reqCtx, ticket.Key)
+	if err != nil {
+		HandleError(err, "Failed to fetch transitions")
+		return
+	}
+
+	fmt.Printf("%s %s - %s\n", ticket.Key, GetStatusColor(ticket.Status).Render("<"+ticket.Status+">"), ticket.Title)
+
+	if len(transitions) == 0 {
+		fmt.Println("\nNo transitions available.")
+		return
+	}
+
+	fmt.Println("\nAvailable transitions:")
+	for _, transition := range transitions {
+		line := fmt.Sprintf("  %-20s → %s", transition.Name, GetStatusColor(transition.To.Name).Render(transition.To.Name))
+		if transition.HasScreen {
+			line += " (has screen)"
+		}
+		fmt.Println(line)
 	}
+	fmt.Printf("\nUse 'jit status <status>' to move %s.\n", ticket.Key)
 }
 
 // GetStatusCmd returns the status command
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timerMessageFlag string
+	timerOfflineFlag bool
+	timerDiscardFlag bool
+)
+
+var timerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Track time against a ticket",
+	Long: `Run a timer against a ticket and log the time in Jira when it stops.
+
+The running timer is kept in the data directory, so it survives closing the
+terminal. Worklogs that cannot be pushed are queued until the next
+successful push.
+
+Examples:
+  jit timer start               # Start timing current focus
+  jit timer start SRE-1234      # Start timing a specific ticket
+  jit timer status              # Show the running timer
+  jit timer stop -m "Pairing"   # Stop and log the time with a comment
+  jit timer stop --offline      # Stop and queue the worklog`,
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:   "start [ticket-key]",
+	Short: "Start a timer",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		running, err := ctx.Storage.LoadTimer()
+		if err != nil {
+			HandleError(err, "Failed to load timer")
+			return
+		}
+		if running != nil {
+			fmt.Printf("A timer is already running on %s (%s).\n", running.TicketKey, types.FormatDuration(running.Elapsed(time.Now())))
+			fmt.Println("Use 'jit timer stop' to stop it first.")
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		if err := ctx.Storage.SaveTimer(types.NewTimer(ticketKey)); err != nil {
+			HandleError(err, "Failed to start timer")
+			return
+		}
+
+		PrintSuccess(fmt.Sprintf("Timer started on %s", ticketKey))
+	},
+}
+
+var timerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the timer and log the time",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		timer, err := ctx.Storage.LoadTimer()
+		if err != nil {
+			HandleError(err, "Failed to load timer")
+			return
+		}
+		if timer == nil {
+			fmt.Println("No timer running.")
+			return
+		}
+
+		elapsed := timer.Elapsed(time.Now())
+
+		if !timerDiscardFlag {
+			worklog := &types.Worklog{
+				TicketKey: timer.TicketKey,
+				Started:   timer.Started,
+				TimeSpent: elapsed,
+				Comment:   timerMessageFlag,
+			}
+			if err := recordWorklog(cmd.Context(), ctx, worklog, timerOfflineFlag); err != nil {
+				HandleError(err, "Failed to record worklog; timer left running")
+				return
+			}
+		}
+
+		if err := ctx.Storage.DeleteTimer(); err != nil {
+			HandleError(err, "Failed to clear timer")
+			return
+		}
+
+		if timerDiscardFlag {
+			PrintInfo(fmt.Sprintf("Discarded %s on %s", types.FormatDuration(elapsed), timer.TicketKey))
+		}
+	},
+}
+
+var timerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		timer, err := ctx.Storage.LoadTimer()
+		if err != nil {
+			HandleError(err, "Failed to load timer")
+			return
+		}
+		if timer == nil {
+			fmt.Println("No timer running.")
+		} else {
+			fmt.Printf("Timing %s for %s (since %s)\n",
+				timer.TicketKey,
+				types.FormatDuration(timer.Elapsed(time.Now())),
+				timer.Started.Local().Format("15:04"))
+		}
+
+		queue, err := ctx.Storage.LoadWorklogQueue()
+		if err != nil {
+			HandleError(err, "Failed to load worklog queue")
+			return
+		}
+		if len(queue) > 0 {
+			fmt.Printf("%d worklog(s) queued; push them with 'jit worklog --push'\n", len(queue))
+		}
+	},
+}
+
+func init() {
+	timerStopCmd.Flags().StringVarP(&timerMessageFlag, "message", "m", "", "Worklog comment")
+	timerStopCmd.Flags().BoolVar(&timerOfflineFlag, "offline", false, "Queue the worklog instead of pushing it to Jira")
+	timerStopCmd.Flags().BoolVar(&timerDiscardFlag, "discard", false, "Stop the timer without logging the time")
+
+	timerCmd.AddCommand(timerStartCmd)
+	timerCmd.AddCommand(timerStopCmd)
+	timerCmd.AddCommand(timerStatusCmd)
+}
+
+// GetTimerCmd returns the timer command
+func GetTimerCmd() *cobra.Command {
+	return timerCmd
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	worklogPushFlag bool
+)
+
+var worklogCmd = &cobra.Command{
+	Use:   "worklog [ticket-key]",
+	Short: "List time logged on a ticket",
+	Long: `List the worklogs on a ticket, including entries queued offline that
+have not been pushed to Jira yet. If no ticket is specified, uses current focus.
+
+Examples:
+  jit worklog               # Worklogs of current focus
+  jit worklog SRE-1234      # Worklogs of a specific ticket
+  jit worklog --push        # Push queued worklogs to Jira`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Initialize command context
+		ctx, err := InitializeCommand()
+		if err != nil {
+			HandleError(err, "Failed to initialize")
+			return
+		}
+
+		if worklogPushFlag {
+			pushed, remaining, err := pushWorklogQueue(cmd.Context(), ctx)
+			if err != nil {
+				HandleError(err, fmt.Sprintf("Pushed %d queued worklog(s); %d still queued", pushed, remaining))
+				return
+			}
+			if pushed == 0 {
+				fmt.Println("No queued worklogs.")
+				return
+			}
+			PrintSuccess(fmt.Sprintf("Pushed %d queued worklog(s) to Jira", pushed))
+			return
+		}
+
+		ticketKey, ok := ticketKeyOrFocus(ctx, args)
+		if !ok {
+			return
+		}
+
+		worklogs, err := ctx.TicketService.GetWorklogs(cmd.Context(), ticketKey)
+		if err != nil {
+			HandleError(err, "Failed to fetch worklogs")
+			return
+		}
+
+		queue, err := ctx.Storage.LoadWorklogQueue()
+		if err != nil {
+			HandleError(err, "Failed to load worklog queue")
+			return
+		}
+
+		var queued []*types.Worklog
+		for _, worklog := range queue {
+			if worklog.TicketKey == ticketKey {
+				queued = append(queued, worklog)
+			}
+		}
+
+		if len(worklogs) == 0 && len(queued) == 0 {
+			fmt.Printf("No time logged on %s.\n", ticketKey)
+			return
+		}
+
+		var total time.Duration
+		fmt.Printf("Worklogs for %s:\n", ticketKey)
+		for _, worklog := range worklogs {
+			printWorklog(worklog, "")
+			total += worklog.TimeSpent
+		}
+		for _, worklog := range queued {
+			printWorklog(worklog, "(queued)")
+			total += worklog.TimeSpent
+		}
+		fmt.Printf("Total: %s\n", types.FormatDuration(total))
+	},
+}
+
+func init() {
+	worklogCmd.Flags().BoolVar(&worklogPushFlag, "push", false, "Push worklogs queued offline to Jira")
+}
+
+// printWorklog prints one worklog line
+func printWorklog(worklog *types.Worklog, note string) {
+	parts := []string{
+		worklog.Started.Local().Format("2006-01-02 15:04"),
+		fmt.Sprintf("%8s", types.FormatDuration(worklog.TimeSpent)),
+	}
+	if worklog.Author != "" {
+		parts = append(parts, worklog.Author)
+	}
+	if note != "" {
+		parts = append(parts, StatusBlocked.Render(note))
+	}
+	if comment := strings.TrimSpace(worklog.Comment); comment != "" {
+		parts = append(parts, "- "+strings.SplitN(comment, "\n", 2)[0])
+	}
+	fmt.Printf("  %s\n", strings.Join(parts, "  "))
+}
+
+// recordWorklog pushes a worklog to Jira, or queues it when offline or when
+// the push fails. A successful push also flushes earlier queued worklogs.
+func recordWorklog(cmdCtx context.Context, ctx *CommandContext, worklog *types.Worklog, offline bool) error {
+	duration := types.FormatDuration(worklog.TimeSpent)
+
+	if !offline {
+		_, err := ctx.TicketService.AddWorklog(cmdCtx, worklog)
+		if err == nil {
+			PrintSuccess(fmt.Sprintf("Logged %s on %s", duration, worklog.TicketKey))
+
+			pushed, remaining, err := pushWorklogQueue(cmdCtx, ctx)
+			if pushed > 0 {
+				PrintSuccess(fmt.Sprintf("Pushed %d queued worklog(s)", pushed))
+			}
+			if err != nil {
+				PrintWarning(fmt.Sprintf("%d worklog(s) still queued: %v", remaining, err))
+			}
+			return nil
+		}
+		PrintWarning(fmt.Sprintf("Failed to log time in Jira: %v", err))
+	}
+
+	queue, err := ctx.Storage.LoadWorklogQueue()
+	if err != nil {
+		return err
+	}
+	if err := ctx.Storage.SaveWorklogQueue(append(queue, worklog)); err != nil {
+		return err
+	}
+
+	PrintInfo(fmt.Sprintf("Queued %s on %s; push it with 'jit worklog --push'", duration, worklog.TicketKey))
+	return nil
+}
+
+// pushWorklogQueue pushes queued worklogs in order, stopping at the first
+// failure so the rest stay queued
+func pushWorklogQueue(cmdCtx context.Context, ctx *CommandContext) (int, int, error) {
+	queue, err := ctx.Storage.LoadWorklogQueue()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	pushed := 0
+	for pushed < len(queue) {
+		if _, err = ctx.TicketService.AddWorklog(cmdCtx, queue[pushed]); err != nil {
+			break
+		}
+		pushed++
+	}
+
+	if pushed > 0 {
+		if saveErr := ctx.Storage.SaveWorklogQueue(queue[pushed:]); saveErr != nil {
+			return pushed, len(queue) - pushed, saveErr
+		}
+	}
+
+	return pushed, len(queue) - pushed, err
+}
+
+// ticketKeyOrFocus returns the ticket named in args, falling back to the
+// current focus. It reports false after explaining when there is neither.
+func ticketKeyOrFocus(ctx *CommandContext, args []string) (string, bool) {
+	if len(args) > 0 {
+		return strings.ToUpper(args[0]), true
+	}
+
+	ticketKey, err := ctx.ContextManager.GetCurrentFocus()
+	if err != nil {
+		HandleError(err, "Failed to get current focus")
+		return "", false
+	}
+	if ticketKey == "" {
+		fmt.Println("No ticket specified and no current focus.")
+		fmt.Println("Use 'jit focus <ticket>' to set focus or specify a ticket key.")
+		return "", false
+	}
+
+	return ticketKey, true
+}
+
+// GetWorklogCmd returns the worklog command
+func GetWorklogCmd() *cobra.Command {
+	return worklogCmd
+}
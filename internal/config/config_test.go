@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/lunchboxsushi/jit/pkg/types"
 	"gopkg.in/yaml.v3"
@@ -283,6 +284,54 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "server flavor without username",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:     "https://jira.example.com",
+					Flavor:  "server",
+					Token:   "test-pat",
+					Project: "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "oauth without api token",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:     "https://example.atlassian.net",
+					Project: "TEST",
+					OAuth:   &types.OAuthConfig{ClientID: "client-123"},
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid flavor",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Flavor:   "onprem",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "lowercase project",
 			config: &types.Config{
@@ -307,6 +356,98 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "network settings",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+				Network: types.NetworkConfig{
+					ProxyURL:   "proxy.corp.example:3128",
+					NoProxy:    []string{".corp.example", "10.0.0.0/8"},
+					ClientCert: "~/.jit/client.pem",
+					ClientKey:  "~/.jit/client-key.pem",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "client certificate without key",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+				Network: types.NetworkConfig{ClientCert: "~/.jit/client.pem"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative request timeout",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+				Network: types.NetworkConfig{RequestTimeout: -time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remote without project",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+				Remotes: map[string]types.JiraConfig{
+					"work": {URL: "https://work.example.com", Username: "me@work.example.com", Token: "work-token"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "default remote not configured",
+			config: &types.Config{
+				Jira: types.JiraConfig{
+					URL:      "https://example.com",
+					Username: "test@example.com",
+					Token:    "test-token",
+					Project:  "TEST",
+				},
+				App: types.AppConfig{
+					DataDir:       "/tmp/jit",
+					DefaultEditor: "vim",
+				},
+				DefaultRemote: "work",
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
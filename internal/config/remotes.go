@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// remoteNamePattern is the form remote names take, so they are safe as
+// directory names and on the command line
+var remoteNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateRemoteName checks that name can name a new remote
+func ValidateRemoteName(name string) error {
+	if name == types.DefaultRemoteName {
+		return fmt.Errorf("%q is the remote configured in the jira section", name)
+	}
+	if !remoteNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid remote name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// AddRemote adds a named remote to the config file, replacing one of the
+// same name. The rest of the file, comments and ${VAR} references included,
+// is kept as written.
+func AddRemote(name string, remote types.JiraConfig) error {
+	if err := ValidateRemoteName(name); err != nil {
+		return err
+	}
+
+	var value yaml.Node
+	if err := value.Encode(remote); err != nil {
+		return fmt.Errorf("failed to encode remote %s: %v", name, err)
+	}
+	// Leave unset settings out rather than writing empty strings
+	for i := 0; i+1 < len(value.Content); {
+		if v := value.Content[i+1]; v.Kind == yaml.ScalarNode && v.Value == "" {
+			value.Content = append(value.Content[:i], value.Content[i+2:]...)
+			continue
+		}
+		i += 2
+	}
+
+	return editConfigFile(func(root *yaml.Node) error {
+		remotes := mappingValue(root, "remotes")
+		if remotes == nil || remotes.Kind != yaml.MappingNode {
+			remotes = &yaml.Node{Kind: yaml.MappingNode}
+			setMappingValue(root, "remotes", remotes)
+		}
+		setMappingValue(remotes, name, &value)
+		return nil
+	})
+}
+
+// RemoveRemote removes a named remote from the config file, and clears
+// default_remote when it named that remote
+func RemoveRemote(name string) error {
+	return editConfigFile(func(root *yaml.Node) error {
+		remotes := mappingValue(root, "remotes")
+		if remotes == nil || !deleteMappingKey(remotes, name) {
+			return fmt.Errorf("no remote %q", name)
+		}
+		if len(remotes.Content) == 0 {
+			deleteMappingKey(root, "remotes")
+		}
+		if def := mappingValue(root, "default_remote"); def != nil && def.Value == name {
+			deleteMappingKey(root, "default_remote")
+		}
+		return nil
+	})
+}
+
+// SetDefaultRemote records the remote used without --remote. The name
+// "default" goes back to the jira section.
+func SetDefaultRemote(name string) error {
+	return editConfigFile(func(root *yaml.Node) error {
+		if name == types.DefaultRemoteName {
+			deleteMappingKey(root, "default_remote")
+			return nil
+		}
+		if remotes := mappingValue(root, "remotes"); remotes == nil || mappingValue(remotes, name) == nil {
+			return fmt.Errorf("no remote %q", name)
+		}
+		setMappingValue(root, "default_remote", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name})
+		return nil
+	})
+}
+
+// editConfigFile applies edit to the config file's YAML document without
+// expanding environment variables, so secrets never get written back
+func editConfigFile(edit func(root *yaml.Node) error) error {
+	configPath := GetDefaultConfigPath()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("configuration file not found: %s. Run 'jit init' to create one", configPath)
+		}
+		return fmt.Errorf("failed to read config file %s: %v", configPath, err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", configPath, err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a YAML mapping", configPath)
+	}
+
+	if err := edit(doc.Content[0]); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
+	}
+	encoder.Close()
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %v", configPath, err)
+	}
+	if err := os.WriteFile(configPath, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config file %s: %v", configPath, err)
+	}
+
+	return nil
+}
+
+// mappingValue returns the value under key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets the value under key in a mapping node, appending the
+// key when it is new
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+// deleteMappingKey removes key from a mapping node and reports whether it
+// was there
+func deleteMappingKey(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
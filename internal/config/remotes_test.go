@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+const remotesTestConfig = `# Personal site
+jira:
+  url: "https://home.atlassian.net"
+  username: "me@example.com"
+  token: "${JIRA_API_TOKEN}"
+  project: "PROJ"
+app:
+  data_dir: "/tmp/jit"
+  default_editor: "vim"
+`
+
+// writeTestConfig writes content as the config file under a temporary
+// XDG_CONFIG_HOME and returns its path
+func writeTestConfig(t *testing.T, content string) string {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := GetDefaultConfigPath()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	return path
+}
+
+// readTestConfig parses the config file without expanding variables
+func readTestConfig(t *testing.T, path string) (*types.Config, string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	var config types.Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Failed to parse config: %v\n%s", err, data)
+	}
+	return &config, string(data)
+}
+
+func TestAddRemoteKeepsFile(t *testing.T) {
+	path := writeTestConfig(t, remotesTestConfig)
+	t.Setenv("JIRA_API_TOKEN", "secret-token")
+
+	remote := types.JiraConfig{URL: "https://work.atlassian.net", Username: "me@work.example", Token: "${JIT_WORK_TOKEN}", Project: "SRE"}
+	if err := AddRemote("work", remote); err != nil {
+		t.Fatalf("AddRemote failed: %v", err)
+	}
+	if err := SetDefaultRemote("work"); err != nil {
+		t.Fatalf("SetDefaultRemote failed: %v", err)
+	}
+
+	config, data := readTestConfig(t, path)
+	if got := config.Remotes["work"]; got.URL != remote.URL || got.Project != "SRE" || got.Token != "${JIT_WORK_TOKEN}" {
+		t.Errorf("Expected the work remote to be saved, got %+v", got)
+	}
+	if config.DefaultRemote != "work" {
+		t.Errorf("Expected work to be the default remote, got %q", config.DefaultRemote)
+	}
+	if config.Jira.Token != "${JIRA_API_TOKEN}" || strings.Contains(data, "secret-token") {
+		t.Errorf("Expected variable references to be kept unexpanded, got:\n%s", data)
+	}
+	if !strings.Contains(data, "# Personal site") {
+		t.Errorf("Expected comments to be kept, got:\n%s", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file mode to be kept, got %v", info.Mode().Perm())
+	}
+
+	if err := RemoveRemote("work"); err != nil {
+		t.Fatalf("RemoveRemote failed: %v", err)
+	}
+	config, data = readTestConfig(t, path)
+	if len(config.Remotes) != 0 || config.DefaultRemote != "" {
+		t.Errorf("Expected the remote and the default to be gone, got:\n%s", data)
+	}
+}
+
+func TestRemoteEditErrors(t *testing.T) {
+	writeTestConfig(t, remotesTestConfig)
+
+	if err := AddRemote(types.DefaultRemoteName, types.JiraConfig{}); err == nil {
+		t.Error("Expected the default remote's name to be reserved")
+	}
+	if err := AddRemote("Work Site", types.JiraConfig{}); err == nil {
+		t.Error("Expected an invalid name to be rejected")
+	}
+	if err := RemoveRemote("missing"); err == nil {
+		t.Error("Expected removing an unknown remote to fail")
+	}
+	if err := SetDefaultRemote("missing"); err == nil {
+		t.Error("Expected an unknown default remote to fail")
+	}
+	if err := SetDefaultRemote(types.DefaultRemoteName); err != nil {
+		t.Errorf("Expected the jira section to be selectable as default, got %v", err)
+	}
+}
+
+func TestValidateRemoteFieldNames(t *testing.T) {
+	err := ValidateRemote("work", types.JiraConfig{URL: "https://work.atlassian.net", Username: "me", Token: "t"})
+	verr, ok := err.(ValidationError)
+	if !ok || verr.Field != "remotes.work.project" {
+		t.Errorf("Expected an error in remotes.work.project, got %v", err)
+	}
+}
@@ -28,6 +28,9 @@ func ValidateConfig(config *types.Config) []error {
 		errors = append(errors, err)
 	}
 
+	// Validate named remotes (optional)
+	errors = append(errors, validateRemotes(config)...)
+
 	// Validate AI configuration (optional)
 	if err := validateAIConfig(config.AI); err != nil {
 		errors = append(errors, err)
@@ -38,6 +41,11 @@ func ValidateConfig(config *types.Config) []error {
 		errors = append(errors, err)
 	}
 
+	// Validate network configuration (optional)
+	if err := validateNetworkConfig(config.Network); err != nil {
+		errors = append(errors, err)
+	}
+
 	return errors
 }
 
@@ -53,13 +61,28 @@ func validateJiraConfig(jira types.JiraConfig) error {
 		return ValidationError{Field: "jira.url", Message: fmt.Sprintf("Invalid URL format: %v", err)}
 	}
 
-	// Username is required
-	if jira.Username == "" {
+	// Flavor selects the API version and auth scheme
+	flavor := strings.ToLower(jira.Flavor)
+	switch flavor {
+	case "", "cloud", "server", "datacenter", "data-center", "dc", "auto":
+	default:
+		return ValidationError{Field: "jira.flavor", Message: fmt.Sprintf("Invalid flavor: %s. Valid flavors: cloud, server, auto", jira.Flavor)}
+	}
+
+	// OAuth logins replace the username and API token
+	usesOAuth := jira.OAuth != nil
+	if usesOAuth && jira.OAuth.ClientID == "" {
+		return ValidationError{Field: "jira.oauth.client_id", Message: "OAuth client ID is required when oauth is configured"}
+	}
+
+	// Username is required for basic auth; Server Personal Access Tokens stand alone
+	isServer := flavor != "" && flavor != "cloud" && flavor != "auto"
+	if jira.Username == "" && !isServer && !usesOAuth {
 		return ValidationError{Field: "jira.username", Message: "Jira username is required"}
 	}
 
 	// Token is required
-	if jira.Token == "" {
+	if jira.Token == "" && !usesOAuth {
 		return ValidationError{Field: "jira.token", Message: "Jira API token is required"}
 	}
 
@@ -76,6 +99,40 @@ func validateJiraConfig(jira types.JiraConfig) error {
 	return nil
 }
 
+// validateRemotes validates each named remote like the jira section, and
+// that the default remote exists
+func validateRemotes(config *types.Config) []error {
+	var errors []error
+
+	for _, name := range config.RemoteNames()[1:] {
+		if err := ValidateRemote(name, config.Remotes[name]); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	if name := config.DefaultRemote; name != "" && name != types.DefaultRemoteName {
+		if _, ok := config.Remotes[name]; !ok {
+			errors = append(errors, ValidationError{Field: "default_remote", Message: fmt.Sprintf("No remote named %s", name)})
+		}
+	}
+
+	return errors
+}
+
+// ValidateRemote validates a named remote's name and Jira settings
+func ValidateRemote(name string, remote types.JiraConfig) error {
+	if err := ValidateRemoteName(name); err != nil {
+		return ValidationError{Field: "remotes." + name, Message: err.Error()}
+	}
+
+	err := validateJiraConfig(remote)
+	if verr, ok := err.(ValidationError); ok {
+		verr.Field = "remotes." + name + strings.TrimPrefix(verr.Field, "jira")
+		return verr
+	}
+	return err
+}
+
 // validateAIConfig validates AI-specific configuration (optional)
 func validateAIConfig(ai types.AIConfig) error {
 	// If provider is empty, AI is disabled (which is fine)
@@ -111,6 +168,10 @@ func validateAIConfig(ai types.AIConfig) error {
 		return ValidationError{Field: "ai.max_tokens", Message: "AI max tokens must be greater than 0"}
 	}
 
+	if ai.Timeout < 0 {
+		return ValidationError{Field: "ai.timeout", Message: "Timeout cannot be negative"}
+	}
+
 	return nil
 }
 
@@ -129,6 +190,37 @@ func validateAppConfig(app types.AppConfig) error {
 	return nil
 }
 
+// validateNetworkConfig validates proxy, TLS and timeout settings (optional).
+// Certificate files are read when the transport is built.
+func validateNetworkConfig(network types.NetworkConfig) error {
+	if network.ProxyURL != "" {
+		raw := network.ProxyURL
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		if u, err := url.Parse(raw); err != nil || u.Host == "" {
+			return ValidationError{Field: "network.proxy_url", Message: fmt.Sprintf("Invalid proxy URL: %s", network.ProxyURL)}
+		}
+	}
+
+	// A client certificate needs its key, and the other way round
+	if (network.ClientCert == "") != (network.ClientKey == "") {
+		return ValidationError{Field: "network.client_cert", Message: "client_cert and client_key must be set together"}
+	}
+
+	if network.ConnectTimeout < 0 {
+		return ValidationError{Field: "network.connect_timeout", Message: "Timeout cannot be negative"}
+	}
+	if network.ResponseTimeout < 0 {
+		return ValidationError{Field: "network.response_timeout", Message: "Timeout cannot be negative"}
+	}
+	if network.RequestTimeout < 0 {
+		return ValidationError{Field: "network.request_timeout", Message: "Timeout cannot be negative"}
+	}
+
+	return nil
+}
+
 // IsConfigMissing checks if the configuration file is missing
 func IsConfigMissing() bool {
 	configPath := GetDefaultConfigPath()
@@ -0,0 +1,909 @@
+package jira
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ADFNode represents a node in an Atlassian Document Format tree
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"`
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Content []*ADFNode             `json:"content,omitempty"`
+	Text    string                 `json:"text,omitempty"`
+	Marks   []*ADFMark             `json:"marks,omitempty"`
+}
+
+// ADFMark represents formatting applied to an ADF text node
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// mediaRefPrefix marks markdown image sources that point at Jira media files
+const mediaRefPrefix = "media:"
+
+// mentionRefPrefix marks markdown link targets that represent user mentions
+const mentionRefPrefix = "accountid:"
+
+// markRank defines the canonical nesting order of marks (outermost first)
+var markRank = map[string]int{
+	"link":      0,
+	"strong":    1,
+	"em":        2,
+	"strike":    3,
+	"underline": 4,
+	"code":      5,
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])(\s+|$)(.*)$`)
+	taskPattern     = regexp.MustCompile(`^\[([ xX])\](\s+|$)(.*)$`)
+	rulePattern     = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	tableSepPattern = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+)
+
+// NewADFDocument creates an ADF document node with the given content
+func NewADFDocument(content ...*ADFNode) *ADFNode {
+	return &ADFNode{
+		Type:    "doc",
+		Version: 1,
+		Content: content,
+	}
+}
+
+// MarkdownToADF converts markdown text into an ADF document.
+// Returns nil for blank input so that empty descriptions are omitted.
+func MarkdownToADF(markdown string) *ADFNode {
+	markdown = strings.ReplaceAll(markdown, "\r\n", "\n")
+	if strings.TrimSpace(markdown) == "" {
+		return nil
+	}
+
+	p := &markdownParser{}
+	return NewADFDocument(p.parseBlocks(strings.Split(markdown, "\n"))...)
+}
+
+// markdownParser converts markdown into ADF nodes
+type markdownParser struct {
+	localIDs int
+}
+
+// nextLocalID returns a unique local ID for task list nodes
+func (p *markdownParser) nextLocalID() string {
+	p.localIDs++
+	return "jit-" + strconv.Itoa(p.localIDs)
+}
+
+// parseBlocks parses a sequence of lines into block nodes
+func (p *markdownParser) parseBlocks(lines []string) []*ADFNode {
+	var blocks []*ADFNode
+
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			i++
+		case isFence(trimmed):
+			var node *ADFNode
+			node, i = p.parseCodeBlock(lines, i)
+			blocks = append(blocks, node)
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, &ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": len(match[1])},
+				Content: imagesAsLinks(p.parseInline(match[2], nil)),
+			})
+			i++
+		case rulePattern.MatchString(trimmed):
+			blocks = append(blocks, &ADFNode{Type: "rule"})
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			var node *ADFNode
+			node, i = p.parseBlockquote(lines, i)
+			blocks = append(blocks, node)
+		case isTableStart(lines, i):
+			var node *ADFNode
+			node, i = p.parseTable(lines, i)
+			blocks = append(blocks, node)
+		case listItemPattern.MatchString(lines[i]):
+			var nodes []*ADFNode
+			nodes, i = p.parseList(lines, i)
+			blocks = append(blocks, nodes...)
+		default:
+			var nodes []*ADFNode
+			nodes, i = p.parseParagraph(lines, i)
+			blocks = append(blocks, nodes...)
+		}
+	}
+
+	return blocks
+}
+
+// parseCodeBlock parses a fenced code block starting at lines[start]
+func (p *markdownParser) parseCodeBlock(lines []string, start int) (*ADFNode, int) {
+	opening := strings.TrimSpace(lines[start])
+	fence := opening[:3]
+	language := strings.TrimSpace(strings.TrimLeft(opening, fence[:1]))
+	indent := leadingSpaces(lines[start])
+
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		code = append(code, trimIndent(lines[i], indent))
+	}
+
+	node := &ADFNode{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []*ADFNode{{Type: "text", Text: text}}
+	}
+
+	return node, i
+}
+
+// parseBlockquote parses consecutive quoted lines
+func (p *markdownParser) parseBlockquote(lines []string, start int) (*ADFNode, int) {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		trimmed = strings.TrimPrefix(trimmed, " ")
+		inner = append(inner, trimmed)
+	}
+
+	return &ADFNode{Type: "blockquote", Content: p.parseBlocks(inner)}, i
+}
+
+// parseTable parses a pipe table; the first row becomes the header row
+func (p *markdownParser) parseTable(lines []string, start int) (*ADFNode, int) {
+	header := splitTableRow(lines[start])
+	table := &ADFNode{Type: "table"}
+	table.Content = append(table.Content, p.tableRow(header, "tableHeader", len(header)))
+
+	i := start + 2
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || !strings.HasPrefix(trimmed, "|") {
+			break
+		}
+		table.Content = append(table.Content, p.tableRow(splitTableRow(trimmed), "tableCell", len(header)))
+	}
+
+	return table, i
+}
+
+// tableRow builds a table row with exactly columns cells
+func (p *markdownParser) tableRow(cells []string, cellType string, columns int) *ADFNode {
+	row := &ADFNode{Type: "tableRow"}
+	for c := 0; c < columns; c++ {
+		content := []*ADFNode{{Type: "paragraph"}}
+		if c < len(cells) {
+			content = splitMedia(p.parseInline(cells[c], nil))
+		}
+		row.Content = append(row.Content, &ADFNode{Type: cellType, Content: content})
+	}
+	return row
+}
+
+// listItem holds the raw lines of a single list item
+type listItem struct {
+	lines   []string
+	indent  int
+	ordinal int
+	task    bool
+	done    bool
+}
+
+// parseList parses a bullet, ordered or task list starting at lines[start].
+// A change of list kind at the same indentation starts a new list node.
+func (p *markdownParser) parseList(lines []string, start int) ([]*ADFNode, int) {
+	baseIndent := leadingSpaces(lines[start])
+	kind := listKind(lines[start])
+
+	var items []*listItem
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			next := nextNonBlank(lines, i)
+			if next < 0 || leadingSpaces(lines[next]) < baseIndent ||
+				(leadingSpaces(lines[next]) == baseIndent && listKind(lines[next]) != kind) {
+				break
+			}
+			if len(items) > 0 {
+				items[len(items)-1].lines = append(items[len(items)-1].lines, "")
+			}
+			i++
+			continue
+		}
+
+		indent := leadingSpaces(line)
+		if indent == baseIndent && listItemPattern.MatchString(line) {
+			if listKind(line) != kind {
+				break
+			}
+			items = append(items, newListItem(line))
+			i++
+			continue
+		}
+
+		if indent > baseIndent && len(items) > 0 {
+			item := items[len(items)-1]
+			item.lines = append(item.lines, trimIndent(line, item.indent))
+			i++
+			continue
+		}
+
+		break
+	}
+
+	switch kind {
+	case "task":
+		return []*ADFNode{p.buildTaskList(items)}, i
+	case "ordered":
+		list := &ADFNode{Type: "orderedList"}
+		if len(items) > 0 && items[0].ordinal != 1 {
+			list.Attrs = map[string]interface{}{"order": items[0].ordinal}
+		}
+		for _, item := range items {
+			list.Content = append(list.Content, p.buildListItem(item))
+		}
+		return []*ADFNode{list}, i
+	default:
+		list := &ADFNode{Type: "bulletList"}
+		for _, item := range items {
+			list.Content = append(list.Content, p.buildListItem(item))
+		}
+		return []*ADFNode{list}, i
+	}
+}
+
+// newListItem creates a list item from its marker line
+func newListItem(line string) *listItem {
+	match := listItemPattern.FindStringSubmatch(line)
+	item := &listItem{indent: len(match[1]) + len(match[2]) + 1}
+	text := match[4]
+
+	if marker := match[2]; marker[0] >= '0' && marker[0] <= '9' {
+		item.ordinal, _ = strconv.Atoi(marker[:len(marker)-1])
+	} else if task := taskPattern.FindStringSubmatch(text); task != nil {
+		item.task = true
+		item.done = task[1] != " "
+		text = task[3]
+	}
+
+	item.lines = []string{text}
+	return item
+}
+
+// buildListItem converts a bullet or ordered list item into ADF
+func (p *markdownParser) buildListItem(item *listItem) *ADFNode {
+	content := p.parseBlocks(item.lines)
+	if len(content) == 0 || content[0].Type != "paragraph" {
+		content = append([]*ADFNode{{Type: "paragraph"}}, content...)
+	}
+	return &ADFNode{Type: "listItem", Content: content}
+}
+
+// buildTaskList converts task items into an ADF task list.
+// Nested task lists become siblings of their parent item, as ADF requires.
+func (p *markdownParser) buildTaskList(items []*listItem) *ADFNode {
+	list := &ADFNode{
+		Type:  "taskList",
+		Attrs: map[string]interface{}{"localId": p.nextLocalID()},
+	}
+
+	for _, item := range items {
+		state := "TODO"
+		if item.done {
+			state = "DONE"
+		}
+		taskItem := &ADFNode{
+			Type:  "taskItem",
+			Attrs: map[string]interface{}{"localId": p.nextLocalID(), "state": state},
+		}
+		list.Content = append(list.Content, taskItem)
+
+		blocks := p.parseBlocks(item.lines)
+		for idx, block := range blocks {
+			switch {
+			case block.Type == "taskList":
+				list.Content = append(list.Content, block)
+			case block.Type == "paragraph":
+				if idx > 0 && len(taskItem.Content) > 0 {
+					taskItem.Content = append(taskItem.Content, &ADFNode{Type: "hardBreak"})
+				}
+				taskItem.Content = appendInline(taskItem.Content, block.Content...)
+			default:
+				if len(taskItem.Content) > 0 {
+					taskItem.Content = append(taskItem.Content, &ADFNode{Type: "hardBreak"})
+				}
+				taskItem.Content = appendInline(taskItem.Content, &ADFNode{Type: "text", Text: plainText(block)})
+			}
+		}
+	}
+
+	return list
+}
+
+// parseParagraph collects lines until a blank line or the start of another
+// block. Images in the paragraph are split out into blocks of their own.
+func (p *markdownParser) parseParagraph(lines []string, start int) ([]*ADFNode, int) {
+	var text []string
+	i := start
+	for ; i < len(lines); i++ {
+		if i > start && startsBlock(lines, i) {
+			break
+		}
+		line := strings.TrimLeft(lines[i], " ")
+		if line == "" {
+			break
+		}
+		text = append(text, strings.TrimRight(strings.TrimSuffix(line, "\\"), " "))
+	}
+
+	return splitMedia(p.parseInline(strings.Join(text, "\n"), nil)), i
+}
+
+// splitMedia turns inline content into blocks: paragraphs of the text, with
+// each image, which ADF only allows as a block, between them
+func splitMedia(inline []*ADFNode) []*ADFNode {
+	var blocks, text []*ADFNode
+	flush := func() {
+		if text = trimBreaks(text); len(text) > 0 {
+			blocks = append(blocks, &ADFNode{Type: "paragraph", Content: text})
+		}
+		text = nil
+	}
+
+	for _, node := range inline {
+		if node.Type != "mediaSingle" {
+			text = append(text, node)
+			continue
+		}
+		flush()
+		blocks = append(blocks, node)
+	}
+	flush()
+
+	if len(blocks) == 0 {
+		blocks = append(blocks, &ADFNode{Type: "paragraph"})
+	}
+	return blocks
+}
+
+// trimBreaks drops the line breaks and spaces left at either end of inline
+// content once an image is split out of it
+func trimBreaks(nodes []*ADFNode) []*ADFNode {
+	blank := func(node *ADFNode) bool {
+		return node.Type == "hardBreak" || (node.Type == "text" && strings.TrimSpace(node.Text) == "")
+	}
+	for len(nodes) > 0 && blank(nodes[0]) {
+		nodes = nodes[1:]
+	}
+	for len(nodes) > 0 && blank(nodes[len(nodes)-1]) {
+		nodes = nodes[:len(nodes)-1]
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	nodes = append([]*ADFNode(nil), nodes...)
+	if first := nodes[0]; first.Type == "text" {
+		nodes[0] = &ADFNode{Type: "text", Text: strings.TrimLeft(first.Text, " "), Marks: first.Marks}
+	}
+	if last := nodes[len(nodes)-1]; last.Type == "text" {
+		nodes[len(nodes)-1] = &ADFNode{Type: "text", Text: strings.TrimRight(last.Text, " "), Marks: last.Marks}
+	}
+	return nodes
+}
+
+// imagesAsLinks replaces images in content that only holds inline nodes,
+// such as headings, with their alt text linking to the image
+func imagesAsLinks(inline []*ADFNode) []*ADFNode {
+	var nodes []*ADFNode
+	for _, node := range inline {
+		if node.Type == "mediaSingle" {
+			media := node.Content[0]
+			href := attrString(media.Attrs, "url")
+			if attrString(media.Attrs, "type") == "file" {
+				href = mediaRef(attrString(media.Attrs, "collection"), attrString(media.Attrs, "id"))
+			}
+			link := &ADFMark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+			node = &ADFNode{Type: "text", Text: attrString(media.Attrs, "alt"), Marks: []*ADFMark{link}}
+		}
+		nodes = appendInline(nodes, node)
+	}
+	return nodes
+}
+
+// mediaRef returns the markdown image source of a Jira media file, naming
+// the collection it belongs to when that is known
+func mediaRef(collection, id string) string {
+	if collection == "" {
+		return mediaRefPrefix + id
+	}
+	return mediaRefPrefix + collection + "/" + id
+}
+
+// parseMediaRef splits a markdown image source made by mediaRef into the
+// file's collection and ID
+func parseMediaRef(src string) (collection, id string, ok bool) {
+	if !strings.HasPrefix(src, mediaRefPrefix) {
+		return "", "", false
+	}
+	ref := strings.TrimPrefix(src, mediaRefPrefix)
+	if slash := strings.LastIndex(ref, "/"); slash >= 0 {
+		return ref[:slash], ref[slash+1:], true
+	}
+	return "", ref, true
+}
+
+// mediaSingle builds a block-level image node. Jira media files keep their
+// collection; one that is not known is left out rather than sent empty.
+func mediaSingle(alt, src string) *ADFNode {
+	attrs := map[string]interface{}{}
+	if collection, id, ok := parseMediaRef(src); ok {
+		attrs["type"] = "file"
+		attrs["id"] = id
+		if collection != "" {
+			attrs["collection"] = collection
+		}
+	} else {
+		attrs["type"] = "external"
+		attrs["url"] = src
+	}
+	if alt != "" {
+		attrs["alt"] = alt
+	}
+
+	return &ADFNode{
+		Type:    "mediaSingle",
+		Attrs:   map[string]interface{}{"layout": "center"},
+		Content: []*ADFNode{{Type: "media", Attrs: attrs}},
+	}
+}
+
+// parseInline parses inline markdown into text nodes with marks
+func (p *markdownParser) parseInline(text string, marks []*ADFMark) []*ADFNode {
+	var nodes []*ADFNode
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = appendInline(nodes, &ADFNode{Type: "text", Text: buf.String(), Marks: marks})
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(text) && isASCIIPunct(text[i+1]):
+			buf.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '\n':
+			flush()
+			nodes = append(nodes, &ADFNode{Type: "hardBreak"})
+			i++
+			continue
+
+		case c == '`':
+			run := countRun(text, i, '`')
+			if end := findCodeClose(text, i+run, run); end >= 0 {
+				flush()
+				code := text[i+run : end]
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				nodes = appendInline(nodes, &ADFNode{Type: "text", Text: code, Marks: codeMarks(marks)})
+				i = end + run
+				continue
+			}
+			buf.WriteString(text[i : i+run])
+			i += run
+			continue
+
+		case strings.HasPrefix(rest, "**") || (strings.HasPrefix(rest, "__") && leftBoundary(text, i)):
+			if end := findEmphasisClose(text, i+2, rest[:2]); end > i+2 {
+				flush()
+				nodes = appendInline(nodes, p.parseInline(text[i+2:end], addMark(marks, &ADFMark{Type: "strong"}))...)
+				i = end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if end := findEmphasisClose(text, i+2, "~~"); end > i+2 {
+				flush()
+				nodes = appendInline(nodes, p.parseInline(text[i+2:end], addMark(marks, &ADFMark{Type: "strike"}))...)
+				i = end + 2
+				continue
+			}
+
+		case c == '*' || (c == '_' && leftBoundary(text, i)):
+			if end := findEmphasisClose(text, i+1, string(c)); end > i+1 {
+				flush()
+				nodes = appendInline(nodes, p.parseInline(text[i+1:end], addMark(marks, &ADFMark{Type: "em"}))...)
+				i = end + 1
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if label, dest, n, ok := parseLinkAt(text, i+1); ok {
+				flush()
+				nodes = append(nodes, mediaSingle(label, dest))
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if label, dest, n, ok := parseLinkAt(text, i); ok {
+				flush()
+				if strings.HasPrefix(dest, mentionRefPrefix) && strings.HasPrefix(label, "@") {
+					nodes = append(nodes, &ADFNode{
+						Type: "mention",
+						Attrs: map[string]interface{}{
+							"id":   strings.TrimPrefix(dest, mentionRefPrefix),
+							"text": label,
+						},
+					})
+				} else {
+					link := &ADFMark{Type: "link", Attrs: map[string]interface{}{"href": dest}}
+					nodes = appendInline(nodes, p.parseInline(label, addMark(marks, link))...)
+				}
+				i += n
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(rest, '>'); end > 1 {
+				target := rest[1:end]
+				if isAutolink(target) {
+					flush()
+					link := &ADFMark{Type: "link", Attrs: map[string]interface{}{"href": target}}
+					nodes = appendInline(nodes, &ADFNode{Type: "text", Text: target, Marks: addMark(marks, link)})
+					i += end + 1
+					continue
+				}
+			}
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+
+	flush()
+	return nodes
+}
+
+// appendInline appends inline nodes, merging adjacent text nodes with identical marks
+func appendInline(nodes []*ADFNode, add ...*ADFNode) []*ADFNode {
+	for _, node := range add {
+		if node.Type == "text" && node.Text == "" {
+			continue
+		}
+		if len(nodes) > 0 {
+			last := nodes[len(nodes)-1]
+			if last.Type == "text" && node.Type == "text" && marksEqual(last.Marks, node.Marks) {
+				nodes[len(nodes)-1] = &ADFNode{Type: "text", Text: last.Text + node.Text, Marks: last.Marks}
+				continue
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// addMark returns a copy of marks with mark inserted in canonical order
+func addMark(marks []*ADFMark, mark *ADFMark) []*ADFMark {
+	result := make([]*ADFMark, 0, len(marks)+1)
+	inserted := false
+	for _, existing := range marks {
+		if existing.Type == mark.Type && mark.Type != "link" {
+			return marks
+		}
+		if !inserted && markRank[mark.Type] < markRank[existing.Type] {
+			result = append(result, mark)
+			inserted = true
+		}
+		result = append(result, existing)
+	}
+	if !inserted {
+		result = append(result, mark)
+	}
+	return result
+}
+
+// codeMarks returns the marks for an inline code span; ADF only allows links alongside code
+func codeMarks(marks []*ADFMark) []*ADFMark {
+	var result []*ADFMark
+	for _, mark := range marks {
+		if mark.Type == "link" {
+			result = append(result, mark)
+		}
+	}
+	return append(result, &ADFMark{Type: "code"})
+}
+
+// marksEqual compares two mark lists
+func marksEqual(a, b []*ADFMark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !markEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// markEqual compares two marks by type and href
+func markEqual(a, b *ADFMark) bool {
+	return a.Type == b.Type && attrString(a.Attrs, "href") == attrString(b.Attrs, "href")
+}
+
+// parseLinkAt parses [label](dest) at text[start] and returns the consumed length
+func parseLinkAt(text string, start int) (string, string, int, bool) {
+	depth := 0
+	closeLabel := -1
+	for j := start; j < len(text); j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = j
+			}
+		}
+		if closeLabel >= 0 {
+			break
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+
+	depth = 0
+	for j := closeLabel + 1; j < len(text); j++ {
+		switch text[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				dest := strings.TrimSpace(text[closeLabel+2 : j])
+				if space := strings.IndexAny(dest, " \t"); space > 0 {
+					dest = dest[:space] // drop optional link title
+				}
+				return text[start+1 : closeLabel], dest, j - start + 1, true
+			}
+		}
+	}
+
+	return "", "", 0, false
+}
+
+// findEmphasisClose finds the closing delimiter for an emphasis span
+func findEmphasisClose(text string, from int, delim string) int {
+	if from >= len(text) || text[from] == ' ' {
+		return -1
+	}
+
+	for j := from; j < len(text); j++ {
+		switch {
+		case text[j] == '\\':
+			j++
+		case text[j] == '`':
+			run := countRun(text, j, '`')
+			if end := findCodeClose(text, j+run, run); end >= 0 {
+				j = end + run - 1
+			}
+		case text[j] == '[':
+			if _, _, n, ok := parseLinkAt(text, j); ok {
+				j += n - 1
+			}
+		case strings.HasPrefix(text[j:], delim):
+			if delim == "*" && strings.HasPrefix(text[j:], "**") {
+				j++
+				continue
+			}
+			if text[j-1] == ' ' {
+				continue
+			}
+			if delim[0] == '_' && j+len(delim) < len(text) && isAlphaNum(text[j+len(delim)]) {
+				continue
+			}
+			return j
+		}
+	}
+
+	return -1
+}
+
+// findCodeClose finds a closing backtick run of exactly run characters
+func findCodeClose(text string, from, run int) int {
+	for j := from; j < len(text); j++ {
+		if text[j] == '`' {
+			n := countRun(text, j, '`')
+			if n == run {
+				return j
+			}
+			j += n - 1
+		}
+	}
+	return -1
+}
+
+// countRun counts consecutive occurrences of c starting at text[i]
+func countRun(text string, i int, c byte) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// leftBoundary reports whether position i is not preceded by a word character
+func leftBoundary(text string, i int) bool {
+	return i == 0 || !isAlphaNum(text[i-1])
+}
+
+// isAlphaNum reports whether c is an ASCII letter or digit
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// isASCIIPunct reports whether c is ASCII punctuation and can be backslash-escaped
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// isAutolink reports whether target is a URL suitable for <url> autolinks
+func isAutolink(target string) bool {
+	return (strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") ||
+		strings.HasPrefix(target, "mailto:")) && !strings.ContainsAny(target, " <")
+}
+
+// isFence reports whether a trimmed line opens or closes a fenced code block
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// isTableStart reports whether lines[i] starts a pipe table
+func isTableStart(lines []string, i int) bool {
+	if i+1 >= len(lines) {
+		return false
+	}
+	header := strings.TrimSpace(lines[i])
+	separator := strings.TrimSpace(lines[i+1])
+	return strings.HasPrefix(header, "|") && strings.Contains(separator, "-") && tableSepPattern.MatchString(separator)
+}
+
+// startsBlock reports whether lines[i] starts a non-paragraph block
+func startsBlock(lines []string, i int) bool {
+	trimmed := strings.TrimSpace(lines[i])
+	return isFence(trimmed) || headingPattern.MatchString(trimmed) || rulePattern.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, ">") || isTableStart(lines, i) || listItemPattern.MatchString(lines[i])
+}
+
+// splitTableRow splits a pipe table row into trimmed cell strings
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, "\\|") {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		if row[i] == '\\' && i+1 < len(row) && row[i+1] == '|' {
+			cell.WriteString("\\|")
+			i++
+			continue
+		}
+		if row[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+			continue
+		}
+		cell.WriteByte(row[i])
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// listKind classifies a list item line as bullet, ordered or task
+func listKind(line string) string {
+	match := listItemPattern.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+	if match[2][0] >= '0' && match[2][0] <= '9' {
+		return "ordered"
+	}
+	if taskPattern.MatchString(match[4]) {
+		return "task"
+	}
+	return "bullet"
+}
+
+// leadingSpaces counts the leading spaces of a line
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent removes up to n leading spaces from a line
+func trimIndent(line string, n int) string {
+	spaces := leadingSpaces(line)
+	if spaces > n {
+		spaces = n
+	}
+	return line[spaces:]
+}
+
+// nextNonBlank returns the index of the next non-blank line after i, or -1
+func nextNonBlank(lines []string, i int) int {
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) != "" {
+			return j
+		}
+	}
+	return -1
+}
+
+// attrString returns a string attribute or an empty string
+func attrString(attrs map[string]interface{}, key string) string {
+	if value, ok := attrs[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+// attrInt returns an integer attribute, accepting both decoded JSON numbers and ints
+func attrInt(attrs map[string]interface{}, key string, fallback int) int {
+	switch value := attrs[key].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	default:
+		return fallback
+	}
+}
+
+// plainText returns the concatenated text content of a node
+func plainText(node *ADFNode) string {
+	if node.Type == "text" {
+		return node.Text
+	}
+	var parts []string
+	for _, child := range node.Content {
+		parts = append(parts, plainText(child))
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,424 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lineEscapePattern matches line starts that would otherwise be read as block syntax
+var lineEscapePattern = regexp.MustCompile(`^(\s*)(#|>|[-+](\s|$)|\d+[.)](\s|$)|\|)`)
+
+// ADFToMarkdown converts an ADF document (or any ADF node) into markdown
+func ADFToMarkdown(node *ADFNode) string {
+	if node == nil {
+		return ""
+	}
+
+	r := &markdownRenderer{}
+	if node.Type == "doc" {
+		return strings.TrimSpace(r.renderBlocks(node.Content))
+	}
+	return strings.TrimSpace(r.renderBlock(node))
+}
+
+// markdownRenderer renders ADF nodes as markdown
+type markdownRenderer struct{}
+
+// renderBlocks renders block nodes separated by blank lines
+func (r *markdownRenderer) renderBlocks(nodes []*ADFNode) string {
+	var blocks []string
+	for _, node := range nodes {
+		if rendered := r.renderBlock(node); rendered != "" {
+			blocks = append(blocks, rendered)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderBlock renders a single block node
+func (r *markdownRenderer) renderBlock(node *ADFNode) string {
+	switch node.Type {
+	case "paragraph":
+		return escapeLineStarts(r.renderInline(node.Content))
+	case "heading":
+		level := attrInt(node.Attrs, "level", 1)
+		return strings.Repeat("#", level) + " " + r.renderInline(node.Content)
+	case "codeBlock":
+		return renderCodeBlock(plainText(node), attrString(node.Attrs, "language"))
+	case "blockquote", "panel":
+		return prefixLines(r.renderBlocks(node.Content), "> ")
+	case "rule":
+		return "---"
+	case "bulletList":
+		return r.renderList(node, func(int) string { return "- " })
+	case "orderedList":
+		start := attrInt(node.Attrs, "order", 1)
+		return r.renderList(node, func(i int) string { return fmt.Sprintf("%d. ", start+i) })
+	case "taskList":
+		return r.renderTaskList(node)
+	case "decisionList":
+		var items []string
+		for _, item := range node.Content {
+			items = append(items, "- "+r.renderInline(item.Content))
+		}
+		return strings.Join(items, "\n")
+	case "table":
+		return r.renderTable(node)
+	case "mediaSingle", "mediaGroup":
+		var media []string
+		for _, child := range node.Content {
+			media = append(media, renderMedia(child))
+		}
+		return strings.Join(media, "\n\n")
+	case "media":
+		return renderMedia(node)
+	case "expand", "nestedExpand":
+		title := attrString(node.Attrs, "title")
+		content := r.renderBlocks(node.Content)
+		if title == "" {
+			return content
+		}
+		return "**" + escapeMarkdown(title) + "**\n\n" + content
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return r.renderInline([]*ADFNode{node})
+	default:
+		return r.renderBlocks(node.Content)
+	}
+}
+
+// renderList renders bullet and ordered lists
+func (r *markdownRenderer) renderList(node *ADFNode, marker func(int) string) string {
+	var items []string
+	for i, item := range node.Content {
+		prefix := marker(i)
+		var parts []string
+		for j, child := range item.Content {
+			rendered := r.renderBlock(child)
+			if j > 0 && !isListNode(child) {
+				rendered = "\n" + rendered
+			}
+			parts = append(parts, rendered)
+		}
+		body := strings.Join(parts, "\n")
+		items = append(items, prefix+indentLines(body, len(prefix)))
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderTaskList renders task lists using GitHub-style checkboxes
+func (r *markdownRenderer) renderTaskList(node *ADFNode) string {
+	var items []string
+	for _, item := range node.Content {
+		switch item.Type {
+		case "taskItem":
+			box := "[ ]"
+			if attrString(item.Attrs, "state") == "DONE" {
+				box = "[x]"
+			}
+			items = append(items, "- "+box+" "+indentLines(r.renderInline(item.Content), 2))
+		case "taskList":
+			items = append(items, "  "+indentLines(r.renderTaskList(item), 2))
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+// renderTable renders an ADF table as a pipe table; the first row is used as header
+func (r *markdownRenderer) renderTable(node *ADFNode) string {
+	var rows [][]string
+	columns := 0
+	for _, row := range node.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			var parts []string
+			for _, child := range cell.Content {
+				parts = append(parts, r.renderInline(inlineContent(child)))
+			}
+			text := strings.ReplaceAll(strings.Join(parts, " "), "\n", " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", "\\|"))
+		}
+		if len(cells) > columns {
+			columns = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var lines []string
+	for i, cells := range rows {
+		for len(cells) < columns {
+			cells = append(cells, "")
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			separators := make([]string, columns)
+			for c := range separators {
+				separators[c] = "---"
+			}
+			lines = append(lines, "| "+strings.Join(separators, " | ")+" |")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderInline renders inline nodes, opening and closing marks as a stack
+// so that adjacent text nodes sharing formatting produce a single span.
+func (r *markdownRenderer) renderInline(nodes []*ADFNode) string {
+	var out []byte
+	var open []*ADFMark
+
+	closeTo := func(depth int) {
+		for len(open) > depth {
+			mark := open[len(open)-1]
+			open = open[:len(open)-1]
+
+			trailing := len(out) - len(strings.TrimRight(string(out), " "))
+			out = out[:len(out)-trailing]
+			out = append(out, markCloser(mark)...)
+			out = append(out, strings.Repeat(" ", trailing)...)
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Type == "text" && node.Text == "" {
+			continue
+		}
+
+		marks := stackMarks(node)
+		common := 0
+		for common < len(open) && common < len(marks) && markEqual(open[common], marks[common]) {
+			common++
+		}
+		closeTo(common)
+
+		text := node.Text
+		if common < len(marks) && node.Type == "text" {
+			leading := len(text) - len(strings.TrimLeft(text, " "))
+			out = append(out, text[:leading]...)
+			text = text[leading:]
+		}
+		for _, mark := range marks[common:] {
+			out = append(out, markOpener(mark)...)
+			open = append(open, mark)
+		}
+
+		switch node.Type {
+		case "text":
+			if hasMark(node, "code") {
+				out = append(out, codeSpan(text)...)
+			} else {
+				out = append(out, escapeMarkdown(text)...)
+			}
+		case "hardBreak":
+			out = append(out, '\n')
+		case "mention":
+			label := attrString(node.Attrs, "text")
+			if !strings.HasPrefix(label, "@") {
+				label = "@" + label
+			}
+			out = append(out, "["+label+"]("+mentionRefPrefix+attrString(node.Attrs, "id")+")"...)
+		case "emoji":
+			if text := attrString(node.Attrs, "text"); text != "" {
+				out = append(out, text...)
+			} else {
+				out = append(out, attrString(node.Attrs, "shortName")...)
+			}
+		case "inlineCard", "blockCard":
+			out = append(out, "<"+attrString(node.Attrs, "url")+">"...)
+		case "status":
+			out = append(out, "`"+attrString(node.Attrs, "text")+"`"...)
+		case "date":
+			out = append(out, renderDate(attrString(node.Attrs, "timestamp"))...)
+		default:
+			out = append(out, r.renderInline(node.Content)...)
+		}
+	}
+	closeTo(0)
+
+	return string(out)
+}
+
+// stackMarks returns the marks of a node that are rendered via the mark stack,
+// in canonical order. Code is rendered directly on the text and excluded.
+func stackMarks(node *ADFNode) []*ADFMark {
+	var marks []*ADFMark
+	for _, mark := range node.Marks {
+		if _, known := markRank[mark.Type]; known && mark.Type != "code" {
+			marks = append(marks, mark)
+		}
+	}
+	sort.SliceStable(marks, func(i, j int) bool {
+		return markRank[marks[i].Type] < markRank[marks[j].Type]
+	})
+	return marks
+}
+
+// markOpener returns the markdown that opens a mark
+func markOpener(mark *ADFMark) string {
+	switch mark.Type {
+	case "link":
+		return "["
+	case "strong":
+		return "**"
+	case "em":
+		return "_"
+	case "strike":
+		return "~~"
+	}
+	return ""
+}
+
+// markCloser returns the markdown that closes a mark
+func markCloser(mark *ADFMark) string {
+	switch mark.Type {
+	case "link":
+		return "](" + attrString(mark.Attrs, "href") + ")"
+	case "strong":
+		return "**"
+	case "em":
+		return "_"
+	case "strike":
+		return "~~"
+	}
+	return ""
+}
+
+// hasMark reports whether a node carries a mark of the given type
+func hasMark(node *ADFNode, markType string) bool {
+	for _, mark := range node.Marks {
+		if mark.Type == markType {
+			return true
+		}
+	}
+	return false
+}
+
+// isListNode reports whether a node is a list block
+func isListNode(node *ADFNode) bool {
+	return node.Type == "bulletList" || node.Type == "orderedList" || node.Type == "taskList"
+}
+
+// inlineContent returns the inline children of a block, or the node itself if inline
+func inlineContent(node *ADFNode) []*ADFNode {
+	switch node.Type {
+	case "paragraph", "heading":
+		return node.Content
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return []*ADFNode{node}
+	default:
+		return []*ADFNode{{Type: "text", Text: plainText(node)}}
+	}
+}
+
+// renderMedia renders a media node as a markdown image
+func renderMedia(node *ADFNode) string {
+	alt := attrString(node.Attrs, "alt")
+	if attrString(node.Attrs, "type") == "external" {
+		return "![" + alt + "](" + attrString(node.Attrs, "url") + ")"
+	}
+	return "![" + alt + "](" + mediaRef(attrString(node.Attrs, "collection"), attrString(node.Attrs, "id")) + ")"
+}
+
+// renderCodeBlock renders a fenced code block, choosing a fence that does not clash with the code
+func renderCodeBlock(code, language string) string {
+	fence := "```"
+	if strings.Contains(code, "```") {
+		fence = "~~~"
+	}
+	return fence + language + "\n" + code + "\n" + fence
+}
+
+// renderDate renders an ADF date timestamp (milliseconds since epoch) as YYYY-MM-DD
+func renderDate(timestamp string) string {
+	var millis int64
+	if _, err := fmt.Sscanf(timestamp, "%d", &millis); err != nil {
+		return timestamp
+	}
+	return time.UnixMilli(millis).UTC().Format("2006-01-02")
+}
+
+// codeSpan wraps text in backticks, using a longer run if the text contains backticks
+func codeSpan(text string) string {
+	longest := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '`' {
+			if n := countRun(text, i, '`'); n > longest {
+				longest = n
+			}
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if longest > 0 {
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
+
+// escapeMarkdown escapes characters that the inline parser would treat as syntax
+func escapeMarkdown(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' || c == '*' || c == '`' || c == '[' || c == ']':
+			b.WriteByte('\\')
+		case c == '~' && i+1 < len(text) && text[i+1] == '~':
+			b.WriteByte('\\')
+		case c == '_' && (leftBoundary(text, i) || i+1 >= len(text) || !isAlphaNum(text[i+1])):
+			b.WriteByte('\\')
+		case c == '<' && isAutolinkStart(text[i+1:]):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// isAutolinkStart reports whether text begins like an autolink target
+func isAutolinkStart(text string) bool {
+	return strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://") || strings.HasPrefix(text, "mailto:")
+}
+
+// escapeLineStarts escapes paragraph lines that would otherwise parse as block syntax
+func escapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if match := lineEscapePattern.FindStringSubmatchIndex(line); match != nil {
+			lines[i] = line[:match[3]] + "\\" + line[match[3]:]
+		}
+		if trimmed := strings.TrimSpace(line); rulePattern.MatchString(trimmed) || isFence(trimmed) {
+			lines[i] = "\\" + strings.TrimLeft(line, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines indents every line after the first by n spaces
+func indentLines(text string, n int) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = pad + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixLines prefixes every line with prefix
+func prefixLines(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,216 @@
+package jira
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// normalizeADF converts a node into generic JSON with local IDs removed,
+// since those are regenerated on every conversion.
+func normalizeADF(t *testing.T, node *ADFNode) interface{} {
+	t.Helper()
+
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatalf("Failed to marshal ADF: %v", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("Failed to unmarshal ADF: %v", err)
+	}
+
+	var strip func(v interface{})
+	strip = func(v interface{}) {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			delete(value, "localId")
+			if attrs, ok := value["attrs"].(map[string]interface{}); ok && len(attrs) == 0 {
+				delete(value, "attrs")
+			}
+			for _, child := range value {
+				strip(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				strip(child)
+			}
+		}
+	}
+	strip(generic)
+
+	return generic
+}
+
+func loadADFFixture(t *testing.T, path string) *ADFNode {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", path, err)
+	}
+
+	var doc ADFNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to decode fixture %s: %v", path, err)
+	}
+	return &doc
+}
+
+func TestADFFixtureRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "adf", "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("Expected ADF fixtures in testdata/adf")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			doc := loadADFFixture(t, fixture)
+
+			markdown := ADFToMarkdown(doc)
+			roundTripped := MarkdownToADF(markdown)
+
+			want := normalizeADF(t, doc)
+			got := normalizeADF(t, roundTripped)
+			if !reflect.DeepEqual(want, got) {
+				gotJSON, _ := json.MarshalIndent(got, "", "  ")
+				t.Errorf("ADF round trip mismatch\nmarkdown:\n%s\n\ngot:\n%s", markdown, gotJSON)
+			}
+
+			// Markdown produced from ADF must be stable across another round trip
+			if again := ADFToMarkdown(roundTripped); again != markdown {
+				t.Errorf("Markdown not stable\nfirst:\n%s\n\nsecond:\n%s", markdown, again)
+			}
+		})
+	}
+}
+
+func TestMarkdownToADF(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "heading",
+			markdown: "## Acceptance Criteria",
+			expected: `{"type":"doc","version":1,"content":[{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"Acceptance Criteria"}]}]}`,
+		},
+		{
+			name:     "paragraph with line break",
+			markdown: "line one\nline two",
+			expected: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"line one"},{"type":"hardBreak"},{"type":"text","text":"line two"}]}]}`,
+		},
+		{
+			name:     "task list",
+			markdown: "- [ ] todo\n- [x] done",
+			expected: `{"type":"doc","version":1,"content":[{"type":"taskList","attrs":{"localId":"jit-1"},"content":[{"type":"taskItem","attrs":{"localId":"jit-2","state":"TODO"},"content":[{"type":"text","text":"todo"}]},{"type":"taskItem","attrs":{"localId":"jit-3","state":"DONE"},"content":[{"type":"text","text":"done"}]}]}]}`,
+		},
+		{
+			name:     "code block keeps content verbatim",
+			markdown: "```sh\necho **hi**\n```",
+			expected: `{"type":"doc","version":1,"content":[{"type":"codeBlock","attrs":{"language":"sh"},"content":[{"type":"text","text":"echo **hi**"}]}]}`,
+		},
+		{
+			name:     "mention",
+			markdown: "cc [@Jane](accountid:123)",
+			expected: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"cc "},{"type":"mention","attrs":{"id":"123","text":"@Jane"}}]}]}`,
+		},
+		{
+			name:     "autolink",
+			markdown: "<https://example.com>",
+			expected: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"https://example.com","marks":[{"type":"link","attrs":{"href":"https://example.com"}}]}]}]}`,
+		},
+		{
+			name:     "inline images become media blocks",
+			markdown: "See ![shot](media:jira-10001/abc-123) and ![logo](https://example.com/logo.png)\nbelow",
+			expected: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"See"}]},{"type":"mediaSingle","attrs":{"layout":"center"},"content":[{"type":"media","attrs":{"alt":"shot","collection":"jira-10001","id":"abc-123","type":"file"}}]},{"type":"paragraph","content":[{"type":"text","text":"and"}]},{"type":"mediaSingle","attrs":{"layout":"center"},"content":[{"type":"media","attrs":{"alt":"logo","type":"external","url":"https://example.com/logo.png"}}]},{"type":"paragraph","content":[{"type":"text","text":"below"}]}]}`,
+		},
+		{
+			name:     "media without a known collection",
+			markdown: "![](media:abc-123)",
+			expected: `{"type":"doc","version":1,"content":[{"type":"mediaSingle","attrs":{"layout":"center"},"content":[{"type":"media","attrs":{"id":"abc-123","type":"file"}}]}]}`,
+		},
+		{
+			name:     "intra-word underscores are literal",
+			markdown: "use my_var_name",
+			expected: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"use my_var_name"}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(MarkdownToADF(tt.markdown))
+			if err != nil {
+				t.Fatalf("Failed to marshal ADF: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("MarkdownToADF(%q)\ngot:  %s\nwant: %s", tt.markdown, data, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownImagesRoundTrip(t *testing.T) {
+	markdown := "See ![shot](media:jira-10001/abc-123) and ![logo](https://example.com/logo.png) below"
+
+	expected := "See\n\n![shot](media:jira-10001/abc-123)\n\nand\n\n![logo](https://example.com/logo.png)\n\nbelow"
+	once := ADFToMarkdown(MarkdownToADF(markdown))
+	if once != expected {
+		t.Errorf("Unexpected markdown:\n%s\nwant:\n%s", once, expected)
+	}
+	if again := ADFToMarkdown(MarkdownToADF(once)); again != once {
+		t.Errorf("Markdown not stable\nfirst:\n%s\n\nsecond:\n%s", once, again)
+	}
+}
+
+func TestMarkdownToADFEmpty(t *testing.T) {
+	if doc := MarkdownToADF("  \n\n "); doc != nil {
+		t.Errorf("Expected nil document for blank markdown, got %+v", doc)
+	}
+}
+
+func TestADFToMarkdownJiraNodes(t *testing.T) {
+	doc := NewADFDocument(
+		&ADFNode{Type: "panel", Attrs: map[string]interface{}{"panelType": "info"}, Content: []*ADFNode{
+			{Type: "paragraph", Content: []*ADFNode{{Type: "text", Text: "Heads up"}}},
+		}},
+		&ADFNode{Type: "paragraph", Content: []*ADFNode{
+			{Type: "status", Attrs: map[string]interface{}{"text": "IN REVIEW"}},
+			{Type: "text", Text: " "},
+			{Type: "emoji", Attrs: map[string]interface{}{"shortName": ":tada:"}},
+			{Type: "text", Text: " "},
+			{Type: "inlineCard", Attrs: map[string]interface{}{"url": "https://example.com/pr/1"}},
+		}},
+	)
+
+	expected := "> Heads up\n\n`IN REVIEW` :tada: <https://example.com/pr/1>"
+	if markdown := ADFToMarkdown(doc); markdown != expected {
+		t.Errorf("ADFToMarkdown()\ngot:\n%s\nwant:\n%s", markdown, expected)
+	}
+}
+
+func TestADFToMarkdownSplitMarks(t *testing.T) {
+	// Jira frequently splits formatted runs into several text nodes
+	doc := NewADFDocument(&ADFNode{Type: "paragraph", Content: []*ADFNode{
+		{Type: "text", Text: "very ", Marks: []*ADFMark{{Type: "strong"}}},
+		{Type: "text", Text: "important", Marks: []*ADFMark{{Type: "strong"}, {Type: "em"}}},
+		{Type: "text", Text: " note"},
+	}})
+
+	markdown := ADFToMarkdown(doc)
+	if markdown != "**very _important_** note" {
+		t.Errorf("Unexpected markdown: %s", markdown)
+	}
+
+	if !strings.Contains(ADFToMarkdown(MarkdownToADF(markdown)), "_important_") {
+		t.Errorf("Expected emphasis to survive a round trip")
+	}
+}
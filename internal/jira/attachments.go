@@ -0,0 +1,311 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// markdownImagePattern matches markdown images anywhere in a line
+var markdownImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+
+// mediaFilePattern extracts the file ID from an Atlassian media URL
+var mediaFilePattern = regexp.MustCompile(`/file/([0-9a-fA-F-]{36})/`)
+
+// AddAttachment uploads a file to an issue
+func (c *Client) AddAttachment(ctx context.Context, issueKey, filename string, content io.Reader) ([]JiraAttachment, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create form file: %v", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", filename, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish upload: %v", err)
+	}
+
+	// Jira rejects uploads without the XSRF opt-out header
+	header := http.Header{}
+	header.Set("Content-Type", writer.FormDataContentType())
+	header.Set("X-Atlassian-Token", "no-check")
+
+	endpoint := fmt.Sprintf("/issue/%s/attachments", issueKey)
+	resp, err := c.doRequestWithHeaders(ctx, "POST", endpoint, bytes.NewReader(body.Bytes()), header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var attachments []JiraAttachment
+	if err := json.NewDecoder(resp.Body).Decode(&attachments); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachments lists the files attached to an issue
+func (c *Client) GetAttachments(ctx context.Context, issueKey string) ([]JiraAttachment, error) {
+	endpoint := fmt.Sprintf("/issue/%s?fields=attachment", issueKey)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var issue JiraIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return issue.Fields.Attachments, nil
+}
+
+// DownloadAttachment writes an attachment's content to w
+func (c *Client) DownloadAttachment(ctx context.Context, attachment *JiraAttachment, w io.Writer) error {
+	resp, err := c.getAttachmentContent(ctx, attachment)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return c.parseErrorResponse(resp)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %v", attachment.Filename, err)
+	}
+
+	return nil
+}
+
+// getAttachmentContent requests an attachment's content. Cloud redirects the
+// content endpoint to the media store; Server and Data Center serve the
+// attachment's own URL, which lies outside the REST API.
+func (c *Client) getAttachmentContent(ctx context.Context, attachment *JiraAttachment) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", "*/*")
+
+	if c.Flavor(ctx) == FlavorServer && attachment.Content != "" {
+		return c.send(ctx, "GET", attachment.Content, nil, c.authorization(ctx), header)
+	}
+	return c.doRequestWithHeaders(ctx, "GET", fmt.Sprintf("/attachment/content/%s", attachment.ID), nil, header)
+}
+
+// mediaReference returns the markdown image source that embeds an
+// attachment. Cloud descriptions embed media by the file's ID and collection
+// in the media store, which Jira only reveals through the content redirect;
+// wiki markup embeds attachments by filename.
+func (c *Client) mediaReference(ctx context.Context, attachment *JiraAttachment) (string, error) {
+	if c.Flavor(ctx) == FlavorServer {
+		return mediaRefPrefix + attachment.Filename, nil
+	}
+
+	resp, err := c.getAttachmentContent(ctx, attachment)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", c.parseErrorResponse(resp)
+	}
+
+	match := mediaFilePattern.FindStringSubmatch(resp.Request.URL.Path)
+	if match == nil {
+		return "", fmt.Errorf("attachment %s has no media file ID", attachment.Filename)
+	}
+
+	return mediaRef(resp.Request.URL.Query().Get("collection"), match[1]), nil
+}
+
+// AttachFile uploads a local file to a ticket
+func (ts *TicketService) AttachFile(ctx context.Context, ticketKey, path string) (*types.Attachment, error) {
+	jiraAttachment, err := ts.attachFile(ctx, ticketKey, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertAttachment(ticketKey, jiraAttachment), nil
+}
+
+// attachFile uploads a local file and returns Jira's record of it
+func (ts *TicketService) attachFile(ctx context.Context, ticketKey, path string) (*JiraAttachment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	attachments, err := ts.client.AddAttachment(ctx, ticketKey, filepath.Base(path), file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach %s to %s: %v", filepath.Base(path), ticketKey, err)
+	}
+	if len(attachments) == 0 {
+		return nil, fmt.Errorf("Jira did not return the attachment for %s", filepath.Base(path))
+	}
+
+	return &attachments[0], nil
+}
+
+// GetAttachments lists the files attached to a ticket
+func (ts *TicketService) GetAttachments(ctx context.Context, ticketKey string) ([]*types.Attachment, error) {
+	jiraAttachments, err := ts.client.GetAttachments(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attachments for %s: %v", ticketKey, err)
+	}
+
+	attachments := make([]*types.Attachment, 0, len(jiraAttachments))
+	for i := range jiraAttachments {
+		attachments = append(attachments, convertAttachment(ticketKey, &jiraAttachments[i]))
+	}
+
+	return attachments, nil
+}
+
+// DownloadAttachments saves all of a ticket's attachments into dir and
+// returns the paths written. Existing files are overwritten.
+func (ts *TicketService) DownloadAttachments(ctx context.Context, ticketKey, dir string) ([]string, error) {
+	jiraAttachments, err := ts.client.GetAttachments(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attachments for %s: %v", ticketKey, err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %v", dir, err)
+	}
+
+	var paths []string
+	for i := range jiraAttachments {
+		attachment := &jiraAttachments[i]
+
+		// Never let a filename from Jira escape dir
+		path := filepath.Join(dir, filepath.Base(attachment.Filename))
+		if err := ts.downloadAttachment(ctx, attachment, path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// downloadAttachment writes one attachment to path
+func (ts *TicketService) downloadAttachment(ctx context.Context, attachment *JiraAttachment, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+
+	if err := ts.client.DownloadAttachment(ctx, attachment, file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+
+	return file.Close()
+}
+
+// UploadMarkdownImages uploads the local images referenced in markdown to a
+// ticket and rewrites them as Jira media references. Relative paths are
+// resolved against baseDir; remote images and missing files are left alone.
+// It returns the rewritten markdown and the paths uploaded.
+func (ts *TicketService) UploadMarkdownImages(ctx context.Context, ticketKey, markdown, baseDir string) (string, []string, error) {
+	references := make(map[string]string)
+	var uploaded []string
+	var uploadErr error
+
+	rewritten := markdownImagePattern.ReplaceAllStringFunc(markdown, func(image string) string {
+		match := markdownImagePattern.FindStringSubmatch(image)
+		path := localImagePath(match[2], baseDir)
+		if path == "" || uploadErr != nil {
+			return image
+		}
+
+		// Upload each file once, however often it is referenced
+		if reference, ok := references[path]; ok {
+			return fmt.Sprintf("![%s](%s)", filepath.Base(path), reference)
+		}
+
+		attachment, err := ts.attachFile(ctx, ticketKey, path)
+		if err != nil {
+			uploadErr = err
+			return image
+		}
+		reference, err := ts.client.mediaReference(ctx, attachment)
+		if err != nil {
+			uploadErr = fmt.Errorf("failed to embed %s: %v", attachment.Filename, err)
+			return image
+		}
+
+		references[path] = reference
+		uploaded = append(uploaded, path)
+
+		// Jira names embedded media by filename
+		return fmt.Sprintf("![%s](%s)", attachment.Filename, reference)
+	})
+
+	if uploadErr != nil {
+		return markdown, uploaded, uploadErr
+	}
+	return rewritten, uploaded, nil
+}
+
+// localImagePath returns the file an image source names on disk, or "" when
+// it is remote, already in Jira or missing
+func localImagePath(src, baseDir string) string {
+	if strings.Contains(src, "://") || strings.HasPrefix(src, mediaRefPrefix) || strings.HasPrefix(src, "data:") {
+		return ""
+	}
+
+	path := strings.TrimPrefix(src, "file:")
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// convertAttachment converts a Jira attachment to our internal format
+func convertAttachment(ticketKey string, jiraAttachment *JiraAttachment) *types.Attachment {
+	return &types.Attachment{
+		ID:        jiraAttachment.ID,
+		TicketKey: ticketKey,
+		Filename:  jiraAttachment.Filename,
+		Size:      jiraAttachment.Size,
+		MimeType:  jiraAttachment.MimeType,
+		Author:    jiraAttachment.Author.DisplayName,
+		Created:   jiraAttachment.Created.Time,
+	}
+}
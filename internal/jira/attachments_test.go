@@ -0,0 +1,170 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+const (
+	testMediaID         = "6f1c2a9e-1b2c-4d5e-8f90-0123456789ab"
+	testMediaCollection = "jira-10001"
+)
+
+// newAttachmentServer accepts uploads to TEST-1 and serves their content
+// the way Cloud does, by redirecting to the media store
+func newAttachmentServer(t *testing.T, uploads *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/issue/TEST-1/attachments"):
+			if token := r.Header.Get("X-Atlassian-Token"); token != "no-check" {
+				t.Errorf("Expected X-Atlassian-Token no-check, got %q", token)
+			}
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("Expected multipart file: %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			*uploads = append(*uploads, header.Filename+"="+string(content))
+			fmt.Fprintf(w, `[{"id": "%d", "filename": %q, "size": %d, "content": "%s/secure/attachment/%d/%s"}]`,
+				len(*uploads), header.Filename, len(content), "http://"+r.Host, len(*uploads), header.Filename)
+		case r.URL.Path == "/rest/api/3/attachment/content/1":
+			http.Redirect(w, r, "/file/"+testMediaID+"/binary?token=abc&collection="+testMediaCollection, http.StatusSeeOther)
+		case strings.HasPrefix(r.URL.Path, "/file/"):
+			io.WriteString(w, "binary")
+		case r.URL.Path == "/rest/api/3/issue/TEST-1":
+			fmt.Fprintf(w, `{"key": "TEST-1", "fields": {"attachment": [
+				{"id": "1", "filename": "shot.png", "size": 6, "author": {"displayName": "Dev"}, "created": "2024-03-01T08:30:00.000+0000"},
+				{"id": "1", "filename": "../escape.png", "size": 6}
+			]}}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestAttachFile(t *testing.T) {
+	var uploads []string
+	server := newAttachmentServer(t, &uploads)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+	attachment, err := service.AttachFile(context.Background(), "TEST-1", path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(uploads) != 1 || uploads[0] != "notes.txt=hello" {
+		t.Errorf("Unexpected uploads: %v", uploads)
+	}
+	if attachment.Filename != "notes.txt" || attachment.Size != 5 || attachment.TicketKey != "TEST-1" {
+		t.Errorf("Unexpected attachment: %+v", attachment)
+	}
+}
+
+func TestUploadMarkdownImages(t *testing.T) {
+	var uploads []string
+	server := newAttachmentServer(t, &uploads)
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shot.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown := "Before:\n\n![screenshot](shot.png)\n\nSee ![again](./shot.png), ![logo](https://example.com/logo.png) and ![gone](missing.png)."
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+	rewritten, uploaded, err := service.UploadMarkdownImages(context.Background(), "TEST-1", markdown, dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The same file is uploaded once however often it is referenced
+	if len(uploads) != 1 || len(uploaded) != 1 {
+		t.Errorf("Expected one upload, got %v", uploads)
+	}
+
+	ref := "media:" + testMediaCollection + "/" + testMediaID
+	expected := "Before:\n\n![shot.png](" + ref + ")\n\nSee ![shot.png](" + ref + "), ![logo](https://example.com/logo.png) and ![gone](missing.png)."
+	if rewritten != expected {
+		t.Errorf("Unexpected markdown:\n%s\nwant:\n%s", rewritten, expected)
+	}
+
+	// The rewritten image becomes an embedded media file
+	doc := MarkdownToADF(rewritten)
+	media := doc.Content[1].Content[0]
+	if media.Type != "media" || media.Attrs["type"] != "file" || media.Attrs["id"] != testMediaID || media.Attrs["collection"] != testMediaCollection {
+		t.Errorf("Unexpected media node: %+v", media)
+	}
+}
+
+func TestUploadMarkdownImagesServer(t *testing.T) {
+	var uploads []string
+	server := newAttachmentServer(t, &uploads)
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shot.png"), []byte("png"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Flavor: FlavorServer, Token: "pat"}))
+	rewritten, _, err := service.UploadMarkdownImages(context.Background(), "TEST-1", "![screenshot](shot.png)", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Wiki markup embeds attachments by filename
+	if rewritten != "![shot.png](media:shot.png)" {
+		t.Errorf("Unexpected markdown: %s", rewritten)
+	}
+	if wiki := ADFToWiki(MarkdownToADF(rewritten)); wiki != "!shot.png!" {
+		t.Errorf("Unexpected wiki: %s", wiki)
+	}
+}
+
+func TestDownloadAttachments(t *testing.T) {
+	var uploads []string
+	server := newAttachmentServer(t, &uploads)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	attachments, err := service.GetAttachments(context.Background(), "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(attachments) != 2 || attachments[0].Author != "Dev" || attachments[0].Created.IsZero() {
+		t.Errorf("Unexpected attachments: %+v", attachments[0])
+	}
+
+	dir := t.TempDir()
+	paths, err := service.DownloadAttachments(context.Background(), "TEST-1", dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Filenames from Jira cannot escape the download directory
+	expected := []string{filepath.Join(dir, "shot.png"), filepath.Join(dir, "escape.png")}
+	if len(paths) != 2 || paths[0] != expected[0] || paths[1] != expected[1] {
+		t.Fatalf("Unexpected paths: %v", paths)
+	}
+	if content, _ := os.ReadFile(paths[0]); string(content) != "binary" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+}
@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// MaxBulkCreate is the most issues Jira creates in one bulk request
+const MaxBulkCreate = 50
+
+// BulkCreateResult is the outcome of creating one ticket in a bulk create
+type BulkCreateResult struct {
+	Key string
+	Err error
+}
+
+// CreateIssuesBulk creates up to MaxBulkCreate issues in one request. Jira
+// creates the issues it can and reports the failed ones in the response.
+func (c *Client) CreateIssuesBulk(ctx context.Context, requests []JiraCreateIssueRequest) (*JiraBulkCreateResponse, error) {
+	body, err := json.Marshal(JiraBulkCreateRequest{IssueUpdates: requests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/issue/bulk", strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// A 400 means every issue failed; the reasons are in the same body
+	if resp.StatusCode != 201 && resp.StatusCode != 400 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var response JiraBulkCreateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if resp.StatusCode == 400 && len(response.Errors) == 0 {
+		return nil, fmt.Errorf("HTTP 400: bulk create failed")
+	}
+
+	return &response, nil
+}
+
+// CreateTickets creates tickets in bulk, MaxBulkCreate at a time, and
+// returns a result for each ticket in order. Tickets that cannot be created
+// carry an error; the returned error is for requests that failed outright.
+func (ts *TicketService) CreateTickets(ctx context.Context, tickets []*types.Ticket) ([]BulkCreateResult, error) {
+	results := make([]BulkCreateResult, len(tickets))
+
+	// Build every request first; invalid tickets are left out of the batch
+	var requests []JiraCreateIssueRequest
+	var indexes []int
+	for i, ticket := range tickets {
+		request, err := ts.createRequest(ctx, ticket)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		requests = append(requests, *request)
+		indexes = append(indexes, i)
+	}
+
+	for start := 0; start < len(requests); start += MaxBulkCreate {
+		end := start + MaxBulkCreate
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		response, err := ts.client.CreateIssuesBulk(ctx, requests[start:end])
+		if err != nil {
+			return results, fmt.Errorf("failed to create issues: %v", err)
+		}
+
+		failed := make(map[int]error, len(response.Errors))
+		for _, e := range response.Errors {
+			message := e.ElementErrors.message()
+			if message == "" {
+				message = fmt.Sprintf("HTTP %d", e.Status)
+			}
+			failed[e.FailedElementNumber] = errors.New(message)
+		}
+
+		// Created issues are listed in request order, skipping failures
+		created := response.Issues
+		for n, index := range indexes[start:end] {
+			switch {
+			case failed[n] != nil:
+				results[index].Err = failed[n]
+			case len(created) > 0:
+				results[index].Key = created[0].Key
+				created = created[1:]
+			default:
+				results[index].Err = fmt.Errorf("not created")
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// FindTicket looks for an existing ticket of a type with exactly the given
+// title (ignoring case) under parentKey, or without a parent when parentKey
+// is empty. It returns nil when there is none.
+func (ts *TicketService) FindTicket(ctx context.Context, project, ticketType, title, parentKey string) (*types.Ticket, error) {
+	if project == "" {
+		project = ts.client.config.Project
+	}
+
+	issueTypeID, err := ts.getIssueTypeID(ctx, project, ticketType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Text search is fuzzy; it narrows the candidates, which are then
+	// compared exactly. Quotes would end the phrase, so leave them out.
+	phrase := strings.NewReplacer(`"`, " ", `\`, " ").Replace(title)
+	jql := fmt.Sprintf(`project = %s AND issuetype = %s AND summary ~ "\"%s\"" ORDER BY created ASC`, project, issueTypeID, phrase)
+
+	it := ts.Search(jql, nil)
+	for it.Next(ctx) {
+		ticket := it.Ticket()
+		if strings.EqualFold(strings.TrimSpace(ticket.Title), strings.TrimSpace(title)) &&
+			ticket.Relationships.ParentKey == parentKey {
+			return ticket, nil
+		}
+	}
+	return nil, it.Err()
+}
@@ -0,0 +1,118 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestCreateTicketsInBulk(t *testing.T) {
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	var batches []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue/bulk" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var request JiraBulkCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		batches = append(batches, len(request.IssueUpdates))
+
+		// The first issue of each batch is rejected
+		response := JiraBulkCreateResponse{Errors: []JiraBulkCreateError{{
+			Status:              400,
+			ElementErrors:       JiraError{Errors: map[string]string{"summary": "Summary is too long"}},
+			FailedElementNumber: 0,
+		}}}
+		for i, issue := range request.IssueUpdates[1:] {
+			response.Issues = append(response.Issues, JiraCreateIssueResponse{Key: fmt.Sprintf("TEST-%d", len(batches)*100+i+1)})
+			if issue.Fields.IssueType.ID != "10101" {
+				t.Errorf("Expected the Task issue type, got %q", issue.Fields.IssueType.ID)
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+	service.SetMetadataService(NewMetadataService(NewClient(&types.JiraConfig{URL: metadataServer.URL}), nil))
+
+	var tickets []*types.Ticket
+	for i := 0; i < MaxBulkCreate+2; i++ {
+		tickets = append(tickets, types.NewTicket("", fmt.Sprintf("Task %d", i), types.TicketTypeTask))
+	}
+	// An unmapped custom field fails before anything is sent
+	tickets[1].JiraData.CustomFields["severity"] = "High"
+
+	results, err := service.CreateTickets(context.Background(), tickets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(batches) != fmt.Sprint([]int{MaxBulkCreate, 1}) {
+		t.Errorf("Expected batches of %d and 1, got %v", MaxBulkCreate, batches)
+	}
+	if results[0].Err == nil || !strings.Contains(results[0].Err.Error(), "Summary is too long") {
+		t.Errorf("Expected the rejected issue's error, got %+v", results[0])
+	}
+	if results[1].Err == nil || results[1].Key != "" {
+		t.Errorf("Expected the invalid ticket to fail, got %+v", results[1])
+	}
+	if results[2].Key != "TEST-101" || results[MaxBulkCreate].Key != "TEST-149" {
+		t.Errorf("Expected keys in request order, got %q and %q", results[2].Key, results[MaxBulkCreate].Key)
+	}
+	if results[MaxBulkCreate+1].Err == nil {
+		t.Errorf("Expected the second batch's rejected issue to fail, got %+v", results[MaxBulkCreate+1])
+	}
+}
+
+func TestFindTicket(t *testing.T) {
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	var jql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql = r.URL.Query().Get("jql")
+		fmt.Fprint(w, `{"startAt": 0, "total": 3, "issues": [
+			{"key": "TEST-2", "fields": {"summary": "Add TOTP enrollment later", "parent": {"key": "TEST-1"}}},
+			{"key": "TEST-3", "fields": {"summary": "Add TOTP enrollment", "parent": {"key": "TEST-9"}}},
+			{"key": "TEST-4", "fields": {"summary": "add totp enrollment ", "parent": {"key": "TEST-1"}}}
+		]}`)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+	service.SetMetadataService(NewMetadataService(NewClient(&types.JiraConfig{URL: metadataServer.URL}), nil))
+
+	ticket, err := service.FindTicket(context.Background(), "", types.TicketTypeTask, `Add "TOTP" enrollment`, "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ticket != nil {
+		t.Errorf("Expected quotes to keep the title from matching, got %s", ticket.Key)
+	}
+	if !strings.Contains(jql, `issuetype = 10101 AND summary ~ "\"Add  TOTP  enrollment\""`) {
+		t.Errorf("Unexpected JQL: %s", jql)
+	}
+
+	ticket, err = service.FindTicket(context.Background(), "", types.TicketTypeTask, "Add TOTP enrollment", "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ticket == nil || ticket.Key != "TEST-4" {
+		t.Errorf("Expected TEST-4, got %+v", ticket)
+	}
+}
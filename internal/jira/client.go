@@ -2,7 +2,6 @@ package jira
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,8 +9,10 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/lunchboxsushi/jit/internal/network"
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
 
@@ -22,24 +23,94 @@ type Client struct {
 	token      string
 	httpClient *http.Client
 	config     *types.JiraConfig
+
+	flavor   string // Empty until known
+	flavorMu sync.Mutex
+
+	oauth *OAuthClient
 }
 
-// NewClient creates a new Jira client
+// NewClient creates a new Jira client with the default network settings
 func NewClient(config *types.JiraConfig) *Client {
+	return NewClientWithTransport(config, network.DefaultTransport())
+}
+
+// NewClientWithTransport creates a new Jira client that sends requests
+// through base, typically a transport from network.NewTransport. Its
+// timeouts, like the client's request timeout, apply to each attempt rather
+// than the whole request, so waiting out a Retry-After is not cut short.
+func NewClientWithTransport(config *types.JiraConfig, base http.RoundTripper) *Client {
+	policy := DefaultRetryPolicy()
+	if config.MaxRetries > 0 {
+		policy.MaxRetries = config.MaxRetries
+	} else if config.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+
+	breaker := NewCircuitBreaker(DefaultBreakerThreshold, DefaultBreakerCooldown)
+
 	return &Client{
 		baseURL:    config.URL,
 		username:   config.Username,
 		token:      config.Token,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Transport: NewRetryTransport(base, policy, breaker)},
 		config:     config,
 	}
 }
 
-// doRequest performs an HTTP request with authentication and error handling
+// SetRequestTimeout limits how long each attempt at a request may take,
+// reading the response included
+func (c *Client) SetRequestTimeout(timeout time.Duration) {
+	if transport, ok := c.httpClient.Transport.(*RetryTransport); ok {
+		transport.Policy.AttemptTimeout = timeout
+	}
+}
+
+// SetOAuthClient makes the client authenticate with OAuth tokens once the
+// user has logged in, falling back to the API token until then
+func (c *Client) SetOAuthClient(oauth *OAuthClient) {
+	c.oauth = oauth
+}
+
+// OAuth returns the client's OAuth client, or nil when OAuth is not configured
+func (c *Client) OAuth() *OAuthClient {
+	return c.oauth
+}
+
+// doRequest performs an HTTP request with authentication and error handling.
+// Rate limiting and transient failures are retried by the client's transport.
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
-	// Construct full URL
-	fullURL := c.baseURL + "/rest/api/3" + endpoint
+	return c.doRequestWithHeaders(ctx, method, endpoint, body, nil)
+}
+
+// doRequestWithHeaders is doRequest for requests that are not plain JSON;
+// header values replace the JSON defaults
+func (c *Client) doRequestWithHeaders(ctx context.Context, method, endpoint string, body io.Reader, header http.Header) (*http.Response, error) {
+	return c.doRequestAt(ctx, method, c.apiPath(ctx)+endpoint, body, header)
+}
 
+// doRequestAt sends a request to path, the endpoint with its REST API prefix
+// such as /rest/api/3 or /rest/agile/1.0
+func (c *Client) doRequestAt(ctx context.Context, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	if c.oauth != nil {
+		token, err := c.oauth.Token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			return c.doOAuthRequest(ctx, token, method, path, body, header)
+		}
+		if c.token == "" {
+			return nil, ErrNotLoggedIn
+		}
+	}
+
+	return c.send(ctx, method, c.baseURL+path, body, c.authorization(ctx), header)
+}
+
+// send performs a single request with the given Authorization header. It
+// sends and accepts JSON unless header says otherwise.
+func (c *Client) send(ctx context.Context, method, fullURL string, body io.Reader, authorization string, header http.Header) (*http.Response, error) {
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
 	if err != nil {
@@ -49,10 +120,10 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body io
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-
-	// Set authentication
-	auth := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.token))
-	req.Header.Set("Authorization", "Basic "+auth)
+	req.Header.Set("Authorization", authorization)
+	for name, values := range header {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
 
 	// Make request
 	resp, err := c.httpClient.Do(req)
@@ -60,21 +131,6 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body io
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
 
-	// Handle rate limiting
-	if resp.StatusCode == 429 {
-		retryAfter := resp.Header.Get("Retry-After")
-		if retryAfter != "" {
-			if seconds, err := strconv.Atoi(retryAfter); err == nil {
-				time.Sleep(time.Duration(seconds) * time.Second)
-				// Retry the request
-				return c.doRequest(ctx, method, endpoint, body)
-			}
-		}
-		// Default retry delay
-		time.Sleep(5 * time.Second)
-		return c.doRequest(ctx, method, endpoint, body)
-	}
-
 	return resp, nil
 }
 
@@ -88,15 +144,8 @@ func (c *Client) parseErrorResponse(resp *http.Response) error {
 	// Try to parse as Jira error
 	var jiraError JiraErrorResponse
 	if err := json.Unmarshal(body, &jiraError); err == nil {
-		if len(jiraError.ErrorCollection.ErrorMessages) > 0 {
-			return fmt.Errorf("Jira API error: %s", strings.Join(jiraError.ErrorCollection.ErrorMessages, "; "))
-		}
-		if len(jiraError.ErrorCollection.Errors) > 0 {
-			var errors []string
-			for field, message := range jiraError.ErrorCollection.Errors {
-				errors = append(errors, fmt.Sprintf("%s: %s", field, message))
-			}
-			return fmt.Errorf("Jira API error: %s", strings.Join(errors, "; "))
+		if message := jiraError.ErrorCollection.message(); message != "" {
+			return fmt.Errorf("Jira API error: %s", message)
 		}
 	}
 
@@ -152,10 +201,10 @@ func (c *Client) CreateIssue(ctx context.Context, request *JiraCreateIssueReques
 	return &response, nil
 }
 
-// AddComment adds a comment to an issue
+// AddComment adds a markdown comment to an issue
 func (c *Client) AddComment(ctx context.Context, issueKey, commentBody string) (*JiraComment, error) {
 	request := JiraCreateCommentRequest{
-		Body: commentBody,
+		Body: c.richText(ctx, commentBody),
 	}
 
 	// Marshal request body
@@ -183,15 +232,41 @@ func (c *Client) AddComment(ctx context.Context, issueKey, commentBody string) (
 	return &comment, nil
 }
 
-// SearchIssues performs a JQL search
+// SearchIssues performs a JQL search returning a single page of results
 func (c *Client) SearchIssues(ctx context.Context, jql string, maxResults int) (*JiraSearchResponse, error) {
+	return c.SearchIssuesPage(ctx, &SearchOptions{
+		JQL:        jql,
+		MaxResults: maxResults,
+	})
+}
+
+// SearchIssuesPage fetches one page of a JQL search. Pagination is driven either by
+// StartAt (classic offset paging on /search) or, on Cloud, by NextPageToken on
+// /search/jql once Jira returns one.
+func (c *Client) SearchIssuesPage(ctx context.Context, opts *SearchOptions) (*JiraSearchResponse, error) {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = DefaultSearchFields
+	}
+
 	// Build query parameters
 	params := url.Values{}
-	params.Set("jql", jql)
-	params.Set("maxResults", strconv.Itoa(maxResults))
-	params.Set("fields", "summary,description,status,priority,issuetype,project,assignee,reporter,created,updated,labels")
+	params.Set("jql", opts.JQL)
+	params.Set("fields", strings.Join(fields, ","))
+	if opts.MaxResults > 0 {
+		params.Set("maxResults", strconv.Itoa(opts.MaxResults))
+	}
 
-	endpoint := "/search?" + params.Encode()
+	// Tokens are only understood by Cloud's /search/jql; Server and Data
+	// Center page /search by offset
+	endpoint := "/search?"
+	if opts.NextPageToken != "" && c.Flavor(ctx) == FlavorCloud {
+		params.Set("nextPageToken", opts.NextPageToken)
+		endpoint = "/search/jql?"
+	} else if opts.StartAt > 0 {
+		params.Set("startAt", strconv.Itoa(opts.StartAt))
+	}
+	endpoint += params.Encode()
 
 	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
@@ -211,6 +286,26 @@ func (c *Client) SearchIssues(ctx context.Context, jql string, maxResults int) (
 	return &response, nil
 }
 
+// GetCurrentUser fetches the user jit is authenticated as
+func (c *Client) GetCurrentUser(ctx context.Context) (*JiraUser, error) {
+	resp, err := c.doRequest(ctx, "GET", "/myself", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var user JiraUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &user, nil
+}
+
 // TestConnection tests the connection to Jira
 func (c *Client) TestConnection(ctx context.Context) error {
 	resp, err := c.doRequest(ctx, "GET", "/myself", nil)
@@ -59,7 +59,7 @@ func TestGetIssue(t *testing.T) {
 			ID:  "12345",
 			Fields: JiraIssueFields{
 				Summary:     "Test Issue",
-				Description: "This is a test issue",
+				Description: NewADFText("This is a test issue"),
 				Status: JiraStatus{
 					ID:   "10000",
 					Name: "To Do",
@@ -77,8 +77,8 @@ func TestGetIssue(t *testing.T) {
 					Key:  "TEST",
 					Name: "Test Project",
 				},
-				Created: time.Now(),
-				Updated: time.Now(),
+				Created: JiraTime{time.Now()},
+				Updated: JiraTime{time.Now()},
 				Labels:  []string{"test", "example"},
 			},
 		}
@@ -116,6 +116,10 @@ func TestGetIssue(t *testing.T) {
 	if issue.Fields.Status.Name != "To Do" {
 		t.Errorf("Expected status 'To Do', got %s", issue.Fields.Status.Name)
 	}
+
+	if description := issue.Fields.Description.Markdown(); description != "This is a test issue" {
+		t.Errorf("Expected description 'This is a test issue', got %s", description)
+	}
 }
 
 func TestCreateIssue(t *testing.T) {
@@ -175,7 +179,7 @@ func TestCreateIssue(t *testing.T) {
 		Fields: JiraCreateIssueFields{
 			Project:     JiraProjectReference{Key: "TEST"},
 			Summary:     "New Test Issue",
-			Description: "This is a new test issue",
+			Description: NewADFText("This is a new test issue"),
 			IssueType:   JiraIssueTypeRef{ID: "10001"},
 		},
 	}
@@ -214,17 +218,20 @@ func TestAddComment(t *testing.T) {
 			t.Errorf("Failed to decode request: %v", err)
 		}
 
-		// Check comment body
-		if request.Body != "Test comment" {
-			t.Errorf("Expected comment 'Test comment', got %s", request.Body)
+		// Check comment body is sent as ADF
+		if request.Body == nil || request.Body.ADF == nil || request.Body.ADF.Type != "doc" {
+			t.Fatalf("Expected ADF document body, got %+v", request.Body)
+		}
+		if body := request.Body.Markdown(); body != "Test comment" {
+			t.Errorf("Expected comment 'Test comment', got %s", body)
 		}
 
 		// Return mock response
 		comment := JiraComment{
 			ID:      "10000",
-			Body:    "Test comment",
-			Created: time.Now(),
-			Updated: time.Now(),
+			Body:    request.Body,
+			Created: JiraTime{time.Now()},
+			Updated: JiraTime{time.Now()},
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -250,8 +257,8 @@ func TestAddComment(t *testing.T) {
 		t.Fatalf("Failed to add comment: %v", err)
 	}
 
-	if comment.Body != "Test comment" {
-		t.Errorf("Expected comment body 'Test comment', got %s", comment.Body)
+	if body := comment.Body.Markdown(); body != "Test comment" {
+		t.Errorf("Expected comment body 'Test comment', got %s", body)
 	}
 }
 
@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// GetComments fetches all comments on an issue, oldest first
+func (c *Client) GetComments(ctx context.Context, issueKey string) ([]JiraComment, error) {
+	var comments []JiraComment
+
+	for {
+		endpoint := fmt.Sprintf("/issue/%s/comment?startAt=%d&orderBy=created", issueKey, len(comments))
+		resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != 200 {
+			err := c.parseErrorResponse(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var page JiraCommentsResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		comments = append(comments, page.Comments...)
+		if len(page.Comments) == 0 || len(comments) >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// UpdateComment replaces the body of a comment with markdown
+func (c *Client) UpdateComment(ctx context.Context, issueKey, commentID, commentBody string) (*JiraComment, error) {
+	body, err := json.Marshal(JiraCreateCommentRequest{Body: c.richText(ctx, commentBody)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s/comment/%s", issueKey, commentID)
+	resp, err := c.doRequest(ctx, "PUT", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var comment JiraComment
+	if err := json.NewDecoder(resp.Body).Decode(&comment); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment deletes a comment
+func (c *Client) DeleteComment(ctx context.Context, issueKey, commentID string) error {
+	endpoint := fmt.Sprintf("/issue/%s/comment/%s", issueKey, commentID)
+	resp, err := c.doRequest(ctx, "DELETE", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetComments fetches the comments on a ticket, oldest first
+func (ts *TicketService) GetComments(ctx context.Context, ticketKey string) ([]*types.Comment, error) {
+	jiraComments, err := ts.client.GetComments(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments for %s: %v", ticketKey, err)
+	}
+
+	comments := make([]*types.Comment, 0, len(jiraComments))
+	for i := range jiraComments {
+		comments = append(comments, convertComment(ticketKey, &jiraComments[i]))
+	}
+
+	return comments, nil
+}
+
+// EditComment replaces a comment's body and returns the comment as Jira
+// recorded it
+func (ts *TicketService) EditComment(ctx context.Context, ticketKey, commentID, commentBody string) (*types.Comment, error) {
+	updated, err := ts.client.UpdateComment(ctx, ticketKey, commentID, commentBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit comment %s: %v", commentID, err)
+	}
+
+	return convertComment(ticketKey, updated), nil
+}
+
+// DeleteComment deletes a comment from a ticket
+func (ts *TicketService) DeleteComment(ctx context.Context, ticketKey, commentID string) error {
+	if err := ts.client.DeleteComment(ctx, ticketKey, commentID); err != nil {
+		return fmt.Errorf("failed to delete comment %s: %v", commentID, err)
+	}
+	return nil
+}
+
+// convertComment converts a Jira comment to our internal format
+func convertComment(ticketKey string, jiraComment *JiraComment) *types.Comment {
+	author := convertUser(&jiraComment.Author)
+	return &types.Comment{
+		ID:        jiraComment.ID,
+		TicketKey: ticketKey,
+		Author:    author.DisplayName,
+		AuthorID:  author.ID,
+		Body:      jiraComment.Body.Markdown(),
+		Created:   jiraComment.Created.Time,
+		Updated:   jiraComment.Updated.Time,
+	}
+}
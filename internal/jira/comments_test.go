@@ -0,0 +1,106 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestGetCommentsFetchesAllPages(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/rest/api/3/issue/TEST-1/comment" || r.URL.Query().Get("orderBy") != "created" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+
+		// Two comments per page, three in all
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		var comments []string
+		for id := startAt + 1; id <= 3 && id <= startAt+2; id++ {
+			comments = append(comments, fmt.Sprintf(`{
+				"id": "%d",
+				"author": {"accountId": "acc-%d", "displayName": "User %d"},
+				"body": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Comment %d", "marks": [{"type": "strong"}]}]}]},
+				"created": "2024-03-0%dT10:00:00.000+0000",
+				"updated": "2024-03-0%dT10:00:00.000+0000"
+			}`, id, id, id, id, id, id))
+		}
+		fmt.Fprintf(w, `{"startAt": %d, "maxResults": 2, "total": 3, "comments": [%s]}`, startAt, strings.Join(comments, ","))
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	comments, err := service.GetComments(context.Background(), "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 2 || len(comments) != 3 {
+		t.Fatalf("Expected 3 comments in 2 requests, got %d in %d", len(comments), requests)
+	}
+	first := comments[0]
+	if first.ID != "1" || first.Author != "User 1" || first.AuthorID != "acc-1" || first.TicketKey != "TEST-1" {
+		t.Errorf("Unexpected comment: %+v", first)
+	}
+	if first.Body != "**Comment 1**" {
+		t.Errorf("Expected a markdown body, got %q", first.Body)
+	}
+	if first.Edited() {
+		t.Error("Expected an unedited comment")
+	}
+}
+
+func TestEditComment(t *testing.T) {
+	var sent map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/rest/api/2/issue/TEST-1/comment/10" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.Write([]byte(`{"id": "10", "author": {"name": "dev", "displayName": "Dev"}, "body": "Now *bold*", "created": "2024-03-01T10:00:00.000+0000", "updated": "2024-03-02T10:00:00.000+0000"}`))
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Flavor: FlavorServer}))
+
+	comment, err := service.EditComment(context.Background(), "TEST-1", "10", "Now **bold**")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if string(sent["body"]) != `"Now *bold*"` {
+		t.Errorf("Expected a wiki body, got %s", sent["body"])
+	}
+	if comment.Body != "Now **bold**" || comment.AuthorID != "dev" || !comment.Edited() {
+		t.Errorf("Unexpected comment: %+v", comment)
+	}
+}
+
+func TestDeleteComment(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/rest/api/3/issue/TEST-1/comment/10" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	if err := service.DeleteComment(context.Background(), "TEST-1", "10"); err != nil || !deleted {
+		t.Fatalf("Expected the comment to be deleted, got %v", err)
+	}
+}
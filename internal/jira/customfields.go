@@ -0,0 +1,249 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// customFieldPrefix starts the IDs of custom fields, e.g. customfield_10016
+const customFieldPrefix = "customfield_"
+
+// Jira's date layout for date custom fields
+const jiraDateFormat = "2006-01-02"
+
+// CustomFieldNames returns the configured custom field names, sorted
+func (ts *TicketService) CustomFieldNames() []string {
+	if ts.client.config == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(ts.client.config.CustomFields))
+	for name := range ts.client.config.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// customFieldIDs maps each configured custom field name to its field ID.
+// The configuration may give IDs outright or Jira field names, which are
+// looked up in the project metadata; names that cannot be found are left out.
+func (ts *TicketService) customFieldIDs(ctx context.Context) map[string]string {
+	ids := make(map[string]string)
+	for _, name := range ts.CustomFieldNames() {
+		ref := ts.client.config.CustomFields[name]
+		if strings.HasPrefix(ref, customFieldPrefix) {
+			ids[name] = ref
+		} else if field := ts.customField(ctx, name); field != nil {
+			ids[name] = field.ID
+		}
+	}
+	return ids
+}
+
+// customField finds the field a configured custom field name refers to,
+// with its schema from the project metadata. A field ID the metadata does
+// not describe is returned without a schema.
+func (ts *TicketService) customField(ctx context.Context, name string) *JiraField {
+	if ts.client.config == nil {
+		return nil
+	}
+	ref, ok := ts.client.config.CustomFields[name]
+	if !ok {
+		return nil
+	}
+
+	if meta, err := ts.projectMetadata(ctx); err == nil {
+		if field, ok := meta.Field(ref); ok {
+			return field
+		}
+	}
+	if strings.HasPrefix(ref, customFieldPrefix) {
+		return &JiraField{ID: ref, Custom: true}
+	}
+	return nil
+}
+
+// decodeCustomFields picks the configured custom fields out of an issue's
+// fields, keyed by their configured names
+func (ts *TicketService) decodeCustomFields(ctx context.Context, fields *JiraIssueFields) map[string]interface{} {
+	values := make(map[string]interface{})
+	if len(fields.CustomFields) == 0 {
+		return values
+	}
+
+	for name, id := range ts.customFieldIDs(ctx) {
+		if value, ok := fields.CustomFields[id]; ok && value != nil {
+			values[name] = decodeCustomFieldValue(value)
+		}
+	}
+	return values
+}
+
+// decodeCustomFieldValue simplifies a custom field value for local storage
+// and editing: options become their value, users their display name, rich
+// text markdown, and lists of these lists of the same.
+func decodeCustomFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		decoded := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			decoded = append(decoded, decodeCustomFieldValue(item))
+		}
+		return decoded
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return v
+			}
+			var body RichText
+			if err := json.Unmarshal(data, &body); err != nil {
+				return v
+			}
+			return body.Markdown()
+		}
+		for _, key := range []string{"value", "displayName", "name", "key"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+		return v
+	}
+	return value
+}
+
+// encodeCustomField converts a custom field value from its local form into
+// what Jira expects for the field's type. A nil value clears the field.
+func (ts *TicketService) encodeCustomField(ctx context.Context, field *JiraField, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if field.Schema == nil {
+		return value, nil
+	}
+
+	if field.Schema.Type == "array" {
+		items, ok := value.([]interface{})
+		if !ok {
+			items = []interface{}{value}
+		}
+
+		encoded := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			value, err := ts.encodeCustomValue(ctx, field.Schema.Items, field.Schema.Custom, item)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, value)
+		}
+		return encoded, nil
+	}
+
+	return ts.encodeCustomValue(ctx, field.Schema.Type, field.Schema.Custom, value)
+}
+
+// encodeCustomValue converts a single value of the given schema type
+func (ts *TicketService) encodeCustomValue(ctx context.Context, schemaType, custom string, value interface{}) (interface{}, error) {
+	switch schemaType {
+	case "number":
+		return customNumber(value)
+	case "string":
+		text := customString(value)
+		// Paragraph fields take rich text; single-line ones plain text
+		if strings.HasSuffix(custom, ":textarea") {
+			return ts.client.richText(ctx, text), nil
+		}
+		return text, nil
+	case "option":
+		return map[string]string{"value": customString(value)}, nil
+	case "user":
+		user, err := ts.users.Resolve(ctx, customString(value))
+		if err != nil {
+			return nil, err
+		}
+		return ts.client.userRef(ctx, user.ID), nil
+	case "date":
+		date, err := customTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return date.Format(jiraDateFormat), nil
+	case "datetime":
+		date, err := customTime(value)
+		if err != nil {
+			return nil, err
+		}
+		return date.Format(JiraTimeFormat), nil
+	}
+	return value, nil
+}
+
+// customNumber reads a number given as a number or a string
+func customNumber(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), nil
+		}
+		return v, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", v)
+		}
+		return customNumber(n)
+	}
+	return nil, fmt.Errorf("%v is not a number", value)
+}
+
+// customString reads a value as text
+func customString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// customTime reads a date or time given as a time or a string in RFC 3339,
+// Jira's format or as a plain date
+func customTime(value interface{}) (time.Time, error) {
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+
+	text := customString(value)
+	for _, layout := range []string{time.RFC3339, JiraTimeFormat, jiraDateFormat} {
+		if t, err := time.ParseInLocation(layout, text, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (use YYYY-MM-DD)", text)
+}
+
+// customFieldsRequest encodes local custom field values keyed by configured
+// name into Jira field values keyed by field ID
+func (ts *TicketService) customFieldsRequest(ctx context.Context, values map[string]interface{}, names []string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, name := range names {
+		field := ts.customField(ctx, name)
+		if field == nil {
+			return nil, fmt.Errorf("unknown custom field %q; map it to a field under jira.custom_fields", name)
+		}
+
+		value, err := ts.encodeCustomField(ctx, field, values[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		fields[field.ID] = value
+	}
+	return fields, nil
+}
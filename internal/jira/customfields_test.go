@@ -0,0 +1,210 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// customFieldConfig maps friendly names both by field ID and by Jira name
+func customFieldConfig(url string) *types.JiraConfig {
+	return &types.JiraConfig{
+		URL:     url,
+		Project: "TEST",
+		CustomFields: map[string]string{
+			"story_points": "Story Points",
+			"team":         "customfield_10030",
+			"platforms":    "customfield_10031",
+			"due":          "customfield_10032",
+			"notes":        "customfield_10033",
+		},
+	}
+}
+
+// newCustomFieldServer serves field metadata with schemas and records the
+// fields sent to edit TEST-1
+func newCustomFieldServer(t *testing.T, sent *map[string]json.RawMessage) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch r.URL.Path {
+		case "/rest/api/3/issue/createmeta/TEST/issuetypes":
+			response = JiraCreateMetaIssueTypesResponse{IssueTypes: []JiraIssueType{{ID: "10101", Name: "Task"}}}
+		case "/rest/api/3/project/TEST":
+			response = JiraProjectDetails{JiraProject: JiraProject{ID: "1", Key: "TEST"}}
+		case "/rest/api/3/priority":
+			response = []JiraPriority{{ID: "3", Name: "Medium"}}
+		case "/rest/api/3/field":
+			response = []JiraField{
+				{ID: "customfield_10016", Name: "Story Points", Custom: true, Schema: &JiraFieldSchema{Type: "number"}},
+				{ID: "customfield_10030", Name: "Team", Custom: true, Schema: &JiraFieldSchema{Type: "option"}},
+				{ID: "customfield_10031", Name: "Platforms", Custom: true, Schema: &JiraFieldSchema{Type: "array", Items: "option"}},
+				{ID: "customfield_10032", Name: "Due", Custom: true, Schema: &JiraFieldSchema{Type: "date"}},
+				{ID: "customfield_10033", Name: "Notes", Custom: true, Schema: &JiraFieldSchema{Type: "string", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:textarea"}},
+			}
+		case "/rest/api/3/issue/TEST-1":
+			var request map[string]map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			*sent = request["fields"]
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestDecodeCustomFields(t *testing.T) {
+	var sent map[string]json.RawMessage
+	server := newCustomFieldServer(t, &sent)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(customFieldConfig(server.URL)))
+
+	var issue JiraIssue
+	data := `{"key": "TEST-1", "fields": {
+		"summary": "Test",
+		"customfield_10016": 5,
+		"customfield_10030": {"id": "1", "value": "Platform"},
+		"customfield_10031": [{"value": "iOS"}, {"value": "Android"}],
+		"customfield_10032": null,
+		"customfield_10033": {"type": "doc", "version": 1, "content": [{"type": "paragraph", "content": [{"type": "text", "text": "Check logs"}]}]},
+		"customfield_10099": "not configured"
+	}}`
+	if err := json.Unmarshal([]byte(data), &issue); err != nil {
+		t.Fatalf("Failed to decode issue: %v", err)
+	}
+
+	ticket := service.convertJiraIssueToTicket(context.Background(), &issue)
+
+	expected := map[string]interface{}{
+		"story_points": float64(5),
+		"team":         "Platform",
+		"platforms":    []interface{}{"iOS", "Android"},
+		"notes":        "Check logs",
+	}
+	if !reflect.DeepEqual(ticket.JiraData.CustomFields, expected) {
+		t.Errorf("Expected %v, got %v", expected, ticket.JiraData.CustomFields)
+	}
+}
+
+func TestDecodeCustomFieldsRemembersMetadataFailure(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMetadataRequest(r) {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(customFieldConfig(server.URL)))
+	issue := &JiraIssue{Key: "TEST-1", Fields: JiraIssueFields{
+		CustomFields: map[string]interface{}{"customfield_10030": "Platform"},
+	}}
+
+	var lookup int
+	for i := 0; i < 3; i++ {
+		ticket := service.convertJiraIssueToTicket(context.Background(), issue)
+		if ticket.JiraData.CustomFields["team"] != "Platform" {
+			t.Errorf("Expected fields configured by ID to decode without metadata, got %v", ticket.JiraData.CustomFields)
+		}
+		if i == 0 {
+			lookup = requests
+		}
+	}
+	if lookup == 0 || requests != lookup {
+		t.Errorf("Expected the failed metadata lookup to be made once, got %d requests after %d", requests, lookup)
+	}
+}
+
+func TestUpdateTicketEncodesCustomFields(t *testing.T) {
+	var sent map[string]json.RawMessage
+	server := newCustomFieldServer(t, &sent)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(customFieldConfig(server.URL)))
+
+	before := editableTicket()
+	before.JiraData.CustomFields = map[string]interface{}{"story_points": float64(3), "team": "Platform"}
+	after := *before
+	after.JiraData.CustomFields = map[string]interface{}{
+		"story_points": "8",
+		"platforms":    []interface{}{"iOS"},
+		"due":          "2024-03-18",
+	}
+
+	changed, err := service.UpdateTicket(context.Background(), before, &after)
+	if err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if expected := []string{"due", "platforms", "story_points", "team"}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v, got %v", expected, changed)
+	}
+
+	expected := map[string]string{
+		"customfield_10016": `8`,
+		"customfield_10030": `null`,
+		"customfield_10031": `[{"value":"iOS"}]`,
+		"customfield_10032": `"2024-03-18"`,
+	}
+	if len(sent) != len(expected) {
+		t.Errorf("Expected only the changed fields, got %v", sent)
+	}
+	for id, value := range expected {
+		if string(sent[id]) != value {
+			t.Errorf("Expected %s to be sent as %s, got %s", id, value, sent[id])
+		}
+	}
+
+	after.JiraData.CustomFields = map[string]interface{}{"story_points": "lots"}
+	if _, err := service.UpdateTicket(context.Background(), before, &after); err == nil {
+		t.Error("Expected error for a non-numeric story points value")
+	}
+}
+
+func TestCustomFieldsRequestRejectsUnknownNames(t *testing.T) {
+	var sent map[string]json.RawMessage
+	server := newCustomFieldServer(t, &sent)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(customFieldConfig(server.URL)))
+
+	values := map[string]interface{}{"severity": "High"}
+	if _, err := service.customFieldsRequest(context.Background(), values, []string{"severity"}); err == nil {
+		t.Error("Expected error for an unmapped custom field")
+	}
+}
+
+func TestCreateIssueFieldsMarshalsCustomFields(t *testing.T) {
+	fields := JiraCreateIssueFields{
+		Project:      JiraProjectReference{Key: "TEST"},
+		Summary:      "Test",
+		CustomFields: map[string]interface{}{"customfield_10016": 5},
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("Failed to encode fields: %v", err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to decode fields: %v", err)
+	}
+	if string(decoded["customfield_10016"]) != "5" || string(decoded["summary"]) != `"Test"` {
+		t.Errorf("Expected custom fields alongside the standard ones, got %s", data)
+	}
+}
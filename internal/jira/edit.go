@@ -0,0 +1,221 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// Editable ticket fields, named as Jira names them
+const (
+	FieldSummary     = "summary"
+	FieldDescription = "description"
+	FieldLabels      = "labels"
+	FieldPriority    = "priority"
+	FieldAssignee    = "assignee"
+)
+
+// UpdateIssue edits an issue's fields
+func (c *Client) UpdateIssue(ctx context.Context, issueKey string, request *JiraUpdateIssueRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s", issueKey)
+	resp, err := c.doRequest(ctx, "PUT", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// DiffTickets returns the editable fields that differ between two versions
+// of a ticket. Descriptions are compared after a round trip through ADF so
+// that formatting-only differences in the markdown are ignored.
+func DiffTickets(before, after *types.Ticket) []string {
+	var changed []string
+
+	if strings.TrimSpace(before.Title) != strings.TrimSpace(after.Title) {
+		changed = append(changed, FieldSummary)
+	}
+	if normalizeMarkdown(before.Description) != normalizeMarkdown(after.Description) {
+		changed = append(changed, FieldDescription)
+	}
+	if !sameLabels(before.Metadata.Labels, after.Metadata.Labels) {
+		changed = append(changed, FieldLabels)
+	}
+	if !sameLabels(before.Metadata.FixVersions, after.Metadata.FixVersions) {
+		changed = append(changed, FieldFixVersions)
+	}
+	if !sameLabels(before.Metadata.Components, after.Metadata.Components) {
+		changed = append(changed, FieldComponents)
+	}
+	if !strings.EqualFold(strings.TrimSpace(before.Priority), strings.TrimSpace(after.Priority)) {
+		changed = append(changed, FieldPriority)
+	}
+	if strings.TrimSpace(before.Metadata.Assignee) != strings.TrimSpace(after.Metadata.Assignee) {
+		changed = append(changed, FieldAssignee)
+	}
+	changed = append(changed, diffCustomFields(before.JiraData.CustomFields, after.JiraData.CustomFields)...)
+
+	return changed
+}
+
+// diffCustomFields returns the names of custom fields whose values differ.
+// A missing field is the same as one without a value, and values are
+// compared as text so that a number read from YAML matches one from JSON.
+func diffCustomFields(before, after map[string]interface{}) []string {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	var changed []string
+	for name := range names {
+		if customFieldText(before[name]) != customFieldText(after[name]) {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// customFieldText renders a custom field value for comparison
+func customFieldText(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// UpdateTicket pushes the fields that differ between before and after to
+// Jira and returns the names of the fields sent
+func (ts *TicketService) UpdateTicket(ctx context.Context, before, after *types.Ticket) ([]string, error) {
+	changed := DiffTickets(before, after)
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	request, err := ts.buildUpdateRequest(ctx, after, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.client.UpdateIssue(ctx, after.Key, request); err != nil {
+		return nil, fmt.Errorf("failed to update %s: %v", after.Key, err)
+	}
+
+	return changed, nil
+}
+
+// buildUpdateRequest sets each changed field to its value in ticket
+func (ts *TicketService) buildUpdateRequest(ctx context.Context, ticket *types.Ticket, changed []string) (*JiraUpdateIssueRequest, error) {
+	project := ticket.Metadata.Project
+	if project == "" {
+		project = ts.client.config.Project
+	}
+
+	fields := make(map[string]interface{})
+	var customNames []string
+	for _, field := range changed {
+		switch field {
+		case FieldSummary:
+			if strings.TrimSpace(ticket.Title) == "" {
+				return nil, fmt.Errorf("summary cannot be empty")
+			}
+			fields[FieldSummary] = strings.TrimSpace(ticket.Title)
+		case FieldDescription:
+			// A nil body clears the description
+			if body := ts.client.richText(ctx, ticket.Description); body != nil {
+				fields[FieldDescription] = body
+			} else {
+				fields[FieldDescription] = nil
+			}
+		case FieldLabels:
+			labels := ticket.Metadata.Labels
+			if labels == nil {
+				labels = []string{}
+			}
+			fields[FieldLabels] = labels
+		case FieldFixVersions:
+			fields[FieldFixVersions] = nameRefs(ticket.Metadata.FixVersions)
+		case FieldComponents:
+			fields[FieldComponents] = nameRefs(ticket.Metadata.Components)
+		case FieldPriority:
+			if ticket.Priority == "" {
+				return nil, fmt.Errorf("priority cannot be cleared")
+			}
+			priority, err := ts.priorityRef(ctx, project, ticket.Priority)
+			if err != nil {
+				return nil, err
+			}
+			fields[FieldPriority] = priority
+		case FieldAssignee:
+			if ticket.Metadata.Assignee == "" {
+				fields[FieldAssignee] = nil
+			} else {
+				assignee, err := ts.assigneeRef(ctx, ticket.Metadata.Assignee)
+				if err != nil {
+					return nil, err
+				}
+				fields[FieldAssignee] = assignee
+			}
+		default:
+			customNames = append(customNames, field)
+		}
+	}
+
+	if len(customNames) > 0 {
+		customFields, err := ts.customFieldsRequest(ctx, ticket.JiraData.CustomFields, customNames)
+		if err != nil {
+			return nil, err
+		}
+		for id, value := range customFields {
+			fields[id] = value
+		}
+	}
+
+	return &JiraUpdateIssueRequest{Fields: fields}, nil
+}
+
+// normalizeMarkdown renders markdown the way it would come back from Jira
+func normalizeMarkdown(markdown string) string {
+	return ADFToMarkdown(MarkdownToADF(markdown))
+}
+
+// sameLabels compares label (or version or component name) sets, ignoring order
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func editableTicket() *types.Ticket {
+	ticket := types.NewTicket("TEST-1", "Add login", types.TicketTypeTask)
+	ticket.Description = "## Goal\n\n- one\n- two"
+	ticket.Metadata.Project = "TEST"
+	ticket.Metadata.Assignee = "abc123"
+	ticket.Metadata.Labels = []string{"backend", "auth"}
+	return ticket
+}
+
+func TestDiffTickets(t *testing.T) {
+	before := editableTicket()
+
+	// Formatting-only differences and label order are not changes
+	after := *before
+	after.Description = "## Goal\n\n\n* one\n* two\n"
+	after.Metadata.Labels = []string{"auth", "backend"}
+	if changed := DiffTickets(before, &after); len(changed) != 0 {
+		t.Errorf("Expected no changes, got %v", changed)
+	}
+
+	after.Title = "Add SSO login"
+	after.Priority = "High"
+	after.Metadata.Assignee = ""
+	expected := []string{FieldSummary, FieldPriority, FieldAssignee}
+	if changed := DiffTickets(before, &after); !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v, got %v", expected, changed)
+	}
+}
+
+func TestUpdateTicketSendsChangedFields(t *testing.T) {
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	var sent map[string]map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/rest/api/3/issue/TEST-1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := &types.JiraConfig{URL: server.URL, Project: "TEST"}
+	service := NewTicketService(NewClient(config))
+	service.SetMetadataService(NewMetadataService(NewClient(&types.JiraConfig{URL: metadataServer.URL}), nil))
+
+	before := editableTicket()
+	after := *before
+	after.Description = "## Goal\n\n- one\n- two\n- three"
+	after.Priority = "High"
+	after.Metadata.Assignee = ""
+
+	changed, err := service.UpdateTicket(context.Background(), before, &after)
+	if err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if expected := []string{FieldDescription, FieldPriority, FieldAssignee}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v, got %v", expected, changed)
+	}
+
+	fields := sent["fields"]
+	if len(fields) != 3 {
+		t.Errorf("Expected only the three changed fields, got %v", fields)
+	}
+	if string(fields["priority"]) != `{"id":"2"}` {
+		t.Errorf("Expected priority resolved to its ID, got %s", fields["priority"])
+	}
+	if string(fields["assignee"]) != "null" {
+		t.Errorf("Expected cleared assignee to be sent as null, got %s", fields["assignee"])
+	}
+
+	var description RichText
+	if err := json.Unmarshal(fields["description"], &description); err != nil || description.ADF == nil {
+		t.Fatalf("Expected ADF description, got %s", fields["description"])
+	}
+	if md := description.Markdown(); md != after.Description {
+		t.Errorf("Expected description %q, got %q", after.Description, md)
+	}
+}
+
+func TestUpdateTicketWithoutChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no request, got %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+	ticket := editableTicket()
+
+	changed, err := service.UpdateTicket(context.Background(), ticket, ticket)
+	if err != nil || len(changed) != 0 {
+		t.Errorf("Expected no update, got %v (%v)", changed, err)
+	}
+}
@@ -0,0 +1,135 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira deployment flavors
+const (
+	FlavorCloud  = "cloud"  // Jira Cloud: REST API v3, basic auth with an API token, ADF bodies
+	FlavorServer = "server" // Jira Server and Data Center: REST API v2, Personal Access Tokens, wiki markup
+	FlavorAuto   = "auto"   // Detect the flavor from /serverInfo on first use
+)
+
+// Flavor returns the deployment flavor the client talks to, detecting it on
+// first use when configured as auto. A detection that gets no answer, such
+// as when ctx is cancelled, assumes cloud for this call and is tried again
+// on the next.
+func (c *Client) Flavor(ctx context.Context) string {
+	c.flavorMu.Lock()
+	defer c.flavorMu.Unlock()
+
+	if c.flavor != "" {
+		return c.flavor
+	}
+
+	// OAuth (3LO) apps only exist on Cloud
+	if c.oauth != nil {
+		c.flavor = FlavorCloud
+		return c.flavor
+	}
+
+	switch strings.ToLower(c.config.Flavor) {
+	case FlavorServer, "datacenter", "data-center", "dc":
+		c.flavor = FlavorServer
+	case FlavorAuto:
+		flavor, answered := c.detectFlavor(ctx)
+		if !answered {
+			return flavor
+		}
+		c.flavor = flavor
+	default:
+		c.flavor = FlavorCloud
+	}
+	return c.flavor
+}
+
+// detectFlavor asks the instance what it is, and reports whether it
+// answered. Atlassian-hosted sites are always Cloud, and cloud is assumed
+// when serverInfo cannot be read.
+func (c *Client) detectFlavor(ctx context.Context) (string, bool) {
+	if u, err := url.Parse(c.baseURL); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return FlavorCloud, true
+	}
+
+	info, answered, err := c.getServerInfo(ctx)
+	if err != nil || strings.EqualFold(info.DeploymentType, "Cloud") {
+		return FlavorCloud, answered
+	}
+	return FlavorServer, true
+}
+
+// GetServerInfo fetches the instance's version and deployment type. It uses
+// API v2, which both Cloud and Server serve, so it works before the flavor is known.
+func (c *Client) GetServerInfo(ctx context.Context) (*JiraServerInfo, error) {
+	info, _, err := c.getServerInfo(ctx)
+	return info, err
+}
+
+// getServerInfo is GetServerInfo, also reporting whether the instance
+// answered at all, even with an error
+func (c *Client) getServerInfo(ctx context.Context) (*JiraServerInfo, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/rest/api/2/serverInfo", nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, true, c.parseErrorResponse(resp)
+	}
+
+	var info JiraServerInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &info, true, nil
+}
+
+// apiPath returns the REST API prefix for the client's flavor
+func (c *Client) apiPath(ctx context.Context) string {
+	if c.Flavor(ctx) == FlavorServer {
+		return "/rest/api/2"
+	}
+	return "/rest/api/3"
+}
+
+// authorization returns the Authorization header for the API token: a
+// Personal Access Token on Server, basic auth with the account email on Cloud
+func (c *Client) authorization(ctx context.Context) string {
+	if c.Flavor(ctx) == FlavorServer {
+		return "Bearer " + c.token
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.token))
+	return "Basic " + auth
+}
+
+// richText converts markdown into the body format the client's flavor expects
+func (c *Client) richText(ctx context.Context, markdown string) *RichText {
+	if c.Flavor(ctx) == FlavorServer {
+		return NewWikiText(markdown)
+	}
+	return NewADFText(markdown)
+}
+
+// userRef references a user by username on Server and by account ID on Cloud
+func (c *Client) userRef(ctx context.Context, user string) *JiraUserRef {
+	if c.Flavor(ctx) == FlavorServer {
+		return &JiraUserRef{Name: user}
+	}
+	return &JiraUserRef{AccountID: user}
+}
@@ -0,0 +1,157 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestServerFlavorRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/TEST-1/comment" {
+			t.Errorf("Expected API v2 comment path, got %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-pat" {
+			t.Errorf("Expected PAT bearer auth, got %q", auth)
+		}
+
+		var request map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body, ok := request["body"].(string); !ok || body != "Deployed *v2*" {
+			t.Errorf("Expected wiki markup body, got %#v", request["body"])
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"id":"1","body":"Deployed *v2*"}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{
+		URL:     server.URL,
+		Flavor:  "server",
+		Token:   "test-pat",
+		Project: "TEST",
+	})
+
+	comment, err := client.AddComment(context.Background(), "TEST-1", "Deployed **v2**")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if body := comment.Body.Markdown(); body != "Deployed **v2**" {
+		t.Errorf("Expected wiki body converted back to markdown, got %q", body)
+	}
+}
+
+func TestFlavorAutoDetection(t *testing.T) {
+	tests := []struct {
+		name           string
+		deploymentType string
+		status         int
+		expected       string
+	}{
+		{"data center", "Server", http.StatusOK, FlavorServer},
+		{"cloud", "Cloud", http.StatusOK, FlavorCloud},
+		{"unreachable serverInfo", "", http.StatusNotFound, FlavorCloud},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if r.URL.Path != "/rest/api/2/serverInfo" {
+					t.Errorf("Unexpected request to %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(JiraServerInfo{DeploymentType: tt.deploymentType})
+			}))
+			defer server.Close()
+
+			client := NewClient(&types.JiraConfig{URL: server.URL, Flavor: "auto"})
+			ctx := context.Background()
+
+			if flavor := client.Flavor(ctx); flavor != tt.expected {
+				t.Errorf("Expected flavor %s, got %s", tt.expected, flavor)
+			}
+			client.Flavor(ctx)
+			if requests != 1 {
+				t.Errorf("Expected detection to run once, got %d requests", requests)
+			}
+		})
+	}
+}
+
+func TestFlavorDetectionRetriesWithoutAnswer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JiraServerInfo{DeploymentType: "Server"})
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{URL: server.URL, Flavor: "auto"})
+
+	// A cancelled detection falls back to cloud without remembering it
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if flavor := client.Flavor(cancelled); flavor != FlavorCloud {
+		t.Errorf("Expected cloud while detection cannot run, got %s", flavor)
+	}
+
+	if flavor := client.Flavor(context.Background()); flavor != FlavorServer {
+		t.Errorf("Expected detection to run again and find server, got %s", flavor)
+	}
+	if flavor := client.Flavor(cancelled); flavor != FlavorServer {
+		t.Errorf("Expected the detected flavor to be kept, got %s", flavor)
+	}
+}
+
+func TestFlavorDefaultsToCloud(t *testing.T) {
+	client := NewClient(&types.JiraConfig{URL: "https://jira.example.com"})
+	ctx := context.Background()
+
+	if flavor := client.Flavor(ctx); flavor != FlavorCloud {
+		t.Errorf("Expected cloud by default, got %s", flavor)
+	}
+	if path := client.apiPath(ctx); path != "/rest/api/3" {
+		t.Errorf("Expected API v3 for cloud, got %s", path)
+	}
+	if ref := client.userRef(ctx, "abc123"); ref.AccountID != "abc123" || ref.Name != "" {
+		t.Errorf("Expected cloud user reference by account ID, got %+v", ref)
+	}
+}
+
+func TestRichTextJSON(t *testing.T) {
+	var fields struct {
+		Wiki *RichText `json:"wiki"`
+		ADF  *RichText `json:"adf"`
+		None *RichText `json:"none"`
+	}
+	data := `{"wiki":"h1. Title","adf":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"hi"}]}]},"none":null}`
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		t.Fatalf("Failed to decode rich text: %v", err)
+	}
+
+	if md := fields.Wiki.Markdown(); md != "# Title" {
+		t.Errorf("Expected wiki string decoded to markdown, got %q", md)
+	}
+	if md := fields.ADF.Markdown(); md != "hi" {
+		t.Errorf("Expected ADF document decoded to markdown, got %q", md)
+	}
+	if fields.None != nil || fields.None.Markdown() != "" {
+		t.Errorf("Expected null body to decode as nil, got %+v", fields.None)
+	}
+
+	encoded, err := json.Marshal(NewWikiText("**bold**"))
+	if err != nil {
+		t.Fatalf("Failed to encode rich text: %v", err)
+	}
+	if string(encoded) != `"*bold*"` {
+		t.Errorf("Expected wiki body encoded as a string, got %s", encoded)
+	}
+}
@@ -0,0 +1,170 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// DefaultConcurrency is how many requests a hierarchy fetch keeps in flight
+const DefaultConcurrency = 4
+
+// SubtaskBatchSize is how many tasks one subtask search covers, keeping the
+// JQL well inside Jira's query length limits
+const SubtaskBatchSize = 50
+
+// HierarchyProgress reports how far a hierarchy fetch has got
+type HierarchyProgress struct {
+	Batches      int // Subtask searches finished
+	TotalBatches int
+	Subtasks     int // Subtasks found so far
+}
+
+// HierarchyFailure is part of a hierarchy that could not be fetched
+type HierarchyFailure struct {
+	Keys []string // Tickets whose children are missing
+	Err  error
+}
+
+// Hierarchy is the descendants of an epic or task
+type Hierarchy struct {
+	Children []*types.Ticket // An epic's children
+	Subtasks []*types.Ticket // Subtasks of the task, or of the epic's tasks
+	Failures []HierarchyFailure
+}
+
+// Tickets returns the children followed by the subtasks
+func (h *Hierarchy) Tickets() []*types.Ticket {
+	return append(append([]*types.Ticket{}, h.Children...), h.Subtasks...)
+}
+
+// Err summarizes the failures, or returns nil when nothing is missing
+func (h *Hierarchy) Err() error {
+	if len(h.Failures) == 0 {
+		return nil
+	}
+
+	var messages []string
+	for _, failure := range h.Failures {
+		messages = append(messages, fmt.Sprintf("children of %s: %v", strings.Join(failure.Keys, ", "), failure.Err))
+	}
+	return fmt.Errorf("failed to fetch %d part(s) of the hierarchy:\n  %s", len(h.Failures), strings.Join(messages, "\n  "))
+}
+
+// FetchHierarchy fetches the descendants of an epic or task. An epic's
+// children come from one search; the subtasks of every task among them, or
+// of root itself when it is a task, come from `parent in (...)` searches of
+// up to SubtaskBatchSize tasks, run by a bounded pool of workers sharing
+// ctx. A search that fails is recorded in the result's Failures and the
+// rest carry on. progress, when set, is called after each subtask search.
+func (ts *TicketService) FetchHierarchy(ctx context.Context, root *types.Ticket, progress func(HierarchyProgress)) *Hierarchy {
+	hierarchy := &Hierarchy{}
+
+	var tasks []string
+	switch root.Type {
+	case types.TicketTypeEpic:
+		children, err := ts.EpicChildren(ctx, root.Key).Collect(ctx)
+		if err != nil {
+			hierarchy.Failures = append(hierarchy.Failures, HierarchyFailure{Keys: []string{root.Key}, Err: err})
+			return hierarchy
+		}
+		hierarchy.Children = children
+		for _, child := range children {
+			if child.Type == types.TicketTypeTask {
+				tasks = append(tasks, child.Key)
+			}
+		}
+	case types.TicketTypeTask:
+		tasks = []string{root.Key}
+	}
+
+	batches := batchKeys(tasks, SubtaskBatchSize)
+	found := make([][]*types.Ticket, len(batches))
+
+	var mu sync.Mutex
+	status := HierarchyProgress{TotalBatches: len(batches)}
+
+	errs := runPool(ctx, ts.concurrency(), len(batches), func(ctx context.Context, i int) error {
+		jql := fmt.Sprintf("parent in (%s) ORDER BY key ASC", strings.Join(batches[i], ", "))
+		subtasks, err := ts.Search(jql, nil).Collect(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+		found[i] = subtasks
+		status.Batches++
+		status.Subtasks += len(subtasks)
+		if progress != nil {
+			progress(status)
+		}
+		return err
+	})
+
+	// Keep the results in task order whatever order the workers finished in
+	for i, err := range errs {
+		if err != nil {
+			hierarchy.Failures = append(hierarchy.Failures, HierarchyFailure{Keys: batches[i], Err: err})
+			continue
+		}
+		hierarchy.Subtasks = append(hierarchy.Subtasks, found[i]...)
+	}
+
+	return hierarchy
+}
+
+// concurrency returns how many requests bulk fetches may keep in flight
+func (ts *TicketService) concurrency() int {
+	if ts.client.config != nil && ts.client.config.Concurrency > 0 {
+		return ts.client.config.Concurrency
+	}
+	return DefaultConcurrency
+}
+
+// batchKeys splits keys into batches of at most size
+func batchKeys(keys []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batches = append(batches, keys[start:end])
+	}
+	return batches
+}
+
+// runPool calls fn for jobs 0 to n-1 on at most workers goroutines and
+// returns each job's error. Jobs not yet started when ctx is done are not
+// run and get ctx's error.
+func runPool(ctx context.Context, workers, n int, fn func(ctx context.Context, job int) error) []error {
+	errs := make([]error, n)
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[job] = err
+					continue
+				}
+				errs[job] = fn(ctx, job)
+			}
+		}()
+	}
+
+	for job := 0; job < n; job++ {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errs
+}
@@ -0,0 +1,170 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newHierarchyServer serves an epic TEST-1 with tasks TEST-2 to TEST-(tasks+1),
+// each with one subtask. Subtask searches naming failKey fail.
+func newHierarchyServer(t *testing.T, tasks int, failKey string, inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		n := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(maxInFlight, max, n) {
+				break
+			}
+		}
+
+		jql := r.URL.Query().Get("jql")
+		var response JiraSearchResponse
+		switch {
+		case strings.HasPrefix(jql, "parent = TEST-1 "):
+			for i := 0; i < tasks; i++ {
+				response.Issues = append(response.Issues, JiraIssue{
+					Key:    fmt.Sprintf("TEST-%d", i+2),
+					Fields: JiraIssueFields{Summary: "Task", IssueType: JiraIssueType{Name: "Task"}},
+				})
+			}
+		case strings.HasPrefix(jql, "parent in ("):
+			// Give the other workers time to overlap
+			time.Sleep(20 * time.Millisecond)
+			keys := strings.Split(strings.TrimSuffix(strings.TrimPrefix(jql, "parent in ("), ") ORDER BY key ASC"), ", ")
+			for _, key := range keys {
+				if key == failKey {
+					w.WriteHeader(http.StatusBadRequest)
+					fmt.Fprint(w, `{"errorMessages": ["query failed"]}`)
+					return
+				}
+				response.Issues = append(response.Issues, JiraIssue{
+					Key:    key + "0",
+					Fields: JiraIssueFields{Summary: "Subtask", IssueType: JiraIssueType{Name: "Sub-task", Subtask: true}, Parent: &JiraLinkedIssue{Key: key}},
+				})
+			}
+		default:
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+
+		response.Total = len(response.Issues)
+		response.IsLast = true
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestFetchHierarchyBatchesSubtasks(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newHierarchyServer(t, 2*SubtaskBatchSize+10, "", &inFlight, &maxInFlight)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST", Concurrency: 2}))
+
+	var updates []HierarchyProgress
+	epic := types.NewTicket("TEST-1", "Epic", types.TicketTypeEpic)
+	hierarchy := service.FetchHierarchy(context.Background(), epic, func(p HierarchyProgress) {
+		updates = append(updates, p)
+	})
+
+	if err := hierarchy.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(hierarchy.Children) != 2*SubtaskBatchSize+10 || len(hierarchy.Subtasks) != len(hierarchy.Children) {
+		t.Fatalf("Expected %d children with a subtask each, got %d and %d", 2*SubtaskBatchSize+10, len(hierarchy.Children), len(hierarchy.Subtasks))
+	}
+
+	// Subtasks are in task order whichever batch finished first
+	for i, subtask := range hierarchy.Subtasks {
+		if parent := hierarchy.Children[i].Key; subtask.Relationships.ParentKey != parent {
+			t.Fatalf("Expected subtask %d under %s, got %s", i, parent, subtask.Relationships.ParentKey)
+		}
+	}
+
+	if len(updates) != 3 {
+		t.Fatalf("Expected progress after each of 3 batches, got %v", updates)
+	}
+	if last := updates[2]; last.Batches != 3 || last.TotalBatches != 3 || last.Subtasks != len(hierarchy.Subtasks) {
+		t.Errorf("Unexpected final progress: %+v", last)
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests in flight, saw %d", maxInFlight)
+	}
+}
+
+func TestFetchHierarchyReportsPartialFailure(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newHierarchyServer(t, SubtaskBatchSize+5, "TEST-3", &inFlight, &maxInFlight)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	epic := types.NewTicket("TEST-1", "Epic", types.TicketTypeEpic)
+	hierarchy := service.FetchHierarchy(context.Background(), epic, nil)
+
+	if len(hierarchy.Failures) != 1 || len(hierarchy.Failures[0].Keys) != SubtaskBatchSize {
+		t.Fatalf("Expected the first batch to fail, got %+v", hierarchy.Failures)
+	}
+	if len(hierarchy.Subtasks) != 5 {
+		t.Errorf("Expected the second batch's 5 subtasks, got %d", len(hierarchy.Subtasks))
+	}
+	if err := hierarchy.Err(); err == nil || !strings.Contains(err.Error(), "TEST-3") {
+		t.Errorf("Expected an error naming the missing tickets, got %v", err)
+	}
+	if len(hierarchy.Tickets()) != SubtaskBatchSize+10 {
+		t.Errorf("Expected children and the subtasks found, got %d tickets", len(hierarchy.Tickets()))
+	}
+}
+
+func TestFetchHierarchyOfTask(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newHierarchyServer(t, 0, "", &inFlight, &maxInFlight)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	task := types.NewTicket("TEST-7", "Task", types.TicketTypeTask)
+	hierarchy := service.FetchHierarchy(context.Background(), task, nil)
+	if hierarchy.Err() != nil || len(hierarchy.Children) != 0 || len(hierarchy.Subtasks) != 1 || hierarchy.Subtasks[0].Key != "TEST-70" {
+		t.Errorf("Unexpected hierarchy: %+v", hierarchy)
+	}
+}
+
+func TestRunPoolStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	ran := 0
+	errs := runPool(ctx, 1, 5, func(ctx context.Context, job int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		ran++
+		if job == 1 {
+			cancel()
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	if ran != 2 {
+		t.Errorf("Expected jobs after the cancel to be skipped, ran %d", ran)
+	}
+	if errs[0] != nil || errs[1] == nil || !errors.Is(errs[4], context.Canceled) {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
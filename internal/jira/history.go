@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// GetChangelog fetches an issue's complete changelog. Cloud pages through
+// it; Server only returns it expanded on the issue.
+func (c *Client) GetChangelog(ctx context.Context, issueKey string) ([]JiraChangelogHistory, error) {
+	if c.Flavor(ctx) == FlavorServer {
+		issue, err := c.GetIssue(ctx, issueKey)
+		if err != nil {
+			return nil, err
+		}
+		if issue.Changelog == nil {
+			return nil, nil
+		}
+		return issue.Changelog.Histories, nil
+	}
+
+	var histories []JiraChangelogHistory
+	for {
+		endpoint := fmt.Sprintf("/issue/%s/changelog?startAt=%d", issueKey, len(histories))
+		resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != 200 {
+			err := c.parseErrorResponse(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var page JiraChangelogPage
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		histories = append(histories, page.Values...)
+		if page.IsLast || len(page.Values) == 0 || len(histories) >= page.Total {
+			return histories, nil
+		}
+	}
+}
+
+// GetHistory fetches a ticket's changes, oldest first
+func (ts *TicketService) GetHistory(ctx context.Context, ticketKey string) ([]*types.HistoryEntry, error) {
+	histories, err := ts.client.GetChangelog(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch history of %s: %v", ticketKey, err)
+	}
+
+	entries := make([]*types.HistoryEntry, 0, len(histories))
+	for i := range histories {
+		entries = append(entries, convertHistory(&histories[i]))
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+
+	return entries, nil
+}
+
+// convertHistory converts a changelog entry to our internal format
+func convertHistory(history *JiraChangelogHistory) *types.HistoryEntry {
+	entry := &types.HistoryEntry{
+		ID:      history.ID,
+		Author:  history.Author.DisplayName,
+		Created: history.Created.Time,
+	}
+	for _, item := range history.Items {
+		entry.Changes = append(entry.Changes, types.FieldChange{
+			Field: item.Field,
+			From:  item.From,
+			To:    item.To,
+		})
+	}
+	return entry
+}
@@ -0,0 +1,107 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestExtractParentRelationships(t *testing.T) {
+	tests := []struct {
+		name     string
+		issue    string
+		parent   string
+		children []string
+	}{
+		{
+			name:     "parent field",
+			issue:    `{"key": "TEST-2", "fields": {"parent": {"key": "TEST-1"}, "subtasks": [{"key": "TEST-3"}, {"key": "TEST-4"}]}}`,
+			parent:   "TEST-1",
+			children: []string{"TEST-3", "TEST-4"},
+		},
+		{
+			name:   "epic link field",
+			issue:  `{"key": "TEST-2", "fields": {"customfield_10014": "TEST-9"}}`,
+			parent: "TEST-9",
+		},
+		{
+			name: "changelog",
+			issue: `{"key": "TEST-2", "fields": {}, "changelog": {"histories": [
+				{"created": "2024-03-02T10:00:00.000+0000", "items": [{"field": "IssueParentAssociation", "toString": "TEST-7"}]},
+				{"created": "2024-03-01T10:00:00.000+0000", "items": [{"field": "Epic Link", "fieldId": "customfield_10014", "toString": "TEST-5"}]}
+			]}}`,
+			parent: "TEST-7",
+		},
+		{
+			name: "changelog removal",
+			issue: `{"key": "TEST-2", "fields": {}, "changelog": {"histories": [
+				{"created": "2024-03-01T10:00:00.000+0000", "items": [{"field": "Epic Link", "toString": "TEST-5"}]},
+				{"created": "2024-03-02T10:00:00.000+0000", "items": [{"field": "Epic Link", "fromString": "TEST-5"}]}
+			]}}`,
+		},
+	}
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: "https://example.atlassian.net", EpicLinkField: "customfield_10014"}))
+
+	for _, tt := range tests {
+		var issue JiraIssue
+		if err := json.Unmarshal([]byte(tt.issue), &issue); err != nil {
+			t.Fatalf("%s: failed to decode issue: %v", tt.name, err)
+		}
+
+		ticket := service.convertJiraIssueToTicket(context.Background(), &issue)
+		if ticket.Relationships.ParentKey != tt.parent {
+			t.Errorf("%s: expected parent %q, got %q", tt.name, tt.parent, ticket.Relationships.ParentKey)
+		}
+		if fmt.Sprint(ticket.Relationships.Children) != fmt.Sprint(append([]string{}, tt.children...)) {
+			t.Errorf("%s: expected children %v, got %v", tt.name, tt.children, ticket.Relationships.Children)
+		}
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/TEST-1/changelog" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Two pages, newest first
+		if r.URL.Query().Get("startAt") == "0" {
+			fmt.Fprint(w, `{"startAt": 0, "total": 2, "isLast": false, "values": [
+				{"id": "2", "author": {"displayName": "Jane"}, "created": "2024-03-02T10:00:00.000+0000", "items": [
+					{"field": "status", "fromString": "To Do", "toString": "In Progress"},
+					{"field": "assignee", "toString": "Jane"}
+				]}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"startAt": 1, "total": 2, "isLast": true, "values": [
+			{"id": "1", "author": {"displayName": "Sam"}, "created": "2024-03-01T10:00:00.000+0000", "items": [
+				{"field": "priority", "fromString": "Medium", "toString": "High"}
+			]}
+		]}`)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	history, err := service.GetHistory(context.Background(), "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(history) != 2 || history[0].ID != "1" || history[1].ID != "2" {
+		t.Fatalf("Expected both entries oldest first, got %+v", history)
+	}
+	status := history[1].Changes[0]
+	if history[1].Author != "Jane" || status.Field != "status" || status.From != "To Do" || status.To != "In Progress" {
+		t.Errorf("Unexpected entry: %+v", history[1])
+	}
+}
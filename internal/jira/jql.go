@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jqlShorthands are the words ExpandJQL expands on their own
+var jqlShorthands = map[string]string{
+	"mine":       "assignee = currentUser()",
+	"unassigned": "assignee is EMPTY",
+	"reported":   "reporter = currentUser()",
+	"open":       "statusCategory != Done",
+	"done":       "statusCategory = Done",
+	"sprint":     "sprint in openSprints()",
+}
+
+// jqlShorthandFields are the "name:value" shorthands ExpandJQL expands, and
+// the JQL clause each value is put into
+var jqlShorthandFields = map[string]string{
+	"project": "project = %s",
+	"type":    "issuetype = %s",
+	"status":  "status = %s",
+	"label":   "labels = %s",
+	"text":    "text ~ %s",
+}
+
+// relativeDate matches the relative dates JQL accepts, such as 7d or 2w
+var relativeDate = regexp.MustCompile(`^\d+[mhdw]$`)
+
+// ExpandJQL turns a search written in shorthands into JQL. A query made only
+// of shorthands such as "mine open updated:7d" becomes the AND of their
+// clauses, limited to project unless a project: shorthand is given, and
+// ordered by last update. Any other query is taken to be JQL and returned
+// as it is.
+//
+// Shorthands are mine, unassigned, reported, open, done and sprint, the
+// fields project:, type:, status:, label: and text:, whose values may be
+// double-quoted (status:"In Progress"), and the dates updated: and created:
+// with a relative age (30m, 12h, 7d, 2w).
+func ExpandJQL(query, project string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", fmt.Errorf("empty search")
+	}
+
+	terms, ok := splitShorthands(query)
+	if !ok {
+		return query, nil
+	}
+
+	var clauses []string
+	scoped := false
+	for _, term := range terms {
+		clause, ok, err := expandShorthand(term)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return query, nil
+		}
+		if strings.HasPrefix(strings.ToLower(term), "project:") {
+			scoped = true
+		}
+		clauses = append(clauses, clause)
+	}
+
+	if !scoped && project != "" {
+		clauses = append([]string{"project = " + project}, clauses...)
+	}
+	return strings.Join(clauses, " AND ") + " ORDER BY updated DESC", nil
+}
+
+// expandShorthand returns the JQL clause for one shorthand term. It reports
+// false for terms that are not shorthands.
+func expandShorthand(term string) (string, bool, error) {
+	lower := strings.ToLower(term)
+	if clause, ok := jqlShorthands[lower]; ok {
+		return clause, true, nil
+	}
+
+	name, value, ok := strings.Cut(term, ":")
+	if !ok || value == "" {
+		return "", false, nil
+	}
+	name = strings.ToLower(name)
+
+	switch name {
+	case "updated", "created":
+		age := strings.ToLower(value)
+		if !relativeDate.MatchString(age) {
+			return "", false, fmt.Errorf("%s: %q is not an age such as 12h, 7d or 2w", name, value)
+		}
+		return fmt.Sprintf("%s >= -%s", name, age), true, nil
+	}
+
+	format, ok := jqlShorthandFields[name]
+	if !ok {
+		return "", false, nil
+	}
+
+	return fmt.Sprintf(format, quoteJQL(value)), true, nil
+}
+
+// splitShorthands splits a query into whitespace-separated terms, keeping
+// double-quoted values together and unquoting them. It reports false when
+// a quote is left open.
+func splitShorthands(query string) ([]string, bool) {
+	var terms []string
+	var term strings.Builder
+	quoted := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if term.Len() > 0 {
+				terms = append(terms, term.String())
+				term.Reset()
+			}
+		default:
+			term.WriteRune(r)
+		}
+	}
+	if term.Len() > 0 {
+		terms = append(terms, term.String())
+	}
+	return terms, !quoted
+}
+
+// quoteJQL quotes a value for use in JQL
+func quoteJQL(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,47 @@
+package jira
+
+import "testing"
+
+func TestExpandJQL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"mine", `project = PROJ AND assignee = currentUser() ORDER BY updated DESC`},
+		{"mine open updated:7d", `project = PROJ AND assignee = currentUser() AND statusCategory != Done AND updated >= -7d ORDER BY updated DESC`},
+		{"project:OPS created:2W", `project = "OPS" AND created >= -2w ORDER BY updated DESC`},
+		{`status:"In Progress" label:auth`, `project = PROJ AND status = "In Progress" AND labels = "auth" ORDER BY updated DESC`},
+		{`text:"login page"`, `project = PROJ AND text ~ "login page" ORDER BY updated DESC`},
+
+		// Anything else is JQL
+		{`assignee = currentUser() ORDER BY created`, `assignee = currentUser() ORDER BY created`},
+		{`mine AND priority = High`, `mine AND priority = High`},
+		{`status:"In Progress`, `status:"In Progress`},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandJQL(tt.query, "PROJ")
+		if err != nil {
+			t.Errorf("ExpandJQL(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandJQL(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExpandJQLWithoutProject(t *testing.T) {
+	got, err := ExpandJQL("open", "")
+	if err != nil || got != "statusCategory != Done ORDER BY updated DESC" {
+		t.Errorf("Unexpected expansion %q (%v)", got, err)
+	}
+}
+
+func TestExpandJQLRejectsBadAges(t *testing.T) {
+	for _, query := range []string{"updated:week", "mine created:7", ""} {
+		if _, err := ExpandJQL(query, "PROJ"); err == nil {
+			t.Errorf("Expected an error for %q", query)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// GetIssueLinkTypes fetches the issue link types defined on the site
+func (c *Client) GetIssueLinkTypes(ctx context.Context) ([]JiraIssueLinkType, error) {
+	resp, err := c.doRequest(ctx, "GET", "/issueLinkType", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var response JiraIssueLinkTypesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return response.IssueLinkTypes, nil
+}
+
+// CreateIssueLink links two issues
+func (c *Client) CreateIssueLink(ctx context.Context, request *JiraCreateIssueLinkRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/issueLink", strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// DeleteIssueLink removes an issue link by ID
+func (c *Client) DeleteIssueLink(ctx context.Context, linkID string) error {
+	resp, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/issueLink/%s", linkID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// ResolveLinkRelation finds the link type and direction a relation names.
+// A relation is either phrase of a link type ("blocks", "is blocked by",
+// "relates to") or the type name itself, which reads outward.
+func ResolveLinkRelation(linkTypes []JiraIssueLinkType, relation string) (*JiraIssueLinkType, string, error) {
+	want := normalizeRelation(relation)
+
+	for i := range linkTypes {
+		if normalizeRelation(linkTypes[i].Outward) == want {
+			return &linkTypes[i], types.LinkOutward, nil
+		}
+	}
+	for i := range linkTypes {
+		if normalizeRelation(linkTypes[i].Inward) == want {
+			return &linkTypes[i], types.LinkInward, nil
+		}
+	}
+	for i := range linkTypes {
+		if normalizeRelation(linkTypes[i].Name) == want {
+			return &linkTypes[i], types.LinkOutward, nil
+		}
+	}
+
+	var phrases []string
+	for _, linkType := range linkTypes {
+		phrases = append(phrases, linkType.Outward)
+		if linkType.Inward != linkType.Outward {
+			phrases = append(phrases, linkType.Inward)
+		}
+	}
+	return nil, "", fmt.Errorf("unknown relation %q; available: %s", relation, strings.Join(phrases, ", "))
+}
+
+// LinkTickets creates a link reading "from <relation> to" and returns the
+// phrase used
+func (ts *TicketService) LinkTickets(ctx context.Context, from, relation, to string) (string, error) {
+	linkTypes, err := ts.client.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch link types: %v", err)
+	}
+
+	linkType, direction, err := ResolveLinkRelation(linkTypes, relation)
+	if err != nil {
+		return "", err
+	}
+
+	// The inward issue is the subject of the outward phrase
+	request := &JiraCreateIssueLinkRequest{
+		Type:         JiraIssueLinkType{Name: linkType.Name},
+		InwardIssue:  JiraLinkedIssue{Key: from},
+		OutwardIssue: JiraLinkedIssue{Key: to},
+	}
+	phrase := linkType.Outward
+	if direction == types.LinkInward {
+		request.InwardIssue, request.OutwardIssue = request.OutwardIssue, request.InwardIssue
+		phrase = linkType.Inward
+	}
+
+	if err := ts.client.CreateIssueLink(ctx, request); err != nil {
+		return "", fmt.Errorf("failed to link %s to %s: %v", from, to, err)
+	}
+
+	return phrase, nil
+}
+
+// UnlinkTickets removes the links reading "from <relation> to" and returns
+// how many were removed
+func (ts *TicketService) UnlinkTickets(ctx context.Context, from, relation, to string) (int, error) {
+	linkTypes, err := ts.client.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch link types: %v", err)
+	}
+
+	linkType, direction, err := ResolveLinkRelation(linkTypes, relation)
+	if err != nil {
+		return 0, err
+	}
+
+	ticket, err := ts.GetTicket(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+
+	// Symmetric types such as "relates to" match links made from either side
+	symmetric := linkType.Inward == linkType.Outward
+
+	removed := 0
+	for _, link := range ticket.Relationships.Links {
+		if link.Type != linkType.Name || (link.Direction != direction && !symmetric) || !strings.EqualFold(link.Key, to) {
+			continue
+		}
+		if err := ts.client.DeleteIssueLink(ctx, link.ID); err != nil {
+			return removed, fmt.Errorf("failed to remove link %s: %v", link.ID, err)
+		}
+		removed++
+	}
+
+	if removed == 0 {
+		return 0, fmt.Errorf("no %q link from %s to %s", linkType.phrase(direction), from, to)
+	}
+	return removed, nil
+}
+
+// phrase returns how the link type reads in a direction
+func (lt *JiraIssueLinkType) phrase(direction string) string {
+	if direction == types.LinkInward {
+		return lt.Inward
+	}
+	return lt.Outward
+}
+
+// convertIssueLinks converts issue links to the ticket's point of view
+func convertIssueLinks(links []JiraIssueLink) []types.TicketLink {
+	var converted []types.TicketLink
+	for _, link := range links {
+		other, direction := link.OutwardIssue, types.LinkOutward
+		if other == nil {
+			other, direction = link.InwardIssue, types.LinkInward
+		}
+		if other == nil {
+			continue
+		}
+
+		ticketLink := types.TicketLink{
+			ID:        link.ID,
+			Type:      link.Type.Name,
+			Direction: direction,
+			Relation:  link.Type.phrase(direction),
+			Key:       other.Key,
+		}
+		if other.Fields != nil {
+			ticketLink.Title = other.Fields.Summary
+			ticketLink.Status = other.Fields.Status.Name
+			ticketLink.Done = other.Fields.Status.StatusCategory != nil && other.Fields.Status.StatusCategory.Key == "done"
+		}
+		converted = append(converted, ticketLink)
+	}
+	return converted
+}
+
+// normalizeRelation folds case, separators and the "is ... " and "... to"
+// wrappers so that "blocked-by" matches "is blocked by"
+func normalizeRelation(relation string) string {
+	relation = strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(relation))
+	relation = strings.Join(strings.Fields(relation), " ")
+	relation = strings.TrimPrefix(relation, "is ")
+	return strings.TrimSuffix(relation, " to")
+}
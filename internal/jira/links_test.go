@@ -0,0 +1,163 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+var testLinkTypes = []JiraIssueLinkType{
+	{ID: "1", Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+	{ID: "2", Name: "Relates", Inward: "relates to", Outward: "relates to"},
+	{ID: "3", Name: "Duplicate", Inward: "is duplicated by", Outward: "duplicates"},
+}
+
+func TestResolveLinkRelation(t *testing.T) {
+	tests := []struct {
+		relation  string
+		name      string
+		direction string
+	}{
+		{"blocks", "Blocks", types.LinkOutward},
+		{"is blocked by", "Blocks", types.LinkInward},
+		{"blocked-by", "Blocks", types.LinkInward},
+		{"Relates", "Relates", types.LinkOutward},
+		{"relates_to", "Relates", types.LinkOutward},
+		{"duplicate", "Duplicate", types.LinkOutward},
+		{"is-duplicated-by", "Duplicate", types.LinkInward},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.relation, func(t *testing.T) {
+			linkType, direction, err := ResolveLinkRelation(testLinkTypes, tt.relation)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if linkType.Name != tt.name || direction != tt.direction {
+				t.Errorf("Expected %s %s, got %s %s", tt.name, tt.direction, linkType.Name, direction)
+			}
+		})
+	}
+
+	if _, _, err := ResolveLinkRelation(testLinkTypes, "causes"); err == nil {
+		t.Error("Expected error for unknown relation")
+	}
+}
+
+func TestConvertIssueLinks(t *testing.T) {
+	var links []JiraIssueLink
+	err := json.Unmarshal([]byte(`[
+		{"id": "10", "type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+		 "inwardIssue": {"key": "TEST-2", "fields": {"summary": "Schema", "status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}}}},
+		{"id": "11", "type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+		 "inwardIssue": {"key": "TEST-3", "fields": {"summary": "Done work", "status": {"name": "Done", "statusCategory": {"key": "done"}}}}},
+		{"id": "12", "type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"},
+		 "outwardIssue": {"key": "TEST-4"}}
+	]`), &links)
+	if err != nil {
+		t.Fatalf("Failed to decode links: %v", err)
+	}
+
+	ticket := types.NewTicket("TEST-1", "Links", types.TicketTypeTask)
+	ticket.Relationships.Links = convertIssueLinks(links)
+
+	if len(ticket.Relationships.Links) != 3 {
+		t.Fatalf("Expected 3 links, got %d", len(ticket.Relationships.Links))
+	}
+	first := ticket.Relationships.Links[0]
+	if first.Direction != types.LinkInward || first.Relation != "is blocked by" || first.Title != "Schema" || first.Done {
+		t.Errorf("Unexpected inward link: %+v", first)
+	}
+	last := ticket.Relationships.Links[2]
+	if last.Direction != types.LinkOutward || last.Relation != "blocks" || last.Key != "TEST-4" {
+		t.Errorf("Unexpected outward link: %+v", last)
+	}
+
+	// Only unfinished inward blockers count
+	blockers := ticket.BlockedBy()
+	if len(blockers) != 1 || blockers[0] != "TEST-2" {
+		t.Errorf("Expected TEST-2 to block, got %v", blockers)
+	}
+}
+
+// newLinkServer serves link types and records created and deleted links
+func newLinkServer(t *testing.T, issue string, created *JiraCreateIssueLinkRequest, deleted *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/issueLinkType":
+			json.NewEncoder(w).Encode(JiraIssueLinkTypesResponse{IssueLinkTypes: testLinkTypes})
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issueLink":
+			if err := json.NewDecoder(r.Body).Decode(created); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/issue/TEST-1":
+			w.Write([]byte(issue))
+		case r.Method == "DELETE":
+			*deleted = append(*deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestLinkTickets(t *testing.T) {
+	var created JiraCreateIssueLinkRequest
+	server := newLinkServer(t, "", &created, nil)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	phrase, err := service.LinkTickets(context.Background(), "TEST-1", "blocks", "TEST-2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if phrase != "blocks" || created.Type.Name != "Blocks" || created.InwardIssue.Key != "TEST-1" || created.OutwardIssue.Key != "TEST-2" {
+		t.Errorf("Unexpected link %q: %+v", phrase, created)
+	}
+
+	// An inward phrase swaps the issues
+	phrase, err = service.LinkTickets(context.Background(), "TEST-1", "is blocked by", "TEST-3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if phrase != "is blocked by" || created.InwardIssue.Key != "TEST-3" || created.OutwardIssue.Key != "TEST-1" {
+		t.Errorf("Unexpected link %q: %+v", phrase, created)
+	}
+}
+
+func TestUnlinkTickets(t *testing.T) {
+	issue := `{"key": "TEST-1", "fields": {"summary": "Links", "issuetype": {"name": "Task"}, "issuelinks": [
+		{"id": "10", "type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "outwardIssue": {"key": "TEST-2"}},
+		{"id": "11", "type": {"name": "Blocks", "inward": "is blocked by", "outward": "blocks"}, "inwardIssue": {"key": "TEST-2"}},
+		{"id": "12", "type": {"name": "Relates", "inward": "relates to", "outward": "relates to"}, "inwardIssue": {"key": "TEST-3"}}
+	]}}`
+	var deleted []string
+	server := newLinkServer(t, issue, nil, &deleted)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	removed, err := service.UnlinkTickets(context.Background(), "TEST-1", "blocks", "test-2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if removed != 1 || len(deleted) != 1 || deleted[0] != "/rest/api/3/issueLink/10" {
+		t.Errorf("Expected only link 10 removed, got %v", deleted)
+	}
+
+	// Symmetric links match from either side
+	if _, err := service.UnlinkTickets(context.Background(), "TEST-1", "relates to", "TEST-3"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := service.UnlinkTickets(context.Background(), "TEST-1", "duplicates", "TEST-2"); err == nil {
+		t.Error("Expected error when no link matches")
+	}
+}
@@ -0,0 +1,368 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// DefaultMetadataTTL is how long cached project metadata is used before it is refetched
+const DefaultMetadataTTL = 24 * time.Hour
+
+// Cache persists named JSON values between runs
+type Cache interface {
+	SaveCache(name string, value interface{}) error
+	LoadCache(name string, value interface{}) (bool, error)
+}
+
+// Project styles: company-managed (classic) and team-managed (next-gen)
+const (
+	ProjectStyleClassic = "classic"
+	ProjectStyleNextGen = "next-gen"
+)
+
+// sprintFieldSchema is the custom type of Jira Software's Sprint field
+const sprintFieldSchema = "com.pyxis.greenhopper.jira:gh-sprint"
+
+// FieldParent is the field that links an issue to its parent. Team-managed
+// projects, and company-managed ones on newer sites, use it for epics too.
+const FieldParent = "parent"
+
+// ProjectMetadata holds the issue types, priorities and fields of a project
+type ProjectMetadata struct {
+	Project    string          `json:"project"`
+	FetchedAt  time.Time       `json:"fetched_at"`
+	Style      string          `json:"style,omitempty"`
+	IssueTypes []JiraIssueType `json:"issue_types"`
+	Priorities []JiraPriority  `json:"priorities"`
+	Fields     []JiraField     `json:"fields"`
+}
+
+// IsStale reports whether the metadata is older than ttl
+func (pm *ProjectMetadata) IsStale(ttl time.Duration) bool {
+	return time.Since(pm.FetchedAt) > ttl
+}
+
+// IssueType finds an issue type by name (case-insensitive) or ID
+func (pm *ProjectMetadata) IssueType(name string) (*JiraIssueType, bool) {
+	for i := range pm.IssueTypes {
+		if pm.IssueTypes[i].ID == name || strings.EqualFold(pm.IssueTypes[i].Name, name) {
+			return &pm.IssueTypes[i], true
+		}
+	}
+	return nil, false
+}
+
+// Priority finds a priority by name (case-insensitive) or ID
+func (pm *ProjectMetadata) Priority(name string) (*JiraPriority, bool) {
+	for i := range pm.Priorities {
+		if pm.Priorities[i].ID == name || strings.EqualFold(pm.Priorities[i].Name, name) {
+			return &pm.Priorities[i], true
+		}
+	}
+	return nil, false
+}
+
+// Field finds a field by name (case-insensitive) or ID
+func (pm *ProjectMetadata) Field(name string) (*JiraField, bool) {
+	for i := range pm.Fields {
+		if pm.Fields[i].ID == name || strings.EqualFold(pm.Fields[i].Name, name) {
+			return &pm.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// IsTeamManaged reports whether the project is team-managed (next-gen)
+func (pm *ProjectMetadata) IsTeamManaged() bool {
+	return pm.Style == ProjectStyleNextGen
+}
+
+// EpicParentField returns the field that attaches issues to their epic:
+// parent for team-managed projects and sites without an Epic Link field,
+// otherwise the ID of the Epic Link field (epicLinkField when it exists)
+func (pm *ProjectMetadata) EpicParentField(epicLinkField string) string {
+	if pm.IsTeamManaged() {
+		return FieldParent
+	}
+	if epicLinkField != "" {
+		if field, ok := pm.Field(epicLinkField); ok {
+			return field.ID
+		}
+	}
+	if field, ok := pm.Field("Epic Link"); ok {
+		return field.ID
+	}
+	return FieldParent
+}
+
+// SprintField returns the ID of the Sprint custom field, or "" when the site
+// has none, as without Jira Software
+func (pm *ProjectMetadata) SprintField() string {
+	for i := range pm.Fields {
+		if schema := pm.Fields[i].Schema; schema != nil && schema.Custom == sprintFieldSchema {
+			return pm.Fields[i].ID
+		}
+	}
+	if field, ok := pm.Field("Sprint"); ok && field.Custom {
+		return field.ID
+	}
+	return ""
+}
+
+// MetadataService discovers project metadata from Jira and caches it
+type MetadataService struct {
+	client   *Client
+	cache    Cache
+	ttl      time.Duration
+	mu       sync.Mutex
+	projects map[string]*ProjectMetadata
+}
+
+// NewMetadataService creates a metadata service. cache may be nil, in which
+// case metadata is only kept in memory for the life of the process.
+func NewMetadataService(client *Client, cache Cache) *MetadataService {
+	ttl := DefaultMetadataTTL
+	if client.config != nil && client.config.MetadataTTL > 0 {
+		ttl = client.config.MetadataTTL
+	}
+
+	return &MetadataService{
+		client:   client,
+		cache:    cache,
+		ttl:      ttl,
+		projects: make(map[string]*ProjectMetadata),
+	}
+}
+
+// Project returns metadata for a project, using the cache while it is fresh
+func (ms *MetadataService) Project(ctx context.Context, projectKey string) (*ProjectMetadata, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if meta, ok := ms.projects[projectKey]; ok && !meta.IsStale(ms.ttl) {
+		return meta, nil
+	}
+
+	if ms.cache != nil {
+		var meta ProjectMetadata
+		found, err := ms.cache.LoadCache(metadataCacheName(projectKey), &meta)
+		if err == nil && found && !meta.IsStale(ms.ttl) {
+			ms.projects[projectKey] = &meta
+			return &meta, nil
+		}
+	}
+
+	return ms.refresh(ctx, projectKey)
+}
+
+// Refresh refetches metadata for a project and rewrites the cache
+func (ms *MetadataService) Refresh(ctx context.Context, projectKey string) (*ProjectMetadata, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.refresh(ctx, projectKey)
+}
+
+// refresh fetches metadata from Jira; callers must hold ms.mu
+func (ms *MetadataService) refresh(ctx context.Context, projectKey string) (*ProjectMetadata, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required to load metadata")
+	}
+
+	issueTypes, err := ms.client.GetCreateIssueTypes(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue types for %s: %v", projectKey, err)
+	}
+
+	priorities, err := ms.client.GetPriorities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch priorities: %v", err)
+	}
+
+	fields, err := ms.client.GetFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fields: %v", err)
+	}
+
+	// Only Cloud reports the project style; anything else is company-managed
+	style := ProjectStyleClassic
+	if project, err := ms.client.GetProject(ctx, projectKey); err == nil && project.IsTeamManaged() {
+		style = ProjectStyleNextGen
+	}
+
+	meta := &ProjectMetadata{
+		Project:    projectKey,
+		FetchedAt:  time.Now(),
+		Style:      style,
+		IssueTypes: issueTypes,
+		Priorities: priorities,
+		Fields:     fields,
+	}
+	ms.projects[projectKey] = meta
+
+	if ms.cache != nil {
+		if err := ms.cache.SaveCache(metadataCacheName(projectKey), meta); err != nil {
+			return nil, fmt.Errorf("failed to cache metadata for %s: %v", projectKey, err)
+		}
+	}
+
+	return meta, nil
+}
+
+// IssueTypeID resolves the Jira issue type ID for a jit ticket type in a project
+func (ms *MetadataService) IssueTypeID(ctx context.Context, projectKey, ticketType string) (string, error) {
+	meta, err := ms.Project(ctx, projectKey)
+	if err != nil {
+		return "", err
+	}
+
+	// An explicit mapping in the config always wins
+	var name string
+	if ms.client.config != nil {
+		name = ms.client.config.IssueTypes[strings.ToLower(ticketType)]
+	}
+	if name != "" {
+		if issueType, ok := meta.IssueType(name); ok {
+			return issueType.ID, nil
+		}
+		return "", fmt.Errorf("issue type %q configured for %s not found in project %s", name, ticketType, projectKey)
+	}
+
+	var issueType *JiraIssueType
+	switch ticketType {
+	case types.TicketTypeEpic:
+		issueType = meta.findIssueType(func(it *JiraIssueType) bool { return strings.EqualFold(it.Name, "Epic") },
+			func(it *JiraIssueType) bool { return it.HierarchyLevel == 1 })
+	case types.TicketTypeTask:
+		issueType = meta.findIssueType(func(it *JiraIssueType) bool { return strings.EqualFold(it.Name, "Task") },
+			func(it *JiraIssueType) bool { return strings.EqualFold(it.Name, "Story") },
+			func(it *JiraIssueType) bool { return !it.Subtask && it.HierarchyLevel == 0 })
+	case types.TicketTypeSubtask:
+		issueType = meta.findIssueType(func(it *JiraIssueType) bool { return it.Subtask })
+	default:
+		return "", fmt.Errorf("unknown ticket type: %s", ticketType)
+	}
+
+	if issueType == nil {
+		return "", fmt.Errorf("no %s issue type available in project %s (set jira.issue_types in the config)", ticketType, projectKey)
+	}
+	return issueType.ID, nil
+}
+
+// findIssueType returns the first issue type matching the earliest matcher
+func (pm *ProjectMetadata) findIssueType(matchers ...func(*JiraIssueType) bool) *JiraIssueType {
+	for _, match := range matchers {
+		for i := range pm.IssueTypes {
+			if match(&pm.IssueTypes[i]) {
+				return &pm.IssueTypes[i]
+			}
+		}
+	}
+	return nil
+}
+
+// metadataCacheName returns the cache entry name for a project
+func metadataCacheName(projectKey string) string {
+	return "metadata-" + projectKey
+}
+
+// GetCreateIssueTypes fetches the issue types that can be created in a project.
+// Sites without the createmeta issuetypes endpoint fall back to the project details.
+func (c *Client) GetCreateIssueTypes(ctx context.Context, projectKey string) ([]JiraIssueType, error) {
+	endpoint := fmt.Sprintf("/issue/createmeta/%s/issuetypes?maxResults=200", url.PathEscape(projectKey))
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		project, err := c.GetProject(ctx, projectKey)
+		if err != nil {
+			return nil, err
+		}
+		return project.IssueTypes, nil
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var response JiraCreateMetaIssueTypesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	if len(response.IssueTypes) == 0 {
+		return response.Values, nil
+	}
+	return response.IssueTypes, nil
+}
+
+// GetProject fetches a project with its style and issue types
+func (c *Client) GetProject(ctx context.Context, projectKey string) (*JiraProjectDetails, error) {
+	resp, err := c.doRequest(ctx, "GET", "/project/"+url.PathEscape(projectKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var project JiraProjectDetails
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &project, nil
+}
+
+// GetPriorities fetches all priorities defined on the site
+func (c *Client) GetPriorities(ctx context.Context) ([]JiraPriority, error) {
+	resp, err := c.doRequest(ctx, "GET", "/priority", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var priorities []JiraPriority
+	if err := json.NewDecoder(resp.Body).Decode(&priorities); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return priorities, nil
+}
+
+// GetFields fetches all system and custom field definitions
+func (c *Client) GetFields(ctx context.Context) ([]JiraField, error) {
+	resp, err := c.doRequest(ctx, "GET", "/field", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var fields []JiraField
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newMetadataServer serves createmeta, project, priority and field metadata
+// for a company-managed project. When
+// requests is non-nil it counts every metadata request.
+func newMetadataServer(t *testing.T, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			*requests++
+		}
+
+		var response interface{}
+		switch r.URL.Path {
+		case "/rest/api/3/issue/createmeta/TEST/issuetypes":
+			response = JiraCreateMetaIssueTypesResponse{
+				IssueTypes: []JiraIssueType{
+					{ID: "10100", Name: "Epic", HierarchyLevel: 1},
+					{ID: "10101", Name: "Task"},
+					{ID: "10102", Name: "Story"},
+					{ID: "10103", Name: "Sub-task", Subtask: true, HierarchyLevel: -1},
+				},
+			}
+		case "/rest/api/3/project/TEST":
+			response = JiraProjectDetails{JiraProject: JiraProject{ID: "1", Key: "TEST", Style: ProjectStyleClassic}}
+		case "/rest/api/3/priority":
+			response = []JiraPriority{
+				{ID: "1", Name: "Highest"},
+				{ID: "2", Name: "High"},
+				{ID: "3", Name: "Medium"},
+				{ID: "10000", Name: "P0 - Urgent"},
+			}
+		case "/rest/api/3/field":
+			response = []JiraField{
+				{ID: "summary", Name: "Summary"},
+				{ID: "customfield_10014", Name: "Epic Link", Custom: true},
+				{ID: "customfield_10020", Name: "Sprint", Custom: true, Schema: &JiraFieldSchema{Type: "array", Items: "json", Custom: "com.pyxis.greenhopper.jira:gh-sprint"}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+// memoryCache is an in-memory Cache for tests
+type memoryCache struct {
+	entries map[string][]byte
+}
+
+func (mc *memoryCache) SaveCache(name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	mc.entries[name] = data
+	return nil
+}
+
+func (mc *memoryCache) LoadCache(name string, value interface{}) (bool, error) {
+	data, ok := mc.entries[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, value)
+}
+
+func TestMetadataServiceProject(t *testing.T) {
+	requests := 0
+	server := newMetadataServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"})
+	metadata := NewMetadataService(client, nil)
+
+	meta, err := metadata.Project(context.Background(), "TEST")
+	if err != nil {
+		t.Fatalf("Failed to load metadata: %v", err)
+	}
+
+	if len(meta.IssueTypes) != 4 {
+		t.Errorf("Expected 4 issue types, got %d", len(meta.IssueTypes))
+	}
+	if field, ok := meta.Field("epic link"); !ok || field.ID != "customfield_10014" {
+		t.Errorf("Expected Epic Link field to resolve to customfield_10014")
+	}
+	if meta.IsTeamManaged() || meta.EpicParentField("") != "customfield_10014" {
+		t.Errorf("Expected a company-managed project linking epics through Epic Link, got style %q", meta.Style)
+	}
+
+	// A second lookup is served from memory
+	if _, err := metadata.Project(context.Background(), "TEST"); err != nil {
+		t.Fatalf("Failed to load metadata: %v", err)
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 metadata requests, got %d", requests)
+	}
+}
+
+func TestMetadataServiceUsesCache(t *testing.T) {
+	requests := 0
+	server := newMetadataServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"})
+	cache := &memoryCache{entries: make(map[string][]byte)}
+
+	if _, err := NewMetadataService(client, cache).Project(context.Background(), "TEST"); err != nil {
+		t.Fatalf("Failed to load metadata: %v", err)
+	}
+	if _, ok := cache.entries["metadata-TEST"]; !ok {
+		t.Fatal("Expected metadata to be written to the cache")
+	}
+
+	// A fresh service reads the cached copy without calling Jira
+	requests = 0
+	if _, err := NewMetadataService(client, cache).Project(context.Background(), "TEST"); err != nil {
+		t.Fatalf("Failed to load cached metadata: %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected cached metadata to be used, got %d requests", requests)
+	}
+
+	// Refresh always goes back to Jira
+	if _, err := NewMetadataService(client, cache).Refresh(context.Background(), "TEST"); err != nil {
+		t.Fatalf("Failed to refresh metadata: %v", err)
+	}
+	if requests != 4 {
+		t.Errorf("Expected refresh to refetch metadata, got %d requests", requests)
+	}
+}
+
+func TestMetadataServiceExpiresCache(t *testing.T) {
+	requests := 0
+	server := newMetadataServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST", MetadataTTL: time.Hour})
+	cache := &memoryCache{entries: make(map[string][]byte)}
+	cache.SaveCache("metadata-TEST", &ProjectMetadata{
+		Project:   "TEST",
+		FetchedAt: time.Now().Add(-2 * time.Hour),
+	})
+
+	meta, err := NewMetadataService(client, cache).Project(context.Background(), "TEST")
+	if err != nil {
+		t.Fatalf("Failed to load metadata: %v", err)
+	}
+	if requests != 4 || len(meta.IssueTypes) == 0 {
+		t.Errorf("Expected stale cache to be refetched, got %d requests", requests)
+	}
+}
+
+func TestGetCreateIssueTypesFallsBackToProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/project/TEST" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(JiraProjectDetails{
+			JiraProject: JiraProject{ID: "1", Key: "TEST"},
+			IssueTypes:  []JiraIssueType{{ID: "1", Name: "Task"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(&types.JiraConfig{URL: server.URL})
+	issueTypes, err := client.GetCreateIssueTypes(context.Background(), "TEST")
+	if err != nil {
+		t.Fatalf("Failed to fetch issue types: %v", err)
+	}
+	if len(issueTypes) != 1 || issueTypes[0].Name != "Task" {
+		t.Errorf("Expected issue types from project details, got %+v", issueTypes)
+	}
+}
@@ -0,0 +1,499 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/network"
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// Atlassian OAuth 2.0 (3LO) defaults
+const (
+	DefaultOAuthAuthURL      = "https://auth.atlassian.com/authorize"
+	DefaultOAuthTokenURL     = "https://auth.atlassian.com/oauth/token"
+	DefaultOAuthResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	DefaultOAuthAPIURL       = "https://api.atlassian.com"
+	DefaultOAuthCallbackPort = 8731
+
+	// oauthCredentialsName is the credential store entry holding the token
+	oauthCredentialsName = "oauth-token"
+
+	// tokenExpiryMargin refreshes tokens slightly early so requests in flight do not expire
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// DefaultOAuthScopes grants jit read/write access to issues and offline refresh
+var DefaultOAuthScopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// ErrNotLoggedIn is returned when OAuth is configured but no token is stored
+var ErrNotLoggedIn = errors.New("not logged in to Jira; run 'jit auth login'")
+
+// CredentialStore persists secrets such as OAuth tokens with owner-only permissions
+type CredentialStore interface {
+	SaveCredentials(name string, value interface{}) error
+	LoadCredentials(name string, value interface{}) (bool, error)
+	DeleteCredentials(name string) error
+}
+
+// OAuthToken is a stored OAuth grant along with the Jira site it is bound to
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+	CloudID      string    `json:"cloud_id,omitempty"`
+	SiteURL      string    `json:"site_url,omitempty"`
+}
+
+// Expired reports whether the access token has expired, or is about to
+func (t *OAuthToken) Expired(now time.Time) bool {
+	return !t.Expiry.IsZero() && now.Add(tokenExpiryMargin).After(t.Expiry)
+}
+
+// oauthTokenResponse is the token endpoint's response body
+type oauthTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// accessibleResource is a Jira site an OAuth token may access
+type accessibleResource struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// OAuthClient runs the authorization code + PKCE flow and keeps the
+// resulting token fresh
+type OAuthClient struct {
+	config     types.OAuthConfig
+	siteURL    string
+	store      CredentialStore
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	token  *OAuthToken
+	loaded bool
+	now    func() time.Time
+}
+
+// NewOAuthClient creates an OAuth client for the Jira site at siteURL,
+// filling in Atlassian's endpoints where the config leaves them empty
+func NewOAuthClient(config *types.OAuthConfig, siteURL string, store CredentialStore) *OAuthClient {
+	cfg := *config
+	if cfg.AuthURL == "" {
+		cfg.AuthURL = DefaultOAuthAuthURL
+	}
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = DefaultOAuthTokenURL
+	}
+	if cfg.ResourcesURL == "" {
+		cfg.ResourcesURL = DefaultOAuthResourcesURL
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = DefaultOAuthAPIURL
+	}
+	if cfg.CallbackPort == 0 {
+		cfg.CallbackPort = DefaultOAuthCallbackPort
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = DefaultOAuthScopes
+	}
+
+	return &OAuthClient{
+		config:     cfg,
+		siteURL:    strings.TrimSuffix(siteURL, "/"),
+		store:      store,
+		httpClient: &http.Client{Transport: network.DefaultTransport(), Timeout: network.DefaultRequestTimeout},
+		now:        time.Now,
+	}
+}
+
+// SetTransport sends the OAuth client's requests through transport,
+// typically one from network.NewTransport, each limited to timeout
+func (o *OAuthClient) SetTransport(transport http.RoundTripper, timeout time.Duration) {
+	o.httpClient = &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// Listen opens the loopback listener for the configured callback port
+func (o *OAuthClient) Listen() (net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", o.config.CallbackPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OAuth callback: %v", err)
+	}
+	return listener, nil
+}
+
+// Login sends the user to the authorization page through open, waits for the
+// redirect to arrive on listener, and exchanges the code for a token that is
+// then stored
+func (o *OAuthClient) Login(ctx context.Context, listener net.Listener, open func(authURL string) error) (*OAuthToken, error) {
+	verifier, challenge, err := newPKCE()
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	redirectURI := fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("OAuth callback state mismatch")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = fmt.Errorf("OAuth callback did not include a code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "jit is now logged in to Jira. You can close this window.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	defer server.Close()
+
+	if err := open(o.authorizationURL(state, challenge, redirectURI)); err != nil {
+		return nil, err
+	}
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for authorization: %v", ctx.Err())
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := o.requestToken(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {result.code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.findSite(ctx, token); err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if err := o.saveLocked(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Token returns the stored token, refreshing it when it has expired.
+// It returns nil without an error when the user has not logged in.
+func (o *OAuthClient) Token(ctx context.Context) (*OAuthToken, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	token, err := o.loadLocked()
+	if err != nil || token == nil {
+		return nil, err
+	}
+	if token.Expired(o.now()) {
+		return o.refreshLocked(ctx, token)
+	}
+	return token, nil
+}
+
+// Refresh exchanges the refresh token for a new access token. Passing the
+// token that was rejected lets concurrent callers share a single refresh.
+func (o *OAuthClient) Refresh(ctx context.Context, rejected *OAuthToken) (*OAuthToken, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	token, err := o.loadLocked()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, ErrNotLoggedIn
+	}
+	if rejected != nil && token.AccessToken != rejected.AccessToken {
+		return token, nil
+	}
+	return o.refreshLocked(ctx, token)
+}
+
+// Logout forgets the stored token
+func (o *OAuthClient) Logout() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.token = nil
+	o.loaded = true
+	return o.store.DeleteCredentials(oauthCredentialsName)
+}
+
+// APIBase returns the gateway URL that proxies the token's Jira site
+func (o *OAuthClient) APIBase(token *OAuthToken) string {
+	return strings.TrimSuffix(o.config.APIURL, "/") + "/ex/jira/" + token.CloudID
+}
+
+// authorizationURL builds the consent page URL for a PKCE authorization request
+func (o *OAuthClient) authorizationURL(state, challenge, redirectURI string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", o.config.ClientID)
+	params.Set("scope", strings.Join(o.config.Scopes, " "))
+	params.Set("redirect_uri", redirectURI)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	params.Set("code_challenge", challenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(o.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return o.config.AuthURL + separator + params.Encode()
+}
+
+// refreshLocked trades token's refresh token for a new token and stores it
+func (o *OAuthClient) refreshLocked(ctx context.Context, token *OAuthToken) (*OAuthToken, error) {
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("OAuth token expired and cannot be refreshed; run 'jit auth login'")
+	}
+
+	refreshed, err := o.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh OAuth token: %v", err)
+	}
+
+	// Refresh tokens may or may not rotate; the site binding never changes
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	refreshed.CloudID = token.CloudID
+	refreshed.SiteURL = token.SiteURL
+
+	if err := o.saveLocked(refreshed); err != nil {
+		return nil, err
+	}
+	return refreshed, nil
+}
+
+// requestToken posts a grant to the token endpoint
+func (o *OAuthClient) requestToken(ctx context.Context, form url.Values) (*OAuthToken, error) {
+	form.Set("client_id", o.config.ClientID)
+	if o.config.ClientSecret != "" {
+		form.Set("client_secret", o.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", o.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var response oauthTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode token response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("token request rejected: %s %s", response.Error, response.ErrorDescription)
+	}
+	if resp.StatusCode != 200 || response.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed with HTTP %d", resp.StatusCode)
+	}
+
+	token := &OAuthToken{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+		TokenType:    response.TokenType,
+		Scope:        response.Scope,
+	}
+	if response.ExpiresIn > 0 {
+		token.Expiry = o.now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// findSite binds the token to the configured Jira site, or to the only
+// site the token can access when none is configured
+func (o *OAuthClient) findSite(ctx context.Context, token *OAuthToken) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", o.config.ResourcesURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to list accessible sites: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to list accessible sites: HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var resources []accessibleResource
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return fmt.Errorf("failed to decode accessible sites: %v", err)
+	}
+
+	var sites []string
+	for _, resource := range resources {
+		if strings.EqualFold(strings.TrimSuffix(resource.URL, "/"), o.siteURL) || (o.siteURL == "" && len(resources) == 1) {
+			token.CloudID = resource.ID
+			token.SiteURL = resource.URL
+			return nil
+		}
+		sites = append(sites, resource.URL)
+	}
+
+	switch {
+	case len(sites) == 0:
+		return fmt.Errorf("token cannot access any Jira site")
+	case o.siteURL == "":
+		return fmt.Errorf("token can access several Jira sites; set jira.url to one of: %s", strings.Join(sites, ", "))
+	}
+	return fmt.Errorf("token cannot access %s; authorized sites: %s", o.siteURL, strings.Join(sites, ", "))
+}
+
+// loadLocked returns the cached token, reading it from the store once
+func (o *OAuthClient) loadLocked() (*OAuthToken, error) {
+	if o.loaded {
+		return o.token, nil
+	}
+
+	var token OAuthToken
+	found, err := o.store.LoadCredentials(oauthCredentialsName, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OAuth token: %v", err)
+	}
+	if found {
+		o.token = &token
+	}
+	o.loaded = true
+	return o.token, nil
+}
+
+// saveLocked stores token and makes it the current token
+func (o *OAuthClient) saveLocked(token *OAuthToken) error {
+	if err := o.store.SaveCredentials(oauthCredentialsName, token); err != nil {
+		return fmt.Errorf("failed to save OAuth token: %v", err)
+	}
+	o.token = token
+	o.loaded = true
+	return nil
+}
+
+// newPKCE returns a PKCE code verifier and its S256 challenge
+func newPKCE() (string, string, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// randomString returns n random bytes encoded as unpadded base64url
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// doOAuthRequest sends a request through the OAuth gateway, refreshing the
+// token and retrying once when Jira rejects it
+func (c *Client) doOAuthRequest(ctx context.Context, token *OAuthToken, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	// Buffer the body so it can be sent again after a refresh
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = io.ReadAll(body); err != nil {
+			return nil, fmt.Errorf("failed to read request body: %v", err)
+		}
+	}
+
+	send := func(token *OAuthToken) (*http.Response, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(payload)
+		}
+		return c.send(ctx, method, c.oauth.APIBase(token)+path, reader, "Bearer "+token.AccessToken, header)
+	}
+
+	resp, err := send(token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	token, err = c.oauth.Refresh(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return send(token)
+}
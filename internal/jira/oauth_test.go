@@ -0,0 +1,294 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// memoryCredentials is an in-memory CredentialStore for tests
+type memoryCredentials map[string][]byte
+
+func (m memoryCredentials) SaveCredentials(name string, value interface{}) error {
+	data, err := json.Marshal(value)
+	m[name] = data
+	return err
+}
+
+func (m memoryCredentials) LoadCredentials(name string, value interface{}) (bool, error) {
+	data, ok := m[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, value)
+}
+
+func (m memoryCredentials) DeleteCredentials(name string) error {
+	delete(m, name)
+	return nil
+}
+
+// fakeAuthServer is a minimal OAuth authorization server and API gateway
+type fakeAuthServer struct {
+	*httptest.Server
+	t         *testing.T
+	challenge string
+	issued    int
+	refreshes int
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	fake := &fakeAuthServer{t: t}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("response_type") != "code" {
+			t.Errorf("Unexpected authorization request: %s", r.URL.RawQuery)
+		}
+		fake.challenge = query.Get("code_challenge")
+
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {"auth-code"}, "state": {query.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "client-123" {
+			t.Errorf("Expected client ID, got %q", r.Form.Get("client_id"))
+		}
+
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if r.Form.Get("code") != "auth-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != fake.challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		case "refresh_token":
+			if r.Form.Get("refresh_token") != "refresh-token" {
+				t.Errorf("Unexpected refresh token %q", r.Form.Get("refresh_token"))
+			}
+			fake.refreshes++
+		default:
+			t.Errorf("Unexpected grant type %q", r.Form.Get("grant_type"))
+		}
+
+		fake.issued++
+		response := map[string]interface{}{
+			"access_token": fmt.Sprintf("access-%d", fake.issued),
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"scope":        "read:jira-work offline_access",
+		}
+		// The refresh token is only returned on the initial grant
+		if r.Form.Get("grant_type") == "authorization_code" {
+			response["refresh_token"] = "refresh-token"
+		}
+		json.NewEncoder(w).Encode(response)
+	})
+
+	mux.HandleFunc("/resources", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]accessibleResource{
+			{ID: "other", URL: "https://other.atlassian.net"},
+			{ID: "cloud-1", URL: "https://example.atlassian.net"},
+		})
+	})
+
+	mux.HandleFunc("/ex/jira/cloud-1/rest/api/3/myself", func(w http.ResponseWriter, r *http.Request) {
+		// Only the most recently issued token is accepted
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer access-%d", fake.issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(JiraUser{AccountID: "abc", DisplayName: "Jane Doe"})
+	})
+
+	fake.Server = httptest.NewServer(mux)
+	return fake
+}
+
+func (f *fakeAuthServer) oauthConfig() *types.OAuthConfig {
+	return &types.OAuthConfig{
+		ClientID:     "client-123",
+		AuthURL:      f.URL + "/authorize",
+		TokenURL:     f.URL + "/token",
+		ResourcesURL: f.URL + "/resources",
+		APIURL:       f.URL,
+	}
+}
+
+// browse follows the authorization URL like a browser would
+func browse(authURL string) error {
+	resp, err := http.Get(authURL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("callback returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func loginForTest(t *testing.T, oauth *OAuthClient) *OAuthToken {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := oauth.Login(ctx, listener, browse)
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return token
+}
+
+func TestOAuthLogin(t *testing.T) {
+	fake := newFakeAuthServer(t)
+	defer fake.Close()
+
+	store := memoryCredentials{}
+	oauth := NewOAuthClient(fake.oauthConfig(), "https://example.atlassian.net/", store)
+
+	token := loginForTest(t, oauth)
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-token" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+	if token.CloudID != "cloud-1" {
+		t.Errorf("Expected token bound to cloud-1, got %q", token.CloudID)
+	}
+	if _, ok := store[oauthCredentialsName]; !ok {
+		t.Error("Expected token to be stored")
+	}
+
+	// A new client picks the stored token up
+	stored, err := NewOAuthClient(fake.oauthConfig(), "https://example.atlassian.net", store).Token(context.Background())
+	if err != nil || stored == nil || stored.AccessToken != "access-1" {
+		t.Errorf("Expected stored token, got %+v (%v)", stored, err)
+	}
+
+	if err := oauth.Logout(); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if token, _ := oauth.Token(context.Background()); token != nil {
+		t.Errorf("Expected no token after logout, got %+v", token)
+	}
+}
+
+func TestOAuthLoginWithSeveralSites(t *testing.T) {
+	fake := newFakeAuthServer(t)
+	defer fake.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	// Without jira.url there is no telling which site was meant
+	oauth := NewOAuthClient(fake.oauthConfig(), "", memoryCredentials{})
+	_, err = oauth.Login(context.Background(), listener, browse)
+	if err == nil {
+		t.Fatal("Expected an error choosing between sites")
+	}
+	for _, want := range []string{"jira.url", "https://other.atlassian.net", "https://example.atlassian.net"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %q", want, err)
+		}
+	}
+}
+
+func TestOAuthLoginRejectsStateMismatch(t *testing.T) {
+	fake := newFakeAuthServer(t)
+	defer fake.Close()
+
+	oauth := NewOAuthClient(fake.oauthConfig(), "https://example.atlassian.net", memoryCredentials{})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	_, err = oauth.Login(context.Background(), listener, func(authURL string) error {
+		callback := fmt.Sprintf("http://%s/callback?code=auth-code&state=forged", listener.Addr())
+		go http.Get(callback)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected forged state to be rejected")
+	}
+}
+
+func TestClientRefreshesOAuthTokenOn401(t *testing.T) {
+	fake := newFakeAuthServer(t)
+	defer fake.Close()
+
+	oauth := NewOAuthClient(fake.oauthConfig(), "https://example.atlassian.net", memoryCredentials{})
+	loginForTest(t, oauth)
+
+	client := NewClient(&types.JiraConfig{URL: "https://example.atlassian.net"})
+	client.SetOAuthClient(oauth)
+
+	// The server revokes the current token by issuing a new one out of band
+	fake.issued++
+
+	user, err := client.GetCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("Expected request to succeed after refresh: %v", err)
+	}
+	if user.DisplayName != "Jane Doe" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+	if fake.refreshes != 1 {
+		t.Errorf("Expected one refresh, got %d", fake.refreshes)
+	}
+
+	// The refresh token survives a response that does not rotate it
+	token, _ := oauth.Token(context.Background())
+	if token.RefreshToken != "refresh-token" || token.CloudID != "cloud-1" {
+		t.Errorf("Expected refresh token and site to be kept, got %+v", token)
+	}
+}
+
+func TestOAuthRefreshesExpiredToken(t *testing.T) {
+	fake := newFakeAuthServer(t)
+	defer fake.Close()
+
+	oauth := NewOAuthClient(fake.oauthConfig(), "https://example.atlassian.net", memoryCredentials{})
+	loginForTest(t, oauth)
+
+	oauth.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	token, err := oauth.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	if token.AccessToken != "access-2" || fake.refreshes != 1 {
+		t.Errorf("Expected expired token to be refreshed, got %+v after %d refreshes", token, fake.refreshes)
+	}
+}
+
+func TestClientRequiresLoginWithoutAPIToken(t *testing.T) {
+	client := NewClient(&types.JiraConfig{URL: "https://example.atlassian.net"})
+	client.SetOAuthClient(NewOAuthClient(&types.OAuthConfig{ClientID: "client-123"}, "https://example.atlassian.net", memoryCredentials{}))
+
+	if _, err := client.GetCurrentUser(context.Background()); err != ErrNotLoggedIn {
+		t.Errorf("Expected ErrNotLoggedIn, got %v", err)
+	}
+}
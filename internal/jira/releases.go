@@ -0,0 +1,153 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// Version and component ticket fields, named as Jira names them
+const (
+	FieldFixVersions = "fixVersions"
+	FieldComponents  = "components"
+)
+
+// GetProjectVersions fetches every version of a project
+func (c *Client) GetProjectVersions(ctx context.Context, project string) ([]JiraVersion, error) {
+	var versions []JiraVersion
+	if err := c.getProjectList(ctx, project, "versions", &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetProjectComponents fetches every component of a project
+func (c *Client) GetProjectComponents(ctx context.Context, project string) ([]JiraComponent, error) {
+	var components []JiraComponent
+	if err := c.getProjectList(ctx, project, "components", &components); err != nil {
+		return nil, err
+	}
+	return components, nil
+}
+
+// getProjectList decodes one of the unpaginated lists under /project/{key}
+func (c *Client) getProjectList(ctx context.Context, project, list string, out interface{}) error {
+	endpoint := fmt.Sprintf("/project/%s/%s", project, list)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return c.parseErrorResponse(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// Versions lists a project's versions, or the configured project's when
+// project is empty, in the order Jira ranks them
+func (ts *TicketService) Versions(ctx context.Context, project string) ([]types.Version, error) {
+	if project == "" {
+		project = ts.client.config.Project
+	}
+
+	jiraVersions, err := ts.client.GetProjectVersions(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch versions of %s: %v", project, err)
+	}
+
+	versions := make([]types.Version, 0, len(jiraVersions))
+	for _, v := range jiraVersions {
+		versions = append(versions, types.Version{
+			ID:          v.ID,
+			Name:        v.Name,
+			Description: v.Description,
+			Released:    v.Released,
+			Archived:    v.Archived,
+			StartDate:   v.StartDate,
+			ReleaseDate: v.ReleaseDate,
+		})
+	}
+	return versions, nil
+}
+
+// FindVersion finds a project's version by name, ignoring case, or by ID
+func (ts *TicketService) FindVersion(ctx context.Context, project, query string) (*types.Version, error) {
+	versions, err := ts.Versions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	for i := range versions {
+		if strings.EqualFold(versions[i].Name, query) || versions[i].ID == query {
+			return &versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no version %q; see 'jit release list'", query)
+}
+
+// Components lists a project's components, or the configured project's
+// when project is empty
+func (ts *TicketService) Components(ctx context.Context, project string) ([]types.Component, error) {
+	if project == "" {
+		project = ts.client.config.Project
+	}
+
+	jiraComponents, err := ts.client.GetProjectComponents(ctx, project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch components of %s: %v", project, err)
+	}
+
+	components := make([]types.Component, 0, len(jiraComponents))
+	for _, c := range jiraComponents {
+		components = append(components, types.Component{ID: c.ID, Name: c.Name, Description: c.Description})
+	}
+	return components, nil
+}
+
+// VersionTickets streams the tickets fixed in a version
+func (ts *TicketService) VersionTickets(project, version string) *TicketIterator {
+	if project == "" {
+		project = ts.client.config.Project
+	}
+	jql := fmt.Sprintf("project = %s AND fixVersion = %s ORDER BY key ASC", project, quoteJQL(version))
+	return ts.Search(jql, nil)
+}
+
+// nameRefs refers to versions or components by name, skipping blank names
+func nameRefs(names []string) []JiraNameRef {
+	refs := []JiraNameRef{}
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			refs = append(refs, JiraNameRef{Name: name})
+		}
+	}
+	return refs
+}
+
+// versionNames lists the names of versions
+func versionNames(versions []JiraVersion) []string {
+	var names []string
+	for _, v := range versions {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// componentNames lists the names of components
+func componentNames(components []JiraComponent) []string {
+	var names []string
+	for _, c := range components {
+		names = append(names, c.Name)
+	}
+	return names
+}
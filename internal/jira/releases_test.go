@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newReleaseServer serves the versions, components and fixed tickets of TEST
+func newReleaseServer(t *testing.T, jql *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/project/TEST/versions":
+			fmt.Fprint(w, `[
+				{"id": "100", "name": "1.3.0", "released": true, "archived": true, "releaseDate": "2024-01-15"},
+				{"id": "101", "name": "1.4.0", "description": "MFA", "released": false, "startDate": "2024-02-01", "releaseDate": "2024-03-01"}
+			]`)
+		case "/rest/api/3/project/TEST/components":
+			fmt.Fprint(w, `[{"id": "10", "name": "Backend", "description": "APIs"}, {"id": "11", "name": "Web"}]`)
+		case "/rest/api/3/search":
+			*jql = r.URL.Query().Get("jql")
+			fmt.Fprint(w, `{"startAt": 0, "total": 1, "issues": [
+				{"key": "TEST-1", "fields": {"summary": "Login", "fixVersions": [{"id": "101", "name": "1.4.0"}], "components": [{"id": "10", "name": "Backend"}]}}
+			]}`)
+		default:
+			if isMetadataRequest(r) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestVersionsAndComponents(t *testing.T) {
+	var jql string
+	server := newReleaseServer(t, &jql)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	versions, err := service.Versions(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := types.Version{ID: "101", Name: "1.4.0", Description: "MFA", StartDate: "2024-02-01", ReleaseDate: "2024-03-01"}
+	if len(versions) != 2 || !versions[0].Archived || versions[1] != expected {
+		t.Errorf("Unexpected versions: %+v", versions)
+	}
+
+	components, err := service.Components(context.Background(), "TEST")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(components) != 2 || components[0] != (types.Component{ID: "10", Name: "Backend", Description: "APIs"}) {
+		t.Errorf("Unexpected components: %+v", components)
+	}
+}
+
+func TestFindVersion(t *testing.T) {
+	var jql string
+	server := newReleaseServer(t, &jql)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	for _, query := range []string{"1.4.0", " 1.4.0 ", "101"} {
+		version, err := service.FindVersion(context.Background(), "", query)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", query, err)
+		}
+		if version.ID != "101" {
+			t.Errorf("%q: expected version 101, got %+v", query, version)
+		}
+	}
+
+	if _, err := service.FindVersion(context.Background(), "", "2.0"); err == nil {
+		t.Error("Expected an error for an unknown version")
+	}
+}
+
+func TestVersionTickets(t *testing.T) {
+	var jql string
+	server := newReleaseServer(t, &jql)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	tickets, err := service.VersionTickets("", "1.4.0").Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := `project = TEST AND fixVersion = "1.4.0" ORDER BY key ASC`; jql != expected {
+		t.Errorf("Expected JQL %q, got %q", expected, jql)
+	}
+
+	if len(tickets) != 1 {
+		t.Fatalf("Expected 1 ticket, got %d", len(tickets))
+	}
+	ticket := tickets[0]
+	if !reflect.DeepEqual(ticket.Metadata.FixVersions, []string{"1.4.0"}) || !reflect.DeepEqual(ticket.Metadata.Components, []string{"Backend"}) {
+		t.Errorf("Unexpected versions and components: %v %v", ticket.Metadata.FixVersions, ticket.Metadata.Components)
+	}
+	if !ticket.HasFixVersion("1.4.0") {
+		t.Error("Expected ticket to be fixed in 1.4.0")
+	}
+}
+
+func TestCreateTicketSendsVersionsAndComponents(t *testing.T) {
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	var sent map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue":
+			var request map[string]map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			sent = request["fields"]
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": "2", "key": "TEST-2"}`)
+		case r.URL.Path == "/rest/api/3/issue/TEST-2":
+			fmt.Fprint(w, `{"key": "TEST-2", "fields": {"summary": "Login"}}`)
+		default:
+			http.Redirect(w, r, metadataServer.URL+r.URL.String(), http.StatusTemporaryRedirect)
+		}
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	ticket := types.NewTicket("", "Login", types.TicketTypeTask)
+	ticket.Metadata.FixVersions = []string{"1.4.0", " "}
+	ticket.Metadata.Components = []string{"Backend"}
+	if _, err := service.CreateTicket(context.Background(), ticket); err != nil {
+		t.Fatalf("CreateTicket failed: %v", err)
+	}
+
+	if string(sent["fixVersions"]) != `[{"name":"1.4.0"}]` {
+		t.Errorf("Expected fixVersions by name, got %s", sent["fixVersions"])
+	}
+	if string(sent["components"]) != `[{"name":"Backend"}]` {
+		t.Errorf("Expected components by name, got %s", sent["components"])
+	}
+}
+
+func TestUpdateTicketSendsVersions(t *testing.T) {
+	var sent map[string]map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	before := editableTicket()
+	before.Metadata.FixVersions = []string{"1.4.0"}
+	after := *before
+	after.Metadata.FixVersions = nil
+
+	changed, err := service.UpdateTicket(context.Background(), before, &after)
+	if err != nil {
+		t.Fatalf("UpdateTicket failed: %v", err)
+	}
+	if expected := []string{FieldFixVersions}; !reflect.DeepEqual(changed, expected) {
+		t.Errorf("Expected %v, got %v", expected, changed)
+	}
+
+	// Clearing the versions sends an empty list, not null
+	if got := string(sent["fields"]["fixVersions"]); got != "[]" {
+		t.Errorf("Expected fixVersions cleared with [], got %s", got)
+	}
+}
@@ -0,0 +1,115 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// GetRemoteLinks fetches the remote links of an issue
+func (c *Client) GetRemoteLinks(ctx context.Context, issueKey string) ([]JiraRemoteLink, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/issue/%s/remotelink", issueKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var links []JiraRemoteLink
+	if err := json.NewDecoder(resp.Body).Decode(&links); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return links, nil
+}
+
+// CreateRemoteLink adds a remote link to an issue. A link with the same
+// global ID as an existing one replaces it, and Jira answers 200 instead of
+// 201.
+func (c *Client) CreateRemoteLink(ctx context.Context, issueKey string, link *JiraRemoteLink) (*JiraCreateRemoteLinkResponse, error) {
+	body, err := json.Marshal(link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s/remotelink", issueKey)
+	resp, err := c.doRequest(ctx, "POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var created JiraCreateRemoteLinkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &created, nil
+}
+
+// RemoteLinks lists the remote links of a ticket
+func (ts *TicketService) RemoteLinks(ctx context.Context, ticketKey string) ([]*types.RemoteLink, error) {
+	jiraLinks, err := ts.client.GetRemoteLinks(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch remote links of %s: %v", ticketKey, err)
+	}
+
+	links := make([]*types.RemoteLink, 0, len(jiraLinks))
+	for i := range jiraLinks {
+		links = append(links, convertRemoteLink(ticketKey, &jiraLinks[i]))
+	}
+	return links, nil
+}
+
+// AddRemoteLink links a ticket to a URL, titled with the URL when no title
+// is given. The URL is the link's global ID, so adding it again updates the
+// existing link rather than duplicating it.
+func (ts *TicketService) AddRemoteLink(ctx context.Context, link *types.RemoteLink) (*types.RemoteLink, error) {
+	if link.URL == "" {
+		return nil, fmt.Errorf("remote link needs a URL")
+	}
+
+	request := &JiraRemoteLink{
+		GlobalID:     link.URL,
+		Relationship: link.Relationship,
+		Object: JiraRemoteLinkObject{
+			URL:     link.URL,
+			Title:   link.Title,
+			Summary: link.Summary,
+		},
+	}
+	if request.Object.Title == "" {
+		request.Object.Title = link.URL
+	}
+
+	created, err := ts.client.CreateRemoteLink(ctx, link.TicketKey, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to link %s to %s: %v", link.TicketKey, link.URL, err)
+	}
+
+	request.ID = created.ID
+	return convertRemoteLink(link.TicketKey, request), nil
+}
+
+// convertRemoteLink converts a Jira remote link to a RemoteLink
+func convertRemoteLink(ticketKey string, link *JiraRemoteLink) *types.RemoteLink {
+	return &types.RemoteLink{
+		ID:           strconv.Itoa(link.ID),
+		TicketKey:    ticketKey,
+		URL:          link.Object.URL,
+		Title:        link.Object.Title,
+		Summary:      link.Object.Summary,
+		Relationship: link.Relationship,
+	}
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestRemoteLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/rest/api/3/issue/TEST-1/remotelink" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `[
+			{"id": 10000, "globalId": "https://github.com/acme/api/pull/42", "relationship": "pull request",
+			 "object": {"url": "https://github.com/acme/api/pull/42", "title": "PR #42: Add TOTP", "icon": {"url16x16": "https://github.com/favicon.ico"}}},
+			{"id": 10001, "object": {"url": "https://docs.acme.dev/mfa", "title": "MFA design", "summary": "Design doc"}}
+		]`)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	links, err := service.RemoteLinks(context.Background(), "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("Expected 2 links, got %d", len(links))
+	}
+
+	expected := types.RemoteLink{ID: "10000", TicketKey: "TEST-1", URL: "https://github.com/acme/api/pull/42", Title: "PR #42: Add TOTP", Relationship: "pull request"}
+	if *links[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *links[0])
+	}
+	if links[1].Summary != "Design doc" {
+		t.Errorf("Expected summary, got %+v", links[1])
+	}
+}
+
+func TestAddRemoteLink(t *testing.T) {
+	var sent JiraRemoteLink
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue/TEST-1/remotelink" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		// An existing global ID is updated in place
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id": 10000, "self": "https://example.atlassian.net/rest/api/3/issue/TEST-1/remotelink/10000"}`)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	link, err := service.AddRemoteLink(context.Background(), &types.RemoteLink{TicketKey: "TEST-1", URL: "https://docs.acme.dev/mfa"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if sent.GlobalID != "https://docs.acme.dev/mfa" || sent.Object.URL != sent.GlobalID {
+		t.Errorf("Expected the URL as global ID, got %+v", sent)
+	}
+	if sent.Object.Title != "https://docs.acme.dev/mfa" {
+		t.Errorf("Expected the URL as default title, got %q", sent.Object.Title)
+	}
+	if link.ID != "10000" || link.TicketKey != "TEST-1" || link.Title != sent.Object.Title {
+		t.Errorf("Unexpected link: %+v", link)
+	}
+
+	if _, err := service.AddRemoteLink(context.Background(), &types.RemoteLink{TicketKey: "TEST-1"}); err == nil {
+		t.Error("Expected an error for a link without a URL")
+	}
+}
+
+func TestAddRemoteLinkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"errorMessages": ["You do not have permission to link issues"]}`)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+
+	if _, err := service.AddRemoteLink(context.Background(), &types.RemoteLink{TicketKey: "TEST-1", URL: "https://example.com"}); err == nil {
+		t.Error("Expected an error when Jira refuses the link")
+	}
+}
@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// RichText is a description or comment body. Jira Cloud exchanges ADF
+// documents while Server and Data Center exchange wiki markup strings;
+// exactly one of ADF or Wiki is set.
+type RichText struct {
+	ADF  *ADFNode
+	Wiki string
+}
+
+// NewADFText converts markdown into an ADF body, or nil for blank input
+func NewADFText(markdown string) *RichText {
+	doc := MarkdownToADF(markdown)
+	if doc == nil {
+		return nil
+	}
+	return &RichText{ADF: doc}
+}
+
+// NewWikiText converts markdown into a wiki markup body, or nil for blank input
+func NewWikiText(markdown string) *RichText {
+	wiki := ADFToWiki(MarkdownToADF(markdown))
+	if wiki == "" {
+		return nil
+	}
+	return &RichText{Wiki: wiki}
+}
+
+// Markdown renders the body as markdown
+func (rt *RichText) Markdown() string {
+	if rt == nil {
+		return ""
+	}
+	if rt.ADF != nil {
+		return ADFToMarkdown(rt.ADF)
+	}
+	return ADFToMarkdown(WikiToADF(rt.Wiki))
+}
+
+// MarshalJSON encodes the body as an ADF object or a wiki string
+func (rt *RichText) MarshalJSON() ([]byte, error) {
+	if rt.ADF != nil {
+		return json.Marshal(rt.ADF)
+	}
+	return json.Marshal(rt.Wiki)
+}
+
+// UnmarshalJSON accepts either an ADF object or a wiki string
+func (rt *RichText) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &rt.Wiki)
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var doc ADFNode
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	rt.ADF = &doc
+	return nil
+}
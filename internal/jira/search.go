@@ -0,0 +1,191 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// DefaultPageSize is the number of issues requested per search page
+const DefaultPageSize = 100
+
+// DefaultSearchFields are the issue fields requested by searches unless overridden
+var DefaultSearchFields = []string{
+	"summary", "description", "status", "priority", "issuetype", "project",
+	"assignee", "reporter", "created", "updated", "labels", "issuelinks",
+	"parent", "subtasks", "fixVersions", "components",
+}
+
+// SearchOptions controls a paginated JQL search
+type SearchOptions struct {
+	JQL           string
+	StartAt       int
+	MaxResults    int      // Page size; defaults to the service page size
+	Fields        []string // Fields to return; defaults to DefaultSearchFields
+	NextPageToken string
+	Limit         int // Maximum number of issues to return in total; 0 means no limit
+}
+
+// TicketIterator streams tickets from a paginated JQL search, fetching
+// pages lazily as the caller advances.
+//
+//	it := ts.Search(jql, nil)
+//	for it.Next(ctx) {
+//		ticket := it.Ticket()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type TicketIterator struct {
+	ts       *TicketService
+	opts     SearchOptions
+	page     []JiraIssue
+	index    int
+	current  *types.Ticket
+	returned int
+	fetched  int // Issues received, for the total when Jira reports none
+	total    int
+	lastPage bool
+	err      error
+}
+
+// Search returns an iterator over all tickets matching jql
+func (ts *TicketService) Search(jql string, opts *SearchOptions) *TicketIterator {
+	options := SearchOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	options.JQL = jql
+	if options.MaxResults <= 0 {
+		options.MaxResults = ts.pageSize()
+	}
+	return &TicketIterator{
+		ts:    ts,
+		opts:  options,
+		total: -1,
+	}
+}
+
+// Next advances to the next ticket, fetching the next page when needed.
+// It returns false when the results are exhausted or an error occurred.
+func (it *TicketIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if it.opts.Limit > 0 && it.returned >= it.opts.Limit {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.lastPage {
+			return false
+		}
+		if err := it.fetchPage(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.current = it.ts.convertJiraIssueToTicket(ctx, &it.page[it.index])
+	it.index++
+	it.returned++
+	return true
+}
+
+// fetchPage loads the next page of results
+func (it *TicketIterator) fetchPage(ctx context.Context) error {
+	// Resolved here rather than in Search, as it may read the metadata
+	if len(it.opts.Fields) == 0 {
+		it.opts.Fields = it.ts.searchFields(ctx)
+	}
+
+	response, err := it.ts.client.SearchIssuesPage(ctx, &it.opts)
+	if err != nil {
+		return fmt.Errorf("failed to search issues: %v", err)
+	}
+
+	tokenPaging := it.opts.NextPageToken != "" || response.NextPageToken != ""
+	it.page = response.Issues
+	it.index = 0
+	it.fetched += len(response.Issues)
+	if response.Total > 0 {
+		it.total = response.Total
+	}
+
+	// A last or empty page ends the results even when it carries a token
+	switch {
+	case response.IsLast || len(response.Issues) == 0:
+		it.lastPage = true
+	case response.NextPageToken != "":
+		// The offset still moves on, for servers that page by it instead
+		it.opts.NextPageToken = response.NextPageToken
+		it.opts.StartAt += len(response.Issues)
+	case tokenPaging:
+		it.lastPage = true
+	default:
+		it.opts.StartAt = response.StartAt + len(response.Issues)
+		if it.opts.StartAt >= response.Total {
+			it.lastPage = true
+		}
+	}
+
+	// Token paging reports no total; at the end it is what was fetched
+	if it.lastPage && it.total < it.fetched {
+		it.total = it.fetched
+	}
+
+	return nil
+}
+
+// Ticket returns the current ticket
+func (it *TicketIterator) Ticket() *types.Ticket {
+	return it.current
+}
+
+// Err returns the first error encountered while iterating
+func (it *TicketIterator) Err() error {
+	return it.err
+}
+
+// Total returns the total number of matches reported by Jira, or -1 if unknown
+func (it *TicketIterator) Total() int {
+	return it.total
+}
+
+// Collect drains the iterator into a slice
+func (it *TicketIterator) Collect(ctx context.Context) ([]*types.Ticket, error) {
+	var tickets []*types.Ticket
+	for it.Next(ctx) {
+		tickets = append(tickets, it.Ticket())
+	}
+	return tickets, it.Err()
+}
+
+// pageSize returns the configured search page size
+func (ts *TicketService) pageSize() int {
+	if ts.client.config != nil && ts.client.config.PageSize > 0 {
+		return ts.client.config.PageSize
+	}
+	return DefaultPageSize
+}
+
+// searchFields returns the fields searches request by default: the
+// DefaultSearchFields plus the epic link, Sprint and configured custom fields
+func (ts *TicketService) searchFields(ctx context.Context) []string {
+	fields := append([]string{}, DefaultSearchFields...)
+	if field := ts.epicLinkField(ctx); field != "" {
+		fields = append(fields, field)
+	}
+	if meta, err := ts.projectMetadata(ctx); err == nil {
+		if field := meta.SprintField(); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	ids := ts.customFieldIDs(ctx)
+	for _, name := range ts.CustomFieldNames() {
+		if id, ok := ids[name]; ok {
+			fields = append(fields, id)
+		}
+	}
+	return fields
+}
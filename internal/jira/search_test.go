@@ -0,0 +1,304 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newPagedSearchServer serves total issues in pages using startAt/maxResults
+func newPagedSearchServer(t *testing.T, total int, requests *int) *httptest.Server {
+	return newPagedSearchServerWithJQL(t, total, requests, nil)
+}
+
+// newPagedSearchServerWithJQL is newPagedSearchServer recording the JQL of
+// the last search when jql is non-nil
+func newPagedSearchServerWithJQL(t *testing.T, total int, requests *int, jql *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isMetadataRequest(r) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*requests++
+		if jql != nil {
+			*jql = r.URL.Query().Get("jql")
+		}
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		if maxResults == 0 {
+			t.Errorf("Expected maxResults to be set")
+			maxResults = 50
+		}
+
+		response := JiraSearchResponse{StartAt: startAt, MaxResults: maxResults, Total: total}
+		for i := startAt; i < startAt+maxResults && i < total; i++ {
+			response.Issues = append(response.Issues, JiraIssue{
+				Key: fmt.Sprintf("TEST-%d", i+1),
+				Fields: JiraIssueFields{
+					Summary:   fmt.Sprintf("Issue %d", i+1),
+					IssueType: JiraIssueType{Name: "Task"},
+				},
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+// isMetadataRequest reports whether r is one of the requests that load
+// project metadata, which converting issues may make to resolve the epic
+// link field; servers that only serve searches answer them with 404
+func isMetadataRequest(r *http.Request) bool {
+	path := r.URL.Path
+	return strings.Contains(path, "/issue/createmeta/") || strings.HasSuffix(path, "/priority") ||
+		strings.HasSuffix(path, "/field") || regexp.MustCompile(`/project/[^/]+$`).MatchString(path)
+}
+
+func newTestService(url string) *TicketService {
+	return NewTicketService(NewClient(&types.JiraConfig{
+		URL:      url,
+		Username: "test@example.com",
+		Token:    "test-token",
+		Project:  "TEST",
+	}))
+}
+
+func TestSearchIteratorFollowsStartAt(t *testing.T) {
+	requests := 0
+	server := newPagedSearchServer(t, 5, &requests)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+	it := service.Search("project = TEST", &SearchOptions{MaxResults: 2})
+
+	tickets, err := it.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to iterate search: %v", err)
+	}
+
+	if len(tickets) != 5 {
+		t.Fatalf("Expected 5 tickets, got %d", len(tickets))
+	}
+	for i, ticket := range tickets {
+		if expected := fmt.Sprintf("TEST-%d", i+1); ticket.Key != expected {
+			t.Errorf("Expected ticket %s at position %d, got %s", expected, i, ticket.Key)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests)
+	}
+	if it.Total() != 5 {
+		t.Errorf("Expected total 5, got %d", it.Total())
+	}
+}
+
+func TestSearchIteratorFollowsNextPageToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := JiraSearchResponse{}
+		switch r.URL.Query().Get("nextPageToken") {
+		case "":
+			response.Issues = []JiraIssue{{Key: "TEST-1"}, {Key: "TEST-2"}}
+			response.NextPageToken = "page-2"
+		case "page-2":
+			response.Issues = []JiraIssue{{Key: "TEST-3"}}
+			response.IsLast = true
+		default:
+			t.Errorf("Unexpected page token %s", r.URL.Query().Get("nextPageToken"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+	it := service.Search("project = TEST", nil)
+	tickets, err := it.Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to iterate search: %v", err)
+	}
+
+	if len(tickets) != 3 || tickets[2].Key != "TEST-3" {
+		t.Errorf("Expected 3 tickets ending with TEST-3, got %d", len(tickets))
+	}
+	if it.Total() != 3 {
+		t.Errorf("Expected total 3 without a reported total, got %d", it.Total())
+	}
+}
+
+func TestSearchNextPageEndpoint(t *testing.T) {
+	tests := []struct {
+		flavor string
+		path   string
+		query  string
+	}{
+		{FlavorCloud, "/rest/api/3/search/jql", "nextPageToken=page-2"},
+		{FlavorServer, "/rest/api/2/search", "startAt=2"},
+	}
+
+	for _, tt := range tests {
+		var pages []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isMetadataRequest(r) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			pages = append(pages, r.URL.Path)
+
+			response := JiraSearchResponse{Total: 3}
+			if r.URL.Query().Get("nextPageToken") == "" && r.URL.Query().Get("startAt") == "" {
+				response.Issues = []JiraIssue{{Key: "TEST-1"}, {Key: "TEST-2"}}
+				response.NextPageToken = "page-2"
+			} else {
+				if !strings.Contains(r.URL.RawQuery, tt.query) {
+					t.Errorf("%s: expected %s on the second page, got %s", tt.flavor, tt.query, r.URL.RawQuery)
+				}
+				response.StartAt = 2
+				response.Issues = []JiraIssue{{Key: "TEST-3"}}
+				response.IsLast = true
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+		}))
+
+		service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Flavor: tt.flavor, Token: "test-token", Project: "TEST"}))
+		tickets, err := service.Search("project = TEST", nil).Collect(context.Background())
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: failed to iterate search: %v", tt.flavor, err)
+		}
+		if len(tickets) != 3 {
+			t.Errorf("%s: expected 3 tickets, got %d", tt.flavor, len(tickets))
+		}
+		if len(pages) != 2 || pages[1] != tt.path {
+			t.Errorf("%s: expected the second page from %s, got %v", tt.flavor, tt.path, pages)
+		}
+	}
+}
+
+func TestSearchIteratorStopsOnLastTokenPage(t *testing.T) {
+	pages := map[string]JiraSearchResponse{
+		// Some servers keep handing out a token on the final pages
+		"empty page": {NextPageToken: "again"},
+		"last page":  {Issues: []JiraIssue{{Key: "TEST-1"}}, NextPageToken: "again", IsLast: true},
+	}
+
+	for name, page := range pages {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isMetadataRequest(r) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(page)
+		}))
+
+		it := newTestService(server.URL).Search("project = TEST", nil)
+		tickets, err := it.Collect(context.Background())
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: failed to iterate search: %v", name, err)
+		}
+		if requests != 1 {
+			t.Errorf("%s: expected 1 request, got %d", name, requests)
+		}
+		if it.Total() != len(tickets) {
+			t.Errorf("%s: expected total %d, got %d", name, len(tickets), it.Total())
+		}
+	}
+}
+
+func TestSearchIteratorLimitAndFields(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isMetadataRequest(r) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		if fields := r.URL.Query().Get("fields"); fields != "summary,status" {
+			t.Errorf("Expected custom field list, got %s", fields)
+		}
+
+		response := JiraSearchResponse{Total: 10, MaxResults: 3}
+		for i := 0; i < 3; i++ {
+			response.Issues = append(response.Issues, JiraIssue{Key: fmt.Sprintf("TEST-%d", i+1)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+	tickets, err := service.Search("project = TEST", &SearchOptions{
+		MaxResults: 3,
+		Fields:     []string{"summary", "status"},
+		Limit:      2,
+	}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to iterate search: %v", err)
+	}
+
+	if len(tickets) != 2 {
+		t.Errorf("Expected limit of 2 tickets, got %d", len(tickets))
+	}
+	if requests != 1 {
+		t.Errorf("Expected a single page request, got %d", requests)
+	}
+}
+
+func TestGetEpicChildrenFetchesAllPages(t *testing.T) {
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	requests := 0
+	var jql string
+	server := newPagedSearchServerWithJQL(t, 250, &requests, &jql)
+	defer server.Close()
+
+	service := newTestService(server.URL)
+	service.SetMetadataService(NewMetadataService(NewClient(&types.JiraConfig{URL: metadataServer.URL}), nil))
+	children, err := service.GetEpicChildren(context.Background(), "TEST-100")
+	if err != nil {
+		t.Fatalf("Failed to fetch epic children: %v", err)
+	}
+
+	if len(children) != 250 {
+		t.Errorf("Expected 250 children, got %d", len(children))
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 page requests with the default page size, got %d", requests)
+	}
+	if jql != "cf[10014] = TEST-100 ORDER BY created DESC" {
+		t.Errorf("Expected children through the Epic Link field, got %q", jql)
+	}
+}
+
+func TestSearchIteratorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errorMessages":["bad jql"]}`))
+	}))
+	defer server.Close()
+
+	service := newTestService(server.URL)
+	it := service.Search("nonsense", nil)
+	if it.Next(context.Background()) {
+		t.Fatal("Expected iteration to stop on error")
+	}
+	if it.Err() == nil {
+		t.Error("Expected iterator error")
+	}
+}
@@ -0,0 +1,408 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// agileAPIPath is the prefix of the Jira Software (Agile) REST API, which
+// Cloud and Server serve alike
+const agileAPIPath = "/rest/agile/1.0"
+
+// greenhopperSprintPrefix starts the sprint field values Server returns
+// instead of objects, e.g. "com.atlassian.greenhopper.service.sprint.Sprint@1f[id=1,...]"
+const greenhopperSprintPrefix = "com.atlassian.greenhopper.service.sprint.Sprint"
+
+// greenhopperAttribute finds the attributes in a Server sprint field value
+var greenhopperAttribute = regexp.MustCompile(`(?:^|,)([a-zA-Z]+)=`)
+
+// doAgileRequest performs a request against the Agile REST API
+func (c *Client) doAgileRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Response, error) {
+	return c.doRequestAt(ctx, method, agileAPIPath+endpoint, body, nil)
+}
+
+// getAgile fetches an Agile API endpoint and decodes the response into v
+func (c *Client) getAgile(ctx context.Context, endpoint string, v interface{}) error {
+	resp, err := c.doAgileRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return c.parseErrorResponse(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return nil
+}
+
+// GetBoards fetches the boards of a project, or every board when projectKey is empty
+func (c *Client) GetBoards(ctx context.Context, projectKey string) ([]JiraBoard, error) {
+	var boards []JiraBoard
+
+	for {
+		params := url.Values{}
+		params.Set("startAt", strconv.Itoa(len(boards)))
+		if projectKey != "" {
+			params.Set("projectKeyOrId", projectKey)
+		}
+
+		var page JiraBoardsResponse
+		if err := c.getAgile(ctx, "/board?"+params.Encode(), &page); err != nil {
+			return nil, err
+		}
+
+		boards = append(boards, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return boards, nil
+		}
+	}
+}
+
+// GetBoard fetches a board by ID
+func (c *Client) GetBoard(ctx context.Context, boardID int) (*JiraBoard, error) {
+	var board JiraBoard
+	if err := c.getAgile(ctx, fmt.Sprintf("/board/%d", boardID), &board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// GetSprints fetches a board's sprints in the given states, or in any state
+// when none are given
+func (c *Client) GetSprints(ctx context.Context, boardID int, states ...string) ([]JiraSprint, error) {
+	var sprints []JiraSprint
+
+	for {
+		params := url.Values{}
+		params.Set("startAt", strconv.Itoa(len(sprints)))
+		if len(states) > 0 {
+			params.Set("state", strings.Join(states, ","))
+		}
+
+		var page JiraSprintsResponse
+		if err := c.getAgile(ctx, fmt.Sprintf("/board/%d/sprint?%s", boardID, params.Encode()), &page); err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+	}
+}
+
+// GetSprint fetches a sprint by ID
+func (c *Client) GetSprint(ctx context.Context, sprintID int) (*JiraSprint, error) {
+	var sprint JiraSprint
+	if err := c.getAgile(ctx, fmt.Sprintf("/sprint/%d", sprintID), &sprint); err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// GetSprintIssues fetches every issue in a sprint
+func (c *Client) GetSprintIssues(ctx context.Context, sprintID int) ([]JiraIssue, error) {
+	var issues []JiraIssue
+
+	for {
+		var page JiraSearchResponse
+		endpoint := fmt.Sprintf("/sprint/%d/issue?startAt=%d", sprintID, len(issues))
+		if err := c.getAgile(ctx, endpoint, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// MoveIssuesToSprint moves issues into a sprint, taking them out of any
+// other open sprint
+func (c *Client) MoveIssuesToSprint(ctx context.Context, sprintID int, issueKeys []string) error {
+	body, err := json.Marshal(&JiraMoveToSprintRequest{Issues: issueKeys})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/sprint/%d/issue", sprintID)
+	resp, err := c.doAgileRequest(ctx, "POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// Board finds the board sprints are planned on. boardID selects a board
+// outright; otherwise the configured board is used, and failing that the
+// project's only scrum board.
+func (ts *TicketService) Board(ctx context.Context, projectKey string, boardID int) (*types.Board, error) {
+	if boardID == 0 {
+		boardID = ts.client.config.BoardID
+	}
+	if boardID != 0 {
+		jiraBoard, err := ts.client.GetBoard(ctx, boardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch board %d: %v", boardID, err)
+		}
+		board := convertBoard(jiraBoard)
+		return &board, nil
+	}
+
+	boards, err := ts.Boards(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var scrum []types.Board
+	for _, board := range boards {
+		if board.Type == "scrum" {
+			scrum = append(scrum, board)
+		}
+	}
+
+	switch len(scrum) {
+	case 0:
+		return nil, fmt.Errorf("project %s has no scrum board", projectKey)
+	case 1:
+		return &scrum[0], nil
+	default:
+		var names []string
+		for _, board := range scrum {
+			names = append(names, fmt.Sprintf("%s (%d)", board.Name, board.ID))
+		}
+		return nil, fmt.Errorf("project %s has several scrum boards: %s; set jira.board_id to choose one", projectKey, strings.Join(names, ", "))
+	}
+}
+
+// Boards lists the boards of a project
+func (ts *TicketService) Boards(ctx context.Context, projectKey string) ([]types.Board, error) {
+	jiraBoards, err := ts.client.GetBoards(ctx, projectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch boards: %v", err)
+	}
+
+	boards := make([]types.Board, 0, len(jiraBoards))
+	for i := range jiraBoards {
+		boards = append(boards, convertBoard(&jiraBoards[i]))
+	}
+	return boards, nil
+}
+
+// Sprints lists a board's sprints in the given states, or in any state when
+// none are given
+func (ts *TicketService) Sprints(ctx context.Context, boardID int, states ...string) ([]*types.Sprint, error) {
+	jiraSprints, err := ts.client.GetSprints(ctx, boardID, states...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprints of board %d: %v", boardID, err)
+	}
+
+	sprints := make([]*types.Sprint, 0, len(jiraSprints))
+	for i := range jiraSprints {
+		sprints = append(sprints, convertSprint(&jiraSprints[i]))
+	}
+	return sprints, nil
+}
+
+// FindSprint finds the open sprint on a board selected by query: its ID, its
+// name, or "current" for the active sprint
+func (ts *TicketService) FindSprint(ctx context.Context, boardID int, query string) (*types.Sprint, error) {
+	sprints, err := ts.Sprints(ctx, boardID, types.SprintActive, types.SprintFuture)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sprint := range sprints {
+		if sprint.Matches(query) {
+			return sprint, nil
+		}
+	}
+
+	if strings.EqualFold(strings.TrimSpace(query), types.SprintCurrent) {
+		return nil, fmt.Errorf("board %d has no active sprint", boardID)
+	}
+	return nil, fmt.Errorf("no open sprint on board %d matches %q", boardID, query)
+}
+
+// SprintTickets fetches the tickets in a sprint
+func (ts *TicketService) SprintTickets(ctx context.Context, sprint *types.Sprint) ([]*types.Ticket, error) {
+	issues, err := ts.client.GetSprintIssues(ctx, sprint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues in %s: %v", sprint.Name, err)
+	}
+
+	tickets := make([]*types.Ticket, 0, len(issues))
+	for i := range issues {
+		ticket := ts.convertJiraIssueToTicket(ctx, &issues[i])
+		ticket.Metadata.Sprint = sprint
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// MoveToSprint moves tickets into a sprint
+func (ts *TicketService) MoveToSprint(ctx context.Context, sprint *types.Sprint, ticketKeys ...string) error {
+	if err := ts.client.MoveIssuesToSprint(ctx, sprint.ID, ticketKeys); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %v", strings.Join(ticketKeys, ", "), sprint.Name, err)
+	}
+	return nil
+}
+
+// issueSprint finds the sprint an issue is planned in: the Agile API's
+// sprint field when present, otherwise the sprint custom field. An issue
+// carried over between sprints is in the active one, then the next future
+// one, and otherwise was last in the most recent closed one.
+func issueSprint(fields *JiraIssueFields) *types.Sprint {
+	if fields.Sprint != nil {
+		return convertSprint(fields.Sprint)
+	}
+
+	names := make([]string, 0, len(fields.CustomFields))
+	for name := range fields.CustomFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sprints []*types.Sprint
+	for _, name := range names {
+		values, ok := fields.CustomFields[name].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			if sprint := parseSprintValue(value); sprint != nil {
+				sprints = append(sprints, sprint)
+			}
+		}
+	}
+
+	for _, state := range []string{types.SprintActive, types.SprintFuture} {
+		for _, sprint := range sprints {
+			if strings.EqualFold(sprint.State, state) {
+				return sprint
+			}
+		}
+	}
+	if len(sprints) > 0 {
+		return sprints[len(sprints)-1]
+	}
+	return nil
+}
+
+// parseSprintValue reads one value of the sprint custom field, an object on
+// Cloud and a greenhopper string on Server; anything else is not a sprint
+func parseSprintValue(value interface{}) *types.Sprint {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["state"]; !ok {
+			return nil
+		}
+		if _, ok := v["name"]; !ok {
+			return nil
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		var sprint JiraSprint
+		if err := json.Unmarshal(data, &sprint); err != nil || sprint.ID == 0 {
+			return nil
+		}
+		return convertSprint(&sprint)
+	case string:
+		return parseGreenhopperSprint(v)
+	}
+	return nil
+}
+
+// parseGreenhopperSprint reads a Server sprint field value such as
+// "com.atlassian.greenhopper.service.sprint.Sprint@1f[id=3,rapidViewId=2,state=ACTIVE,name=Sprint 3,...]"
+func parseGreenhopperSprint(value string) *types.Sprint {
+	start, end := strings.Index(value, "["), strings.LastIndex(value, "]")
+	if !strings.HasPrefix(value, greenhopperSprintPrefix) || start < 0 || end < start {
+		return nil
+	}
+	body := value[start+1 : end]
+
+	attributes := make(map[string]string)
+	matches := greenhopperAttribute.FindAllStringSubmatchIndex(body, -1)
+	for i, match := range matches {
+		valueEnd := len(body)
+		if i+1 < len(matches) {
+			valueEnd = matches[i+1][0]
+		}
+		attributes[body[match[2]:match[3]]] = body[match[1]:valueEnd]
+	}
+
+	id, err := strconv.Atoi(attributes["id"])
+	if err != nil {
+		return nil
+	}
+
+	sprint := &types.Sprint{
+		ID:    id,
+		Name:  attributes["name"],
+		State: strings.ToLower(attributes["state"]),
+	}
+	sprint.BoardID, _ = strconv.Atoi(attributes["rapidViewId"])
+	if goal := attributes["goal"]; goal != "<null>" {
+		sprint.Goal = goal
+	}
+	sprint.StartDate, _ = time.Parse(time.RFC3339, attributes["startDate"])
+	sprint.EndDate, _ = time.Parse(time.RFC3339, attributes["endDate"])
+	return sprint
+}
+
+// convertBoard converts a Jira board to our internal format
+func convertBoard(jiraBoard *JiraBoard) types.Board {
+	board := types.Board{
+		ID:   jiraBoard.ID,
+		Name: jiraBoard.Name,
+		Type: jiraBoard.Type,
+	}
+	if jiraBoard.Location != nil {
+		board.ProjectKey = jiraBoard.Location.ProjectKey
+	}
+	return board
+}
+
+// convertSprint converts a Jira sprint to our internal format
+func convertSprint(jiraSprint *JiraSprint) *types.Sprint {
+	boardID := jiraSprint.OriginBoardID
+	if boardID == 0 {
+		boardID = jiraSprint.BoardID
+	}
+
+	return &types.Sprint{
+		ID:        jiraSprint.ID,
+		Name:      jiraSprint.Name,
+		State:     strings.ToLower(jiraSprint.State),
+		BoardID:   boardID,
+		Goal:      jiraSprint.Goal,
+		StartDate: jiraSprint.StartDate.Time,
+		EndDate:   jiraSprint.EndDate.Time,
+	}
+}
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newAgileServer serves a project with a scrum and a kanban board, an active
+// and a future sprint, and accepts moves into the future sprint
+func newAgileServer(t *testing.T, moved *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/agile/1.0/board":
+			if project := r.URL.Query().Get("projectKeyOrId"); project != "PROJ" {
+				t.Errorf("Expected boards of PROJ, got %q", project)
+			}
+			// Two pages of boards
+			if r.URL.Query().Get("startAt") == "0" {
+				fmt.Fprint(w, `{"isLast": false, "values": [{"id": 5, "name": "PROJ board", "type": "scrum", "location": {"projectKey": "PROJ"}}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"isLast": true, "values": [{"id": 6, "name": "PROJ flow", "type": "kanban"}]}`)
+		case r.URL.Path == "/rest/agile/1.0/board/5/sprint":
+			if state := r.URL.Query().Get("state"); state != "active,future" {
+				t.Errorf("Expected open sprints, got state %q", state)
+			}
+			fmt.Fprint(w, `{"isLast": true, "values": [
+				{"id": 7, "name": "Sprint 7", "state": "active", "originBoardId": 5, "goal": "Ship MFA", "startDate": "2024-03-04T09:00:00.000Z", "endDate": "2024-03-18T09:00:00.000Z"},
+				{"id": 8, "name": "Sprint 8", "state": "future", "originBoardId": 5}
+			]}`)
+		case r.URL.Path == "/rest/agile/1.0/sprint/7/issue":
+			fmt.Fprint(w, `{"startAt": 0, "total": 1, "issues": [
+				{"key": "PROJ-1", "fields": {"summary": "Login", "status": {"name": "In Progress"}, "sprint": {"id": 7, "name": "Sprint 7", "state": "active"}}}
+			]}`)
+		case r.Method == "POST" && r.URL.Path == "/rest/agile/1.0/sprint/8/issue":
+			var request JiraMoveToSprintRequest
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			*moved = append(*moved, request.Issues...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestBoardPicksOnlyScrumBoard(t *testing.T) {
+	server := newAgileServer(t, nil)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	boards, err := service.Boards(context.Background(), "PROJ")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(boards) != 2 || boards[0].ProjectKey != "PROJ" {
+		t.Errorf("Unexpected boards: %+v", boards)
+	}
+
+	board, err := service.Board(context.Background(), "PROJ", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if board.ID != 5 {
+		t.Errorf("Expected scrum board 5, got %+v", board)
+	}
+}
+
+func TestSprints(t *testing.T) {
+	var moved []string
+	server := newAgileServer(t, &moved)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	current, err := service.FindSprint(context.Background(), 5, "current")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if current.ID != 7 || current.BoardID != 5 || current.Goal != "Ship MFA" || current.EndDate.IsZero() {
+		t.Errorf("Unexpected current sprint: %+v", current)
+	}
+
+	tickets, err := service.SprintTickets(context.Background(), current)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tickets) != 1 || tickets[0].Metadata.Sprint == nil || tickets[0].Metadata.Sprint.ID != 7 {
+		t.Errorf("Expected PROJ-1 in sprint 7, got %+v", tickets)
+	}
+
+	next, err := service.FindSprint(context.Background(), 5, "sprint 8")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := service.MoveToSprint(context.Background(), next, "PROJ-1", "PROJ-2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(moved, ",") != "PROJ-1,PROJ-2" {
+		t.Errorf("Unexpected moved issues: %v", moved)
+	}
+
+	if _, err := service.FindSprint(context.Background(), 5, "Sprint 9"); err == nil {
+		t.Error("Expected error for unknown sprint")
+	}
+}
+
+func TestIssueSprintFromCustomField(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		expected *types.Sprint
+	}{
+		{
+			name:     "cloud carried over",
+			field:    `[{"id": 6, "name": "Sprint 6", "state": "closed", "boardId": 5}, {"id": 7, "name": "Sprint 7", "state": "active", "boardId": 5}]`,
+			expected: &types.Sprint{ID: 7, Name: "Sprint 7", State: types.SprintActive, BoardID: 5},
+		},
+		{
+			name:     "server",
+			field:    `["com.atlassian.greenhopper.service.sprint.Sprint@1f[id=3,rapidViewId=2,state=FUTURE,name=Sprint 3, hardening,goal=<null>,startDate=<null>,endDate=<null>,sequence=3]"]`,
+			expected: &types.Sprint{ID: 3, Name: "Sprint 3, hardening", State: types.SprintFuture, BoardID: 2},
+		},
+		{
+			name:  "not a sprint",
+			field: `[{"value": "Red"}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		var fields JiraIssueFields
+		if err := json.Unmarshal([]byte(`{"summary": "Test", "customfield_10020": `+tt.field+`}`), &fields); err != nil {
+			t.Fatalf("%s: failed to decode fields: %v", tt.name, err)
+		}
+
+		sprint := issueSprint(&fields)
+		switch {
+		case tt.expected == nil && sprint != nil:
+			t.Errorf("%s: expected no sprint, got %+v", tt.name, sprint)
+		case tt.expected != nil && (sprint == nil || *sprint != *tt.expected):
+			t.Errorf("%s: got %+v, want %+v", tt.name, sprint, tt.expected)
+		}
+	}
+}
+
+func TestSearchRecordsSprint(t *testing.T) {
+	// The Sprint field is customfield_10020 on the metadata server
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/rest/api/3/search") {
+			http.Redirect(w, r, metadataServer.URL+r.URL.String(), http.StatusTemporaryRedirect)
+			return
+		}
+		if fields := r.URL.Query().Get("fields"); !strings.Contains(fields, "customfield_10020") {
+			t.Errorf("Expected the search to request the Sprint field, got %s", fields)
+		}
+		fmt.Fprint(w, `{"isLast": true, "issues": [{"key": "TEST-1", "fields": {
+			"summary": "Planned",
+			"issuetype": {"name": "Task"},
+			"customfield_10020": [{"id": 7, "name": "Sprint 7", "state": "active"}]
+		}}]}`)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+	tickets, err := service.Search("project = TEST", nil).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(tickets) != 1 || tickets[0].Metadata.Sprint == nil || tickets[0].Metadata.Sprint.ID != 7 {
+		t.Errorf("Expected TEST-1 to record sprint 7, got %+v", tickets)
+	}
+}
@@ -3,21 +3,92 @@ package jira
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
 
+// issueKeyPattern matches an issue key at the start of a changelog value
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9]+`)
+
 // TicketService provides high-level ticket operations
 type TicketService struct {
-	client *Client
+	client   *Client
+	metadata *MetadataService
+	users    *UserDirectory
+
+	// The epic link field, resolved once through the metadata
+	epicFieldMu sync.Mutex
+	epicField   *string
+
+	// A failed metadata lookup, remembered so reading many issues does not
+	// ask Jira again for each one
+	metadataMu  sync.Mutex
+	metadataErr error
 }
 
 // NewTicketService creates a new ticket service
 func NewTicketService(client *Client) *TicketService {
 	return &TicketService{
-		client: client,
+		client:   client,
+		metadata: NewMetadataService(client, nil),
+		users:    NewUserDirectory(client, nil),
+	}
+}
+
+// SetMetadataService replaces the metadata service used to resolve IDs,
+// typically with one backed by a persistent cache
+func (ts *TicketService) SetMetadataService(metadata *MetadataService) {
+	ts.metadata = metadata
+
+	ts.epicFieldMu.Lock()
+	ts.epicField = nil
+	ts.epicFieldMu.Unlock()
+
+	ts.metadataMu.Lock()
+	ts.metadataErr = nil
+	ts.metadataMu.Unlock()
+}
+
+// projectMetadata returns the configured project's metadata for reading
+// issues. A failure is remembered for the rest of the run, unless it came
+// from ctx ending, in which case the next caller tries again.
+func (ts *TicketService) projectMetadata(ctx context.Context) (*ProjectMetadata, error) {
+	if ts.client.config == nil || ts.client.config.Project == "" || ts.metadata == nil {
+		return nil, fmt.Errorf("no project metadata available")
+	}
+
+	ts.metadataMu.Lock()
+	defer ts.metadataMu.Unlock()
+
+	if ts.metadataErr != nil {
+		return nil, ts.metadataErr
+	}
+
+	meta, err := ts.metadata.Project(ctx, ts.client.config.Project)
+	if err != nil && ctx.Err() == nil {
+		ts.metadataErr = err
 	}
+	return meta, err
+}
+
+// Metadata returns the metadata service used to resolve IDs
+func (ts *TicketService) Metadata() *MetadataService {
+	return ts.metadata
+}
+
+// SetUserDirectory replaces the directory used to resolve users, typically
+// with one backed by a persistent cache
+func (ts *TicketService) SetUserDirectory(users *UserDirectory) {
+	ts.users = users
+}
+
+// Users returns the directory used to resolve users
+func (ts *TicketService) Users() *UserDirectory {
+	return ts.users
 }
 
 // GetTicket fetches a ticket and converts it to our internal format
@@ -27,13 +98,35 @@ func (ts *TicketService) GetTicket(ctx context.Context, key string) (*types.Tick
 		return nil, fmt.Errorf("failed to fetch issue %s: %v", key, err)
 	}
 
-	return ts.convertJiraIssueToTicket(jiraIssue), nil
+	return ts.convertJiraIssueToTicket(ctx, jiraIssue), nil
 }
 
 // CreateTicket creates a new ticket from our internal format
 func (ts *TicketService) CreateTicket(ctx context.Context, ticket *types.Ticket) (*types.Ticket, error) {
+	request, err := ts.createRequest(ctx, ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the issue
+	response, err := ts.client.CreateIssue(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %v", err)
+	}
+
+	// Fetch the created issue to get full details
+	return ts.GetTicket(ctx, response.Key)
+}
+
+// createRequest builds the request that creates ticket in Jira
+func (ts *TicketService) createRequest(ctx context.Context, ticket *types.Ticket) (*JiraCreateIssueRequest, error) {
+	project := ticket.Metadata.Project
+	if project == "" {
+		project = ts.client.config.Project
+	}
+
 	// Determine issue type ID based on ticket type
-	issueTypeID, err := ts.getIssueTypeID(ticket.Type)
+	issueTypeID, err := ts.getIssueTypeID(ctx, project, ticket.Type)
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +135,10 @@ func (ts *TicketService) CreateTicket(ctx context.Context, ticket *types.Ticket)
 	request := &JiraCreateIssueRequest{
 		Fields: JiraCreateIssueFields{
 			Project: JiraProjectReference{
-				Key: ticket.Metadata.Project,
+				Key: project,
 			},
 			Summary:     ticket.Title,
-			Description: ticket.Description,
+			Description: ts.client.richText(ctx, ticket.Description),
 			IssueType: JiraIssueTypeRef{
 				ID: issueTypeID,
 			},
@@ -53,32 +146,71 @@ func (ts *TicketService) CreateTicket(ctx context.Context, ticket *types.Ticket)
 		},
 	}
 
+	// Versions and components are referred to by name
+	if len(ticket.Metadata.FixVersions) > 0 {
+		request.Fields.FixVersions = nameRefs(ticket.Metadata.FixVersions)
+	}
+	if len(ticket.Metadata.Components) > 0 {
+		request.Fields.Components = nameRefs(ticket.Metadata.Components)
+	}
+
 	// Add priority if specified
 	if ticket.Priority != "" {
-		priorityID, err := ts.getPriorityID(ticket.Priority)
-		if err == nil {
-			request.Fields.Priority = &JiraPriorityRef{ID: priorityID}
+		priority, err := ts.priorityRef(ctx, project, ticket.Priority)
+		if err != nil {
+			return nil, err
 		}
+		request.Fields.Priority = priority
 	}
 
 	// Add assignee if specified
 	if ticket.Metadata.Assignee != "" {
-		request.Fields.Assignee = &JiraUserRef{AccountID: ticket.Metadata.Assignee}
+		assignee, err := ts.assigneeRef(ctx, ticket.Metadata.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		request.Fields.Assignee = assignee
 	}
 
-	// Add parent for subtasks
-	if ticket.Type == types.TicketTypeSubtask && ticket.Relationships.ParentKey != "" {
-		request.Fields.Parent = &JiraParentRef{Key: ticket.Relationships.ParentKey}
+	// Add parent: subtasks always use the parent field, other issues the
+	// field the project links epics with
+	var epicLink map[string]interface{}
+	if parentKey := ticket.Relationships.ParentKey; parentKey != "" {
+		field := FieldParent
+		if ticket.Type != types.TicketTypeSubtask {
+			field = ts.epicParentField(ctx, project)
+		}
+
+		if field == FieldParent {
+			request.Fields.Parent = &JiraParentRef{Key: parentKey}
+		} else {
+			epicLink = map[string]interface{}{field: parentKey}
+		}
 	}
 
-	// Create the issue
-	response, err := ts.client.CreateIssue(ctx, request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create issue: %v", err)
+	// Add custom fields that have values
+	var customNames []string
+	for name, value := range ticket.JiraData.CustomFields {
+		if value != nil {
+			customNames = append(customNames, name)
+		}
+	}
+	if len(customNames) > 0 {
+		sort.Strings(customNames)
+		customFields, err := ts.customFieldsRequest(ctx, ticket.JiraData.CustomFields, customNames)
+		if err != nil {
+			return nil, err
+		}
+		request.Fields.CustomFields = customFields
+	}
+	for id, value := range epicLink {
+		if request.Fields.CustomFields == nil {
+			request.Fields.CustomFields = make(map[string]interface{})
+		}
+		request.Fields.CustomFields[id] = value
 	}
 
-	// Fetch the created issue to get full details
-	return ts.GetTicket(ctx, response.Key)
+	return request, nil
 }
 
 // AddComment adds a comment to a ticket
@@ -100,7 +232,7 @@ func (ts *TicketService) SearchTickets(ctx context.Context, jql string, maxResul
 
 	var tickets []*types.Ticket
 	for _, issue := range response.Issues {
-		ticket := ts.convertJiraIssueToTicket(&issue)
+		ticket := ts.convertJiraIssueToTicket(ctx, &issue)
 		tickets = append(tickets, ticket)
 	}
 
@@ -108,43 +240,48 @@ func (ts *TicketService) SearchTickets(ctx context.Context, jql string, maxResul
 }
 
 // convertJiraIssueToTicket converts a Jira API issue to our internal ticket format
-func (ts *TicketService) convertJiraIssueToTicket(jiraIssue *JiraIssue) *types.Ticket {
+func (ts *TicketService) convertJiraIssueToTicket(ctx context.Context, jiraIssue *JiraIssue) *types.Ticket {
 	ticket := &types.Ticket{
 		Key:         jiraIssue.Key,
 		Title:       jiraIssue.Fields.Summary,
 		Type:        ts.convertIssueType(jiraIssue.Fields.IssueType.Name),
 		Status:      jiraIssue.Fields.Status.Name,
 		Priority:    jiraIssue.Fields.Priority.Name,
-		Description: jiraIssue.Fields.Description,
+		Description: jiraIssue.Fields.Description.Markdown(),
 		Metadata: types.TicketMetadata{
-			Project: jiraIssue.Fields.Project.Key,
-			Created: jiraIssue.Fields.Created,
-			Updated: jiraIssue.Fields.Updated,
-			Labels:  jiraIssue.Fields.Labels,
+			Project:     jiraIssue.Fields.Project.Key,
+			Created:     jiraIssue.Fields.Created.Time,
+			Updated:     jiraIssue.Fields.Updated.Time,
+			Labels:      jiraIssue.Fields.Labels,
+			FixVersions: versionNames(jiraIssue.Fields.FixVersions),
+			Components:  componentNames(jiraIssue.Fields.Components),
+			Sprint:      issueSprint(&jiraIssue.Fields),
 		},
 		Relationships: types.TicketRelationships{
 			Children: []string{},
+			Links:    convertIssueLinks(jiraIssue.Fields.IssueLinks),
 		},
 		JiraData: types.JiraData{
 			URL:          fmt.Sprintf("%s/browse/%s", ts.client.baseURL, jiraIssue.Key),
-			CustomFields: make(map[string]interface{}),
+			CustomFields: ts.decodeCustomFields(ctx, &jiraIssue.Fields),
 		},
 		LocalData: types.LocalData{
-			LastSync:     jiraIssue.Fields.Updated,
+			LastSync:     jiraIssue.Fields.Updated.Time,
 			LocalChanges: false,
 			AIEnhanced:   false,
 		},
 	}
 
-	// Set assignee if available
+	// Set assignee if available; emails are often hidden, so keep the ID
 	if jiraIssue.Fields.Assignee != nil {
-		ticket.Metadata.Assignee = jiraIssue.Fields.Assignee.Email
+		assignee := convertUser(jiraIssue.Fields.Assignee)
+		ticket.Metadata.Assignee = assignee.ID
+		ticket.Metadata.AssigneeName = assignee.DisplayName
+		ts.users.Remember(assignee)
 	}
 
-	// Extract parent relationships from changelog
-	if jiraIssue.Changelog != nil {
-		ts.extractParentRelationships(ticket, jiraIssue.Changelog)
-	}
+	// Parent and children come from the issue's fields, falling back to the changelog
+	ts.extractParentRelationships(ctx, ticket, jiraIssue)
 
 	return ticket
 }
@@ -163,59 +300,179 @@ func (ts *TicketService) convertIssueType(jiraType string) string {
 	}
 }
 
-// extractParentRelationships extracts parent/child relationships from changelog
-func (ts *TicketService) extractParentRelationships(ticket *types.Ticket, changelog *JiraChangelog) {
-	// This is a simplified implementation
-	// In a real implementation, you'd parse the changelog to find parent/child relationships
-	// For now, we'll leave this empty and let the calling code handle relationships
+// extractParentRelationships sets a ticket's parent and children. The parent
+// is the parent field when present, then the configured epic link field, and
+// otherwise the last parent change in the changelog. Children are the
+// issue's subtasks; epic children are found by searching.
+func (ts *TicketService) extractParentRelationships(ctx context.Context, ticket *types.Ticket, jiraIssue *JiraIssue) {
+	fields := &jiraIssue.Fields
+
+	switch {
+	case fields.Parent != nil && fields.Parent.Key != "":
+		ticket.Relationships.ParentKey = fields.Parent.Key
+	case ts.epicLinkKey(ctx, fields) != "":
+		ticket.Relationships.ParentKey = ts.epicLinkKey(ctx, fields)
+	case jiraIssue.Changelog != nil:
+		ticket.Relationships.ParentKey = ts.parentFromChangelog(ctx, jiraIssue.Changelog.Histories)
+	}
+
+	for _, subtask := range fields.Subtasks {
+		if subtask.Key != "" {
+			ticket.Relationships.Children = append(ticket.Relationships.Children, subtask.Key)
+		}
+	}
+}
+
+// epicLinkKey returns the epic an issue is linked to through the configured
+// epic link field, if any
+func (ts *TicketService) epicLinkKey(ctx context.Context, fields *JiraIssueFields) string {
+	field := ts.epicLinkField(ctx)
+	if field == "" {
+		return ""
+	}
+	key, _ := fields.CustomFields[field].(string)
+	return key
+}
+
+// epicLinkField returns the ID of the Epic Link custom field issues are
+// read and searched with. It is the field the configured project links epics
+// with, resolved once through the metadata as epics are created with it,
+// and the configured epic_link_field when that cannot be told.
+func (ts *TicketService) epicLinkField(ctx context.Context) string {
+	ts.epicFieldMu.Lock()
+	defer ts.epicFieldMu.Unlock()
+
+	if ts.epicField != nil {
+		return *ts.epicField
+	}
+
+	field := ts.configuredEpicLinkField()
+	meta, err := ts.projectMetadata(ctx)
+	if err == nil {
+		if parentField := meta.EpicParentField(field); parentField != FieldParent {
+			field = parentField
+		}
+	} else if ctx.Err() != nil {
+		// Cut short by the caller; a later call can still resolve it
+		return field
+	}
+
+	// Remembered even when the metadata is unavailable, so converting many
+	// issues does not ask Jira for it again each time
+	ts.epicField = &field
+	return field
+}
+
+// configuredEpicLinkField returns the epic_link_field from the config, if any
+func (ts *TicketService) configuredEpicLinkField() string {
+	if ts.client.config == nil {
+		return ""
+	}
+	return ts.client.config.EpicLinkField
+}
+
+// parentFromChangelog replays parent and epic link changes to find the
+// issue's current parent, or "" when it has none
+func (ts *TicketService) parentFromChangelog(ctx context.Context, histories []JiraChangelogHistory) string {
+	ordered := append([]JiraChangelogHistory{}, histories...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Created.Before(ordered[j].Created.Time)
+	})
+
+	parent := ""
+	for _, history := range ordered {
+		for _, item := range history.Items {
+			if ts.isParentField(ctx, item) {
+				parent = issueKeyPattern.FindString(item.To)
+			}
+		}
+	}
+	return parent
+}
+
+// isParentField reports whether a changelog item changes the issue's parent
+func (ts *TicketService) isParentField(ctx context.Context, item JiraChangelogItem) bool {
+	if field := ts.epicLinkField(ctx); field != "" && item.FieldID == field {
+		return true
+	}
+	switch strings.ToLower(item.Field) {
+	case "parent", "parent issue", "parent link", "epic link", "issueparentassociation":
+		return true
+	}
+	return false
 }
 
 // getIssueTypeID returns the Jira issue type ID for our ticket type
-func (ts *TicketService) getIssueTypeID(ticketType string) (string, error) {
-	// This is a simplified implementation
-	// In a real implementation, you'd fetch issue types from Jira and cache them
-	switch ticketType {
-	case types.TicketTypeEpic:
-		return "10000", nil // Epic issue type ID
-	case types.TicketTypeTask:
-		return "10001", nil // Story/Task issue type ID
-	case types.TicketTypeSubtask:
-		return "10003", nil // Sub-task issue type ID
-	default:
-		return "", fmt.Errorf("unknown ticket type: %s", ticketType)
-	}
-}
-
-// getPriorityID returns the Jira priority ID for our priority name
-func (ts *TicketService) getPriorityID(priority string) (string, error) {
-	// This is a simplified implementation
-	// In a real implementation, you'd fetch priorities from Jira and cache them
-	switch strings.ToLower(priority) {
-	case "highest":
-		return "1", nil
-	case "high":
-		return "2", nil
-	case "medium":
-		return "3", nil
-	case "low":
-		return "4", nil
-	case "lowest":
-		return "5", nil
-	default:
-		return "3", nil // Default to medium
+func (ts *TicketService) getIssueTypeID(ctx context.Context, project, ticketType string) (string, error) {
+	return ts.metadata.IssueTypeID(ctx, project, ticketType)
+}
+
+// priorityRef refers to a priority by its ID. A priority the project does
+// not have is an error; when the metadata cannot be loaded the priority is
+// sent by name for Jira to check.
+func (ts *TicketService) priorityRef(ctx context.Context, project, priority string) (*JiraPriorityRef, error) {
+	meta, err := ts.metadata.Project(ctx, project)
+	if err != nil {
+		return &JiraPriorityRef{Name: priority}, nil
+	}
+
+	if p, ok := meta.Priority(priority); ok {
+		return &JiraPriorityRef{ID: p.ID}, nil
+	}
+
+	var names []string
+	for _, p := range meta.Priorities {
+		names = append(names, p.Name)
 	}
+	return nil, fmt.Errorf("unknown priority %q; available: %s", priority, strings.Join(names, ", "))
 }
 
-// GetEpicChildren fetches all children of an epic
-func (ts *TicketService) GetEpicChildren(ctx context.Context, epicKey string) ([]*types.Ticket, error) {
-	// Search for issues that are linked to this epic
-	jql := fmt.Sprintf("'Epic Link' = %s ORDER BY created DESC", epicKey)
-	return ts.SearchTickets(ctx, jql, 100)
+// EpicChildren returns an iterator over all children of an epic, found
+// through whichever field the epic's project links epics with
+func (ts *TicketService) EpicChildren(ctx context.Context, epicKey string) *TicketIterator {
+	field := ts.epicParentField(ctx, keyProject(epicKey))
+
+	jql := fmt.Sprintf("parent = %s ORDER BY created DESC", epicKey)
+	if field != FieldParent {
+		jql = fmt.Sprintf("cf[%s] = %s ORDER BY created DESC", strings.TrimPrefix(field, customFieldPrefix), epicKey)
+	}
+	return ts.Search(jql, nil)
 }
 
-// GetTaskSubtasks fetches all subtasks of a task
-func (ts *TicketService) GetTaskSubtasks(ctx context.Context, taskKey string) ([]*types.Ticket, error) {
+// epicParentField returns the field that attaches issues in a project to
+// their epic. Without metadata, Server sites are assumed to use the Epic
+// Link field and Cloud sites parent.
+func (ts *TicketService) epicParentField(ctx context.Context, project string) string {
+	if meta, err := ts.metadata.Project(ctx, project); err == nil {
+		return meta.EpicParentField(ts.configuredEpicLinkField())
+	}
+	if field := ts.configuredEpicLinkField(); field != "" && ts.client.Flavor(ctx) == FlavorServer {
+		return field
+	}
+	return FieldParent
+}
+
+// keyProject returns the project part of an issue key, e.g. PROJ for PROJ-12
+func keyProject(key string) string {
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// TaskSubtasks returns an iterator over all subtasks of a task
+func (ts *TicketService) TaskSubtasks(taskKey string) *TicketIterator {
 	// Search for subtasks that have this task as parent
 	jql := fmt.Sprintf("parent = %s ORDER BY created DESC", taskKey)
-	return ts.SearchTickets(ctx, jql, 100)
+	return ts.Search(jql, nil)
+}
+
+// GetEpicChildren fetches all children of an epic, following every result page
+func (ts *TicketService) GetEpicChildren(ctx context.Context, epicKey string) ([]*types.Ticket, error) {
+	return ts.EpicChildren(ctx, epicKey).Collect(ctx)
+}
+
+// GetTaskSubtasks fetches all subtasks of a task, following every result page
+func (ts *TicketService) GetTaskSubtasks(ctx context.Context, taskKey string) ([]*types.Ticket, error) {
+	return ts.TaskSubtasks(taskKey).Collect(ctx)
 }
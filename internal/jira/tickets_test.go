@@ -2,6 +2,11 @@ package jira
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -64,8 +69,11 @@ func TestConvertIssueType(t *testing.T) {
 }
 
 func TestGetIssueTypeID(t *testing.T) {
+	server := newMetadataServer(t, nil)
+	defer server.Close()
+
 	config := &types.JiraConfig{
-		URL:           "https://test.atlassian.net",
+		URL:           server.URL,
 		Username:      "test@example.com",
 		Token:         "test-token",
 		Project:       "TEST",
@@ -80,14 +88,14 @@ func TestGetIssueTypeID(t *testing.T) {
 		expected   string
 		shouldErr  bool
 	}{
-		{types.TicketTypeEpic, "10000", false},
-		{types.TicketTypeTask, "10001", false},
-		{types.TicketTypeSubtask, "10003", false},
+		{types.TicketTypeEpic, "10100", false},
+		{types.TicketTypeTask, "10101", false},
+		{types.TicketTypeSubtask, "10103", false},
 		{"Unknown", "", true},
 	}
 
 	for _, tt := range tests {
-		result, err := service.getIssueTypeID(tt.ticketType)
+		result, err := service.getIssueTypeID(context.Background(), "TEST", tt.ticketType)
 		if tt.shouldErr && err == nil {
 			t.Errorf("getIssueTypeID(%s) should have returned error", tt.ticketType)
 		}
@@ -100,40 +108,73 @@ func TestGetIssueTypeID(t *testing.T) {
 	}
 }
 
-func TestGetPriorityID(t *testing.T) {
+func TestGetIssueTypeIDFromConfig(t *testing.T) {
+	server := newMetadataServer(t, nil)
+	defer server.Close()
+
 	config := &types.JiraConfig{
-		URL:           "https://test.atlassian.net",
-		Username:      "test@example.com",
-		Token:         "test-token",
-		Project:       "TEST",
-		EpicLinkField: "customfield_10014",
+		URL:        server.URL,
+		Username:   "test@example.com",
+		Token:      "test-token",
+		Project:    "TEST",
+		IssueTypes: map[string]string{"task": "story"},
 	}
 
-	client := NewClient(config)
-	service := NewTicketService(client)
+	service := NewTicketService(NewClient(config))
 
-	tests := []struct {
-		priority string
-		expected string
-	}{
-		{"Highest", "1"},
-		{"High", "2"},
-		{"Medium", "3"},
-		{"Low", "4"},
-		{"Lowest", "5"},
-		{"Unknown", "3"}, // Default to medium
-		{"", "3"},        // Default to medium
+	result, err := service.getIssueTypeID(context.Background(), "TEST", types.TicketTypeTask)
+	if err != nil {
+		t.Fatalf("getIssueTypeID returned unexpected error: %v", err)
+	}
+	if result != "10102" {
+		t.Errorf("Expected configured Story issue type 10102, got %s", result)
 	}
+}
 
-	for _, tt := range tests {
-		result, err := service.getPriorityID(tt.priority)
+func TestCreateRequestPriority(t *testing.T) {
+	server := newMetadataServer(t, nil)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+	ticket := types.NewTicket("", "Task", types.TicketTypeTask)
+
+	for name, id := range map[string]string{"Highest": "1", "high": "2", "P0 - Urgent": "10000"} {
+		ticket.Priority = name
+		request, err := service.createRequest(context.Background(), ticket)
 		if err != nil {
-			t.Errorf("getPriorityID(%s) returned unexpected error: %v", tt.priority, err)
+			t.Fatalf("createRequest failed: %v", err)
 		}
-		if result != tt.expected {
-			t.Errorf("getPriorityID(%s) = %s, expected %s", tt.priority, result, tt.expected)
+		if request.Fields.Priority == nil || request.Fields.Priority.ID != id {
+			t.Errorf("Expected priority %s to be ID %s, got %+v", name, id, request.Fields.Priority)
 		}
 	}
+
+	// A typo is reported rather than dropped
+	ticket.Priority = "Hihg"
+	if _, err := service.createRequest(context.Background(), ticket); err == nil || !strings.Contains(err.Error(), "Hihg") || !strings.Contains(err.Error(), "Highest") {
+		t.Errorf("Expected an error listing the priorities, got %v", err)
+	}
+
+	// Without metadata the name is sent for Jira to check
+	unavailable := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "NOMETA"}))
+	priority, err := unavailable.priorityRef(context.Background(), "NOMETA", "Hihg")
+	if err != nil || priority.Name != "Hihg" || priority.ID != "" {
+		t.Errorf("Expected the priority by name, got %+v (%v)", priority, err)
+	}
+}
+
+func TestIssueTypeIDWithoutConfig(t *testing.T) {
+	metadata := NewMetadataService(&Client{}, nil)
+	metadata.projects["TEST"] = &ProjectMetadata{
+		Project:    "TEST",
+		FetchedAt:  time.Now(),
+		IssueTypes: []JiraIssueType{{ID: "10100", Name: "Epic", HierarchyLevel: 1}, {ID: "10101", Name: "Task"}},
+	}
+
+	id, err := metadata.IssueTypeID(context.Background(), "TEST", types.TicketTypeTask)
+	if err != nil || id != "10101" {
+		t.Errorf("Expected the Task issue type, got %q (%v)", id, err)
+	}
 }
 
 func TestConvertJiraIssueToTicket(t *testing.T) {
@@ -154,7 +195,7 @@ func TestConvertJiraIssueToTicket(t *testing.T) {
 		ID:  "12345",
 		Fields: JiraIssueFields{
 			Summary:     "Test Issue",
-			Description: "This is a test issue",
+			Description: NewADFText("This is a test issue"),
 			Status: JiraStatus{
 				ID:   "10000",
 				Name: "In Progress",
@@ -177,14 +218,14 @@ func TestConvertJiraIssueToTicket(t *testing.T) {
 				DisplayName: "Test User",
 				Email:       "test@example.com",
 			},
-			Created: time.Now(),
-			Updated: time.Now(),
+			Created: JiraTime{time.Now()},
+			Updated: JiraTime{time.Now()},
 			Labels:  []string{"test", "example"},
 		},
 	}
 
 	// Convert to our ticket format
-	ticket := service.convertJiraIssueToTicket(jiraIssue)
+	ticket := service.convertJiraIssueToTicket(context.Background(), jiraIssue)
 
 	// Verify conversion
 	if ticket.Key != "TEST-123" {
@@ -215,8 +256,8 @@ func TestConvertJiraIssueToTicket(t *testing.T) {
 		t.Errorf("Expected project TEST, got %s", ticket.Metadata.Project)
 	}
 
-	if ticket.Metadata.Assignee != "test@example.com" {
-		t.Errorf("Expected assignee test@example.com, got %s", ticket.Metadata.Assignee)
+	if ticket.Metadata.Assignee != "12345" || ticket.Metadata.AssigneeName != "Test User" {
+		t.Errorf("Expected assignee 12345 (Test User), got %s (%s)", ticket.Metadata.Assignee, ticket.Metadata.AssigneeName)
 	}
 
 	if len(ticket.Metadata.Labels) != 2 {
@@ -255,7 +296,7 @@ func TestConvertJiraIssueToTicketWithEpic(t *testing.T) {
 	}
 
 	// Convert to our ticket format
-	ticket := service.convertJiraIssueToTicket(jiraIssue)
+	ticket := service.convertJiraIssueToTicket(context.Background(), jiraIssue)
 
 	// Verify it's converted to an epic
 	if ticket.Type != types.TicketTypeEpic {
@@ -290,7 +331,7 @@ func TestConvertJiraIssueToTicketWithSubtask(t *testing.T) {
 	}
 
 	// Convert to our ticket format
-	ticket := service.convertJiraIssueToTicket(jiraIssue)
+	ticket := service.convertJiraIssueToTicket(context.Background(), jiraIssue)
 
 	// Verify it's converted to a subtask
 	if ticket.Type != types.TicketTypeSubtask {
@@ -320,6 +361,107 @@ func TestGetEpicChildren(t *testing.T) {
 	}
 }
 
+func TestCreateTicketLinksEpic(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    string
+		ticket   string
+		field    string
+		expected string
+	}{
+		{name: "team-managed", style: ProjectStyleNextGen, ticket: types.TicketTypeTask, field: "parent", expected: `{"key":"TEST-100"}`},
+		{name: "company-managed", style: ProjectStyleClassic, ticket: types.TicketTypeTask, field: "customfield_10014", expected: `"TEST-100"`},
+		{name: "subtask", style: ProjectStyleClassic, ticket: types.TicketTypeSubtask, field: "parent", expected: `{"key":"TEST-100"}`},
+	}
+
+	for _, tt := range tests {
+		var sent map[string]json.RawMessage
+		metadataServer := newMetadataServer(t, nil)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/rest/api/3/project/TEST":
+				json.NewEncoder(w).Encode(JiraProjectDetails{JiraProject: JiraProject{Key: "TEST", Style: tt.style}})
+			case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue":
+				var request map[string]map[string]json.RawMessage
+				if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+					t.Fatalf("Failed to decode request: %v", err)
+				}
+				sent = request["fields"]
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id": "2", "key": "TEST-2"}`)
+			case r.URL.Path == "/rest/api/3/issue/TEST-2":
+				fmt.Fprint(w, `{"key": "TEST-2", "fields": {"summary": "Child"}}`)
+			default:
+				// Everything else comes from the shared metadata server
+				http.Redirect(w, r, metadataServer.URL+r.URL.String(), http.StatusTemporaryRedirect)
+			}
+		}))
+
+		service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST", EpicLinkField: "customfield_10014"}))
+
+		ticket := types.NewTicket("", "Child", tt.ticket)
+		ticket.Relationships.ParentKey = "TEST-100"
+		if _, err := service.CreateTicket(context.Background(), ticket); err != nil {
+			t.Errorf("%s: CreateTicket failed: %v", tt.name, err)
+		}
+
+		if string(sent[tt.field]) != tt.expected {
+			t.Errorf("%s: expected %s to be %s, got fields %v", tt.name, tt.field, tt.expected, sent)
+		}
+		for _, other := range []string{"parent", "customfield_10014"} {
+			if other != tt.field && sent[other] != nil {
+				t.Errorf("%s: expected no %s, got %s", tt.name, other, sent[other])
+			}
+		}
+
+		server.Close()
+		metadataServer.Close()
+	}
+}
+
+func TestEpicLinkFieldFromMetadata(t *testing.T) {
+	// The site's Epic Link field is customfield_10014, not the configured one
+	metadataServer := newMetadataServer(t, nil)
+	defer metadataServer.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: metadataServer.URL, Project: "TEST", EpicLinkField: "customfield_10500"}))
+
+	// The lookup follows the caller's context, and one cut short is retried
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if field := service.epicLinkField(cancelled); field != "customfield_10500" {
+		t.Errorf("Expected the configured field while the metadata cannot be read, got %s", field)
+	}
+
+	fields := strings.Join(service.searchFields(context.Background()), ",")
+	if !strings.Contains(fields, "customfield_10014") || strings.Contains(fields, "customfield_10500") {
+		t.Errorf("Expected searches to request the discovered field, got %s", fields)
+	}
+
+	// Issues linked through the discovered field get their parent
+	issue := &JiraIssue{
+		Key: "TEST-2",
+		Fields: JiraIssueFields{
+			Summary:      "Child",
+			IssueType:    JiraIssueType{Name: "Task"},
+			CustomFields: map[string]interface{}{"customfield_10014": "TEST-1"},
+		},
+	}
+	if ticket := service.convertJiraIssueToTicket(context.Background(), issue); ticket.Relationships.ParentKey != "TEST-1" {
+		t.Errorf("Expected parent TEST-1 from the discovered field, got %q", ticket.Relationships.ParentKey)
+	}
+
+	// Changelog entries are matched by the discovered field's ID
+	if !service.isParentField(context.Background(), JiraChangelogItem{FieldID: "customfield_10014", Field: "Epic"}) {
+		t.Error("Expected a change to the discovered field to change the parent")
+	}
+
+	// Tickets are created through the same field
+	if field := service.epicParentField(context.Background(), "TEST"); field != "customfield_10014" {
+		t.Errorf("Expected epics to be linked through customfield_10014, got %s", field)
+	}
+}
+
 func TestGetTaskSubtasks(t *testing.T) {
 	config := &types.JiraConfig{
 		URL:           "https://test.atlassian.net",
@@ -0,0 +1,286 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultStatusAliases maps common shorthands to workflow status names
+var defaultStatusAliases = map[string]string{
+	"todo":        "To Do",
+	"to-do":       "To Do",
+	"to do":       "To Do",
+	"open":        "To Do",
+	"progress":    "In Progress",
+	"in-progress": "In Progress",
+	"in progress": "In Progress",
+	"start":       "In Progress",
+	"review":      "In Review",
+	"in-review":   "In Review",
+	"done":        "Done",
+	"completed":   "Done",
+	"complete":    "Done",
+	"close":       "Done",
+	"blocked":     "Blocked",
+	"block":       "Blocked",
+}
+
+// statusCategoryAliases maps built-in status names to the status category
+// used when a workflow has no status with that exact name
+var statusCategoryAliases = map[string]string{
+	"To Do":       "new",
+	"In Progress": "indeterminate",
+	"Done":        "done",
+}
+
+// TransitionOptions supplies values for fields a transition screen may require
+type TransitionOptions struct {
+	Resolution string // Resolution name; defaults to Done or Fixed when required and offered
+	Comment    string // Markdown comment added with the transition
+}
+
+// GetTransitions fetches the transitions available on an issue, including screen fields
+func (c *Client) GetTransitions(ctx context.Context, issueKey string) ([]JiraTransition, error) {
+	endpoint := fmt.Sprintf("/issue/%s/transitions?expand=transitions.fields", issueKey)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var response JiraTransitionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return response.Transitions, nil
+}
+
+// TransitionIssue moves an issue through a workflow transition
+func (c *Client) TransitionIssue(ctx context.Context, issueKey string, request *JiraTransitionRequest) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s/transitions", issueKey)
+	resp, err := c.doRequest(ctx, "POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// GetTransitions returns the transitions available on a ticket
+func (ts *TicketService) GetTransitions(ctx context.Context, key string) ([]JiraTransition, error) {
+	transitions, err := ts.client.GetTransitions(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transitions for %s: %v", key, err)
+	}
+	return transitions, nil
+}
+
+// ResolveStatusAlias returns the status name an alias refers to in a project.
+// Project aliases take precedence over "*" aliases, which take precedence over
+// the built-in shorthands. Unknown input is returned unchanged.
+func (ts *TicketService) ResolveStatusAlias(project, input string) string {
+	alias := strings.ToLower(strings.TrimSpace(input))
+
+	for _, scope := range []string{project, "*"} {
+		for name, status := range ts.client.config.StatusAliases[scope] {
+			if strings.ToLower(name) == alias {
+				return status
+			}
+		}
+	}
+
+	if status, ok := defaultStatusAliases[alias]; ok {
+		return status
+	}
+	return strings.TrimSpace(input)
+}
+
+// FindTransition picks the transition matching a status name or alias
+func (ts *TicketService) FindTransition(transitions []JiraTransition, project, input string) (*JiraTransition, error) {
+	target := ts.ResolveStatusAlias(project, input)
+
+	// Match the target status first, then the transition name itself
+	for _, name := range []string{target, strings.TrimSpace(input)} {
+		for i := range transitions {
+			if strings.EqualFold(transitions[i].To.Name, name) {
+				return &transitions[i], nil
+			}
+		}
+		for i := range transitions {
+			if strings.EqualFold(transitions[i].Name, name) {
+				return &transitions[i], nil
+			}
+		}
+	}
+
+	// Built-in aliases also match any status in the same category
+	if category, ok := statusCategoryAliases[target]; ok {
+		for i := range transitions {
+			if transitions[i].To.StatusCategory != nil && transitions[i].To.StatusCategory.Key == category {
+				return &transitions[i], nil
+			}
+		}
+	}
+
+	var available []string
+	for _, transition := range transitions {
+		available = append(available, transition.To.Name)
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no transitions are available")
+	}
+	return nil, fmt.Errorf("no transition to %q; available: %s", target, strings.Join(available, ", "))
+}
+
+// CommentError is returned by TransitionTicket when the ticket changed
+// status but the comment sent after the transition could not be added
+type CommentError struct {
+	Key string
+	Err error
+}
+
+func (e *CommentError) Error() string {
+	return fmt.Sprintf("%s changed status, but the comment was not added: %v", e.Key, e.Err)
+}
+
+// TransitionTicket moves a ticket to the status named by input, filling in
+// screen fields such as resolution and comment. It returns the transition
+// used, together with a *CommentError when only the comment failed.
+func (ts *TicketService) TransitionTicket(ctx context.Context, key, project, input string, opts *TransitionOptions) (*JiraTransition, error) {
+	if opts == nil {
+		opts = &TransitionOptions{}
+	}
+
+	transitions, err := ts.GetTransitions(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := ts.FindTransition(transitions, project, input)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := ts.buildTransitionRequest(ctx, transition, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.client.TransitionIssue(ctx, key, request); err != nil {
+		return nil, fmt.Errorf("failed to transition %s: %v", key, err)
+	}
+
+	// Transitions without a comment field on their screen take the comment separately
+	if _, onScreen := transition.Fields["comment"]; opts.Comment != "" && !onScreen {
+		if err := ts.AddComment(ctx, key, opts.Comment); err != nil {
+			return transition, &CommentError{Key: key, Err: err}
+		}
+	}
+
+	return transition, nil
+}
+
+// buildTransitionRequest fills in the fields a transition screen requires
+func (ts *TicketService) buildTransitionRequest(ctx context.Context, transition *JiraTransition, opts *TransitionOptions) (*JiraTransitionRequest, error) {
+	request := &JiraTransitionRequest{
+		Transition: JiraTransitionRef{ID: transition.ID},
+	}
+
+	for id, field := range transition.Fields {
+		switch id {
+		case "resolution":
+			if !field.Required && opts.Resolution == "" {
+				continue
+			}
+			value, err := pickAllowedValue(field, opts.Resolution)
+			if err != nil {
+				return nil, err
+			}
+			if request.Fields == nil {
+				request.Fields = make(map[string]interface{})
+			}
+			request.Fields["resolution"] = map[string]string{"name": value.Name}
+		case "comment":
+			if opts.Comment == "" {
+				if field.Required {
+					return nil, fmt.Errorf("transition %q requires a comment", transition.Name)
+				}
+				continue
+			}
+			request.Update = map[string][]map[string]interface{}{
+				"comment": {{"add": map[string]interface{}{"body": ts.client.richText(ctx, opts.Comment)}}},
+			}
+		default:
+			if field.Required && !field.HasDefaultValue {
+				return nil, fmt.Errorf("transition %q requires field %s, which jit cannot fill in", transition.Name, field.Name)
+			}
+		}
+	}
+
+	return request, nil
+}
+
+// defaultResolutions are the resolutions used, in order of preference, when
+// a transition requires one and none was given. Anything else, such as
+// "Won't Do" or "Duplicate", has to be asked for.
+var defaultResolutions = []string{"Done", "Fixed"}
+
+// pickAllowedValue returns the allowed value matching name. Without a name
+// it picks the first of defaultResolutions on offer, and otherwise fails
+// listing the allowed values.
+func pickAllowedValue(field JiraTransitionField, name string) (*JiraAllowedValue, error) {
+	if len(field.AllowedValues) == 0 {
+		if name == "" {
+			return nil, fmt.Errorf("field %s has no allowed values", field.Name)
+		}
+		return &JiraAllowedValue{Name: name}, nil
+	}
+
+	var names []string
+	for i := range field.AllowedValues {
+		names = append(names, field.AllowedValues[i].Name)
+	}
+
+	if name == "" {
+		for _, preferred := range defaultResolutions {
+			if value := findAllowedValue(field, preferred); value != nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%s is required; choose one with --resolution: %s", strings.ToLower(field.Name), strings.Join(names, ", "))
+	}
+
+	if value := findAllowedValue(field, name); value != nil {
+		return value, nil
+	}
+	return nil, fmt.Errorf("invalid %s %q; allowed: %s", strings.ToLower(field.Name), name, strings.Join(names, ", "))
+}
+
+// findAllowedValue returns the allowed value whose name or value is name
+func findAllowedValue(field JiraTransitionField, name string) *JiraAllowedValue {
+	for i := range field.AllowedValues {
+		value := &field.AllowedValues[i]
+		if strings.EqualFold(value.Name, name) || strings.EqualFold(value.Value, name) {
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func testTransitions() []JiraTransition {
+	return []JiraTransition{
+		{ID: "11", Name: "Start Progress", To: JiraStatus{Name: "Doing", StatusCategory: &JiraStatusCategory{Key: "indeterminate"}}},
+		{ID: "21", Name: "Send to review", To: JiraStatus{Name: "Code Review", StatusCategory: &JiraStatusCategory{Key: "indeterminate"}}},
+		{ID: "31", Name: "Close", To: JiraStatus{Name: "Done", StatusCategory: &JiraStatusCategory{Key: "done"}}, HasScreen: true,
+			Fields: map[string]JiraTransitionField{
+				"resolution": {Required: true, Name: "Resolution", AllowedValues: []JiraAllowedValue{{ID: "1", Name: "Fixed"}, {ID: "2", Name: "Won't Do"}}},
+			}},
+	}
+}
+
+func TestFindTransition(t *testing.T) {
+	config := &types.JiraConfig{
+		URL:     "https://test.atlassian.net",
+		Project: "TEST",
+		StatusAliases: map[string]map[string]string{
+			"TEST": {"review": "Code Review"},
+			"*":    {"wip": "Doing"},
+		},
+	}
+	service := NewTicketService(NewClient(config))
+
+	tests := []struct {
+		input     string
+		project   string
+		expected  string
+		shouldErr bool
+	}{
+		{"done", "TEST", "31", false},
+		{"review", "TEST", "21", false},         // project alias
+		{"wip", "OTHER", "11", false},           // wildcard alias
+		{"progress", "TEST", "11", false},       // built-in alias matched by status category
+		{"send to review", "TEST", "21", false}, // transition name
+		{"Code Review", "TEST", "21", false},    // status name
+		{"review", "OTHER", "", true},           // "In Review" does not exist
+		{"blocked", "TEST", "", true},
+	}
+
+	for _, tt := range tests {
+		transition, err := service.FindTransition(testTransitions(), tt.project, tt.input)
+		if tt.shouldErr {
+			if err == nil {
+				t.Errorf("FindTransition(%q) should have returned error", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("FindTransition(%q) returned unexpected error: %v", tt.input, err)
+			continue
+		}
+		if transition.ID != tt.expected {
+			t.Errorf("FindTransition(%q) = %s, expected %s", tt.input, transition.ID, tt.expected)
+		}
+	}
+}
+
+func TestBuildTransitionRequest(t *testing.T) {
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: "https://test.atlassian.net"}))
+	closeTransition := testTransitions()[2]
+	ctx := context.Background()
+
+	request, err := service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{})
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	if resolution, _ := request.Fields["resolution"].(map[string]string); resolution["name"] != "Fixed" {
+		t.Errorf("Expected default resolution Fixed, got %v", request.Fields["resolution"])
+	}
+
+	request, err = service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{Resolution: "won't do"})
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	if resolution, _ := request.Fields["resolution"].(map[string]string); resolution["name"] != "Won't Do" {
+		t.Errorf("Expected resolution Won't Do, got %v", request.Fields["resolution"])
+	}
+
+	if _, err := service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{Resolution: "Duplicate"}); err == nil {
+		t.Error("Expected error for a resolution that is not allowed")
+	}
+
+	// Without --resolution, Done or Fixed is preferred wherever it is listed
+	closeTransition.Fields["resolution"] = JiraTransitionField{Required: true, Name: "Resolution", AllowedValues: []JiraAllowedValue{{ID: "2", Name: "Won't Do"}, {ID: "3", Name: "Duplicate"}, {ID: "4", Name: "Done"}}}
+	request, err = service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{})
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	if resolution, _ := request.Fields["resolution"].(map[string]string); resolution["name"] != "Done" {
+		t.Errorf("Expected resolution Done, got %v", request.Fields["resolution"])
+	}
+
+	// Without either, jit asks rather than picking one
+	closeTransition.Fields["resolution"] = JiraTransitionField{Required: true, Name: "Resolution", AllowedValues: []JiraAllowedValue{{ID: "2", Name: "Won't Do"}, {ID: "3", Name: "Duplicate"}}}
+	_, err = service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{})
+	if err == nil || !strings.Contains(err.Error(), "--resolution") || !strings.Contains(err.Error(), "Won't Do, Duplicate") {
+		t.Errorf("Expected an error listing the resolutions, got %v", err)
+	}
+	closeTransition.Fields["resolution"] = testTransitions()[2].Fields["resolution"]
+
+	closeTransition.Fields["customfield_10500"] = JiraTransitionField{Required: true, Name: "Root Cause"}
+	if _, err := service.buildTransitionRequest(ctx, &closeTransition, &TransitionOptions{}); err == nil || !strings.Contains(err.Error(), "Root Cause") {
+		t.Errorf("Expected error naming the required field, got %v", err)
+	}
+}
+
+func TestTransitionTicket(t *testing.T) {
+	var posted JiraTransitionRequest
+	commented := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/issue/TEST-1/transitions":
+			if r.URL.Query().Get("expand") != "transitions.fields" {
+				t.Errorf("Expected transition fields to be expanded")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(JiraTransitionsResponse{Transitions: testTransitions()})
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue/TEST-1/transitions":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("Failed to decode transition request: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue/TEST-1/comment":
+			commented = true
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"1"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+
+	transition, err := service.TransitionTicket(context.Background(), "TEST-1", "TEST", "done", &TransitionOptions{Comment: "Shipped"})
+	if err != nil {
+		t.Fatalf("Failed to transition ticket: %v", err)
+	}
+
+	if transition.To.Name != "Done" {
+		t.Errorf("Expected transition to Done, got %s", transition.To.Name)
+	}
+	if posted.Transition.ID != "31" {
+		t.Errorf("Expected transition 31 to be posted, got %s", posted.Transition.ID)
+	}
+	if posted.Fields["resolution"] == nil {
+		t.Error("Expected resolution to be sent with the transition")
+	}
+	if !commented {
+		t.Error("Expected comment to be added separately when not on the transition screen")
+	}
+}
+
+func TestTransitionTicketCommentFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/rest/api/3/issue/TEST-1/transitions":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(JiraTransitionsResponse{Transitions: testTransitions()})
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue/TEST-1/transitions":
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST" && r.URL.Path == "/rest/api/3/issue/TEST-1/comment":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorMessages":["Comments are disabled"]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL, Project: "TEST"}))
+
+	// The status changed, so the transition comes back with the comment's error
+	transition, err := service.TransitionTicket(context.Background(), "TEST-1", "TEST", "done", &TransitionOptions{Comment: "Shipped"})
+	var commentErr *CommentError
+	if !errors.As(err, &commentErr) {
+		t.Fatalf("Expected a CommentError, got %v", err)
+	}
+	if transition == nil || transition.To.Name != "Done" {
+		t.Errorf("Expected the transition to Done alongside the error, got %+v", transition)
+	}
+	if !strings.Contains(commentErr.Error(), "Comments are disabled") {
+		t.Errorf("Expected Jira's reason in the error, got %v", commentErr)
+	}
+}
@@ -0,0 +1,428 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/network"
+)
+
+// Retry defaults used when the config does not override them
+const (
+	DefaultMaxRetries    = 4
+	DefaultBaseDelay     = 500 * time.Millisecond
+	DefaultMaxDelay      = 30 * time.Second
+	DefaultMaxRetryAfter = 2 * time.Minute
+
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned when the circuit breaker is rejecting requests
+var ErrCircuitOpen = errors.New("Jira appears to be unavailable (circuit breaker open), try again shortly")
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	MaxRetries     int           // Retries after the first attempt
+	BaseDelay      time.Duration // Backoff for the first retry, doubled for each further retry
+	MaxDelay       time.Duration // Upper bound on computed backoff
+	MaxRetryAfter  time.Duration // Longest server-requested wait that is honoured
+	AttemptTimeout time.Duration // Limit on each attempt, reading the response included; 0 for none
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     DefaultMaxRetries,
+		BaseDelay:      DefaultBaseDelay,
+		MaxDelay:       DefaultMaxDelay,
+		MaxRetryAfter:  DefaultMaxRetryAfter,
+		AttemptTimeout: network.DefaultRequestTimeout,
+	}
+}
+
+// RetryTransport is an http.RoundTripper that retries rate-limited and
+// transiently failing requests with capped exponential backoff and jitter.
+// When Jira rate-limits one request, new requests through the same
+// transport hold off for the same wait, so concurrent callers back off
+// together instead of spending their retries.
+type RetryTransport struct {
+	Base    http.RoundTripper
+	Policy  RetryPolicy
+	Breaker *CircuitBreaker // Optional
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+
+	// sleep waits for d or until ctx is done; replaced in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryTransport wraps base with retries and an optional circuit breaker
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy, breaker *CircuitBreaker) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{
+		Base:    base,
+		Policy:  policy,
+		Breaker: breaker,
+		sleep:   sleepContext,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	// Requests must be replayable to be retried
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer request body: %v", err)
+		}
+		req = req.Clone(ctx)
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+
+	if wait := t.pause(); wait > 0 {
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if t.Breaker != nil {
+			if err := t.Breaker.Allow(); err != nil {
+				return nil, err
+			}
+		}
+
+		// A timed out attempt is retried like any other transient failure,
+		// while the caller's context still bounds the request as a whole
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if t.Policy.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, t.Policy.AttemptTimeout)
+		}
+
+		attemptReq := req.WithContext(attemptCtx)
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("failed to rewind request body: %v", err)
+			}
+			attemptReq = req.Clone(attemptCtx)
+			attemptReq.Body = body
+		}
+
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if err != nil {
+			cancel()
+		} else {
+			// The deadline covers reading the body, so it ends on Close
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		}
+		if t.Breaker != nil {
+			switch {
+			case ctx.Err() != nil:
+				// A cancelled request says nothing about Jira
+				t.Breaker.Abandon()
+			case isOutage(resp, err):
+				t.Breaker.Failure()
+			default:
+				t.Breaker.Success()
+			}
+		}
+
+		transient := t.isTransient(req, resp, err)
+		if !transient || attempt >= t.Policy.MaxRetries {
+			return resp, err
+		}
+
+		delay, ok := t.retryDelay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			t.pauseFor(delay)
+		}
+
+		// Discard the failed response so the connection can be reused
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// cancelOnClose releases an attempt's context once its response is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// pause returns how long new requests should wait for a rate limit to pass
+func (t *RetryTransport) pause() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return nonNegative(time.Until(t.pausedUntil))
+}
+
+// pauseFor holds off new requests for d, unless they are already held
+// off for longer
+func (t *RetryTransport) pauseFor(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// isTransient reports whether a request outcome is worth retrying
+func (t *RetryTransport) isTransient(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// Non-idempotent requests may already have been applied
+		return isIdempotent(req.Method) && isTransientError(req.Context(), err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The request was rejected before being processed
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	default:
+		return false
+	}
+}
+
+// isOutage reports whether an outcome suggests Jira itself is unavailable
+func isOutage(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt. It reports
+// false when the server asks for a longer wait than the policy allows.
+func (t *RetryTransport) retryDelay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := serverRetryDelay(resp.Header, time.Now()); ok {
+			if t.Policy.MaxRetryAfter > 0 && wait > t.Policy.MaxRetryAfter {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+
+	return backoff(t.Policy, attempt), true
+}
+
+// serverRetryDelay reads Retry-After, then X-RateLimit-Reset once the
+// remaining quota is exhausted
+func serverRetryDelay(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return nonNegative(at.Sub(now)), true
+		}
+	}
+
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if value := header.Get("X-RateLimit-Reset"); value != "" {
+			if at, err := time.Parse(time.RFC3339, value); err == nil {
+				return nonNegative(at.Sub(now)), true
+			}
+			if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return nonNegative(time.Unix(epoch, 0).Sub(now)), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// backoff returns the capped exponential delay for an attempt with equal jitter
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 0; i < attempt && delay < policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isTransientError reports whether a transport error is likely to succeed on retry
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// isIdempotent reports whether a method can safely be repeated
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// sleepContext waits for d, returning early with the context error if ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// CircuitBreaker stops sending requests after repeated failures so bulk
+// operations fail fast while Jira is down. After the cooldown a single trial
+// request is let through; its outcome closes or reopens the breaker.
+type CircuitBreaker struct {
+	Threshold int           // Consecutive failures before opening
+	Cooldown  time.Duration // How long to stay open before a trial request
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a circuit breaker
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrCircuitOpen while the breaker is open
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.Threshold <= 0 || cb.failures < cb.Threshold {
+		return nil
+	}
+
+	if cb.trial || cb.now().Sub(cb.openedAt) < cb.Cooldown {
+		return ErrCircuitOpen
+	}
+
+	// Half-open: let one request through to probe
+	cb.trial = true
+	return nil
+}
+
+// Success records a successful request and closes the breaker
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = 0
+	cb.trial = false
+}
+
+// Failure records a failed request, opening the breaker at the threshold
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures++
+	if cb.trial || cb.failures == cb.Threshold {
+		cb.openedAt = cb.now()
+	}
+	cb.trial = false
+}
+
+// Abandon records that an allowed request ended without an outcome, such
+// as when its context was cancelled, so a later request can make the trial
+func (cb *CircuitBreaker) Abandon() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.trial = false
+}
+
+// Open reports whether the breaker is currently rejecting requests
+func (cb *CircuitBreaker) Open() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return cb.Threshold > 0 && cb.failures >= cb.Threshold && (cb.trial || cb.now().Sub(cb.openedAt) < cb.Cooldown)
+}
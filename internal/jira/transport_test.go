@@ -0,0 +1,347 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestTransport returns a retry transport that records delays instead of sleeping
+func newTestTransport(policy RetryPolicy, breaker *CircuitBreaker) (*RetryTransport, *[]time.Duration) {
+	var delays []time.Duration
+	transport := NewRetryTransport(http.DefaultTransport, policy, breaker)
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+	return transport, &delays
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"summary":"retry me"}` {
+			t.Errorf("Attempt %d received body %q", attempts, body)
+		}
+		if attempts < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	transport, delays := newTestTransport(DefaultRetryPolicy(), nil)
+	client := &http.Client{Transport: transport}
+
+	// A plain io.Reader has no GetBody, so the transport must buffer it
+	req, _ := http.NewRequest("POST", server.URL, io.MultiReader(strings.NewReader(`{"summary":"retry me"}`)))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(*delays) != 2 || (*delays)[0] != 2*time.Second {
+		t.Errorf("Expected two Retry-After delays of 2s, got %v", *delays)
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	transport, delays := newTestTransport(policy, nil)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected final 503 to be returned, got %d", resp.StatusCode)
+	}
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", attempts)
+	}
+
+	// Backoff doubles and is capped, with jitter in the upper half
+	maxima := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for i, delay := range *delays {
+		if delay < maxima[i]/2 || delay > maxima[i] {
+			t.Errorf("Delay %d = %v, expected between %v and %v", i, delay, maxima[i]/2, maxima[i])
+		}
+	}
+}
+
+func TestRetryTransportSkipsNonIdempotentGatewayErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	transport, _ := newTestTransport(DefaultRetryPolicy(), nil)
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("Expected POST not to be retried on 502, got %d attempts", attempts)
+	}
+}
+
+func TestRetryTransportPausesOtherRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport, delays := newTestTransport(DefaultRetryPolicy(), nil)
+	client := &http.Client{Transport: transport}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Request %d failed: %v", i+1, err)
+		}
+		resp.Body.Close()
+	}
+
+	// The first request waits out its Retry-After; the second, sent while
+	// the rate limit still holds, waits for the rest of it before sending
+	if len(*delays) != 2 || (*delays)[0] != 30*time.Second {
+		t.Fatalf("Expected the Retry-After and a shared pause, got %v", *delays)
+	}
+	if pause := (*delays)[1]; pause <= 25*time.Second || pause > 30*time.Second {
+		t.Errorf("Expected the second request to wait out the rate limit, waited %v", pause)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransportHonoursContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := &http.Client{Transport: NewRetryTransport(http.DefaultTransport, DefaultRetryPolicy(), nil)}
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+
+	start := time.Now()
+	_, err := client.Do(req)
+	if err == nil {
+		t.Fatal("Expected cancelled request to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected sleep to stop on cancellation, took %v", elapsed)
+	}
+}
+
+func TestRetryTransportTimesOutEachAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stall := func() {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+		switch {
+		case r.URL.Path == "/body":
+			// Send the headers, then stall on the body
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			stall()
+		case attempts.Add(1) == 1:
+			stall()
+		default:
+			io.WriteString(w, "ok")
+		}
+	}))
+	defer server.Close()
+
+	policy := RetryPolicy{MaxRetries: 1, AttemptTimeout: 100 * time.Millisecond}
+	transport, _ := newTestTransport(policy, nil)
+	client := &http.Client{Transport: transport}
+
+	// An attempt that stalls before responding is retried
+	resp, err := client.Get(server.URL + "/headers")
+	if err != nil {
+		t.Fatalf("Expected the timed out attempt to be retried: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" || attempts.Load() != 2 {
+		t.Errorf("Expected ok after 2 attempts, got %q after %d", body, attempts.Load())
+	}
+
+	// The deadline also covers reading the body
+	resp, err = client.Get(server.URL + "/body")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	start := time.Now()
+	_, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err == nil {
+		t.Fatal("Expected the stalled body to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the attempt to stop at its deadline, took %v", elapsed)
+	}
+}
+
+func TestServerRetryDelay(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected time.Duration
+		ok       bool
+	}{
+		{"retry-after seconds", http.Header{"Retry-After": {"7"}}, 7 * time.Second, true},
+		{"retry-after date", http.Header{"Retry-After": {now.Add(3 * time.Second).Format(http.TimeFormat)}}, 3 * time.Second, true},
+		{"rate limit reset", http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {now.Add(10 * time.Second).Format(time.RFC3339)}}, 10 * time.Second, true},
+		{"quota left", http.Header{"X-Ratelimit-Remaining": {"5"}, "X-Ratelimit-Reset": {now.Add(10 * time.Second).Format(time.RFC3339)}}, 0, false},
+		{"no headers", http.Header{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		delay, ok := serverRetryDelay(tt.header, now)
+		if ok != tt.ok || delay != tt.expected {
+			t.Errorf("%s: got (%v, %v), expected (%v, %v)", tt.name, delay, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	policy := RetryPolicy{MaxRetries: 10, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	transport, _ := newTestTransport(policy, breaker)
+	client := &http.Client{Transport: transport}
+
+	if _, err := client.Get(server.URL); err == nil || !strings.Contains(err.Error(), "circuit breaker") {
+		t.Fatalf("Expected circuit breaker error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected breaker to open after 3 attempts, got %d", attempts)
+	}
+
+	// Further requests are rejected without reaching the server
+	client.Get(server.URL)
+	if attempts != 3 {
+		t.Errorf("Expected open breaker to reject requests, got %d attempts", attempts)
+	}
+
+	// After the cooldown a single trial request is allowed through
+	now = now.Add(2 * time.Minute)
+	client.Get(server.URL)
+	if attempts != 4 {
+		t.Errorf("Expected one trial request after cooldown, got %d attempts", attempts)
+	}
+	if !breaker.Open() {
+		t.Error("Expected failed trial to reopen the breaker")
+	}
+
+	breaker.Success()
+	if breaker.Open() {
+		t.Error("Expected success to close the breaker")
+	}
+}
+
+func TestCircuitBreakerRecoversFromCancelledTrial(t *testing.T) {
+	cancelTrial := make(chan struct{})
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 2 {
+			// The trial request's client goes away before Jira answers
+			close(cancelTrial)
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(1, time.Minute)
+	breaker.now = func() time.Time { return now }
+	breaker.Failure()
+
+	transport, _ := newTestTransport(RetryPolicy{}, breaker)
+	client := &http.Client{Transport: transport}
+
+	now = now.Add(2 * time.Minute)
+	attempts = 1
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-cancelTrial
+		cancel()
+	}()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("Expected the cancelled trial to fail")
+	}
+
+	// The next request makes the trial instead of being rejected forever
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected a new trial after the cancelled one, got %v", err)
+	}
+	resp.Body.Close()
+	if breaker.Open() {
+		t.Error("Expected the successful trial to close the breaker")
+	}
+}
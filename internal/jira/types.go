@@ -1,6 +1,12 @@
 package jira
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 // JiraIssue represents a Jira issue from the API
 type JiraIssue struct {
@@ -13,24 +19,67 @@ type JiraIssue struct {
 // JiraIssueFields contains the main issue data
 type JiraIssueFields struct {
 	Summary      string                 `json:"summary"`
-	Description  string                 `json:"description"`
+	Description  *RichText              `json:"description"`
 	Status       JiraStatus             `json:"status"`
 	Priority     JiraPriority           `json:"priority"`
 	IssueType    JiraIssueType          `json:"issuetype"`
 	Project      JiraProject            `json:"project"`
 	Assignee     *JiraUser              `json:"assignee"`
 	Reporter     *JiraUser              `json:"reporter"`
-	Created      time.Time              `json:"created"`
-	Updated      time.Time              `json:"updated"`
+	Created      JiraTime               `json:"created"`
+	Updated      JiraTime               `json:"updated"`
 	Labels       []string               `json:"labels"`
+	IssueLinks   []JiraIssueLink        `json:"issuelinks,omitempty"`
+	Parent       *JiraLinkedIssue       `json:"parent,omitempty"`
+	Subtasks     []JiraLinkedIssue      `json:"subtasks,omitempty"`
+	Attachments  []JiraAttachment       `json:"attachment,omitempty"`
+	FixVersions  []JiraVersion          `json:"fixVersions,omitempty"`
+	Components   []JiraComponent        `json:"components,omitempty"`
+	Sprint       *JiraSprint            `json:"sprint,omitempty"` // Only set by the Agile API
 	CustomFields map[string]interface{} `json:"-"`
 }
 
+// UnmarshalJSON decodes the issue fields, collecting custom fields by ID
+func (f *JiraIssueFields) UnmarshalJSON(data []byte) error {
+	type plain JiraIssueFields
+	if err := json.Unmarshal(data, (*plain)(f)); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for name, value := range raw {
+		if !strings.HasPrefix(name, "customfield_") || string(value) == "null" {
+			continue
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		if f.CustomFields == nil {
+			f.CustomFields = make(map[string]interface{})
+		}
+		f.CustomFields[name] = decoded
+	}
+
+	return nil
+}
+
 // JiraStatus represents the issue status
 type JiraStatus struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID             string              `json:"id"`
+	Name           string              `json:"name"`
+	Description    string              `json:"description"`
+	StatusCategory *JiraStatusCategory `json:"statusCategory,omitempty"`
+}
+
+// JiraStatusCategory groups statuses into new, indeterminate and done
+type JiraStatusCategory struct {
+	ID   int    `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
 }
 
 // JiraPriority represents the issue priority
@@ -42,22 +91,35 @@ type JiraPriority struct {
 
 // JiraIssueType represents the issue type
 type JiraIssueType struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	IconURL     string `json:"iconUrl"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	IconURL        string `json:"iconUrl"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
 }
 
-// JiraProject represents the project
+// JiraProject represents the project. Cloud reports Style as "classic" for
+// company-managed and "next-gen" for team-managed projects.
 type JiraProject struct {
-	ID   string `json:"id"`
-	Key  string `json:"key"`
-	Name string `json:"name"`
+	ID         string `json:"id"`
+	Key        string `json:"key"`
+	Name       string `json:"name"`
+	Style      string `json:"style,omitempty"`
+	Simplified bool   `json:"simplified,omitempty"`
 }
 
-// JiraUser represents a Jira user
+// IsTeamManaged reports whether the project is team-managed (next-gen)
+func (p *JiraProject) IsTeamManaged() bool {
+	return p.Style == ProjectStyleNextGen || p.Simplified
+}
+
+// JiraUser represents a Jira user. Cloud identifies users by AccountID,
+// Server and Data Center by Name.
 type JiraUser struct {
-	AccountID   string `json:"accountId"`
+	AccountID   string `json:"accountId,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Key         string `json:"key,omitempty"`
 	DisplayName string `json:"displayName"`
 	Email       string `json:"emailAddress"`
 	Active      bool   `json:"active"`
@@ -65,14 +127,25 @@ type JiraUser struct {
 
 // JiraChangelog contains the issue changelog
 type JiraChangelog struct {
-	Histories []JiraChangelogHistory `json:"histories"`
+	StartAt    int                    `json:"startAt"`
+	MaxResults int                    `json:"maxResults"`
+	Total      int                    `json:"total"`
+	Histories  []JiraChangelogHistory `json:"histories"`
+}
+
+// JiraChangelogPage represents a page of /issue/{key}/changelog
+type JiraChangelogPage struct {
+	StartAt int                    `json:"startAt"`
+	Total   int                    `json:"total"`
+	IsLast  bool                   `json:"isLast"`
+	Values  []JiraChangelogHistory `json:"values"`
 }
 
 // JiraChangelogHistory represents a changelog entry
 type JiraChangelogHistory struct {
 	ID      string              `json:"id"`
 	Author  JiraUser            `json:"author"`
-	Created time.Time           `json:"created"`
+	Created JiraTime            `json:"created"`
 	Items   []JiraChangelogItem `json:"items"`
 }
 
@@ -81,7 +154,9 @@ type JiraChangelogItem struct {
 	Field     string `json:"field"`
 	FieldType string `json:"fieldtype"`
 	FieldID   string `json:"fieldId"`
+	FromID    string `json:"from"`
 	From      string `json:"fromString"`
+	ToID      string `json:"to"`
 	To        string `json:"toString"`
 }
 
@@ -94,12 +169,42 @@ type JiraCreateIssueRequest struct {
 type JiraCreateIssueFields struct {
 	Project     JiraProjectReference `json:"project"`
 	Summary     string               `json:"summary"`
-	Description string               `json:"description"`
+	Description *RichText            `json:"description,omitempty"`
 	IssueType   JiraIssueTypeRef     `json:"issuetype"`
 	Priority    *JiraPriorityRef     `json:"priority,omitempty"`
 	Labels      []string             `json:"labels,omitempty"`
 	Assignee    *JiraUserRef         `json:"assignee,omitempty"`
 	Parent      *JiraParentRef       `json:"parent,omitempty"`
+	FixVersions []JiraNameRef        `json:"fixVersions,omitempty"`
+	Components  []JiraNameRef        `json:"components,omitempty"`
+
+	// Custom field values keyed by field ID, sent alongside the fields above
+	CustomFields map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON encodes the fields with the custom fields merged in
+func (f JiraCreateIssueFields) MarshalJSON() ([]byte, error) {
+	type plain JiraCreateIssueFields
+	data, err := json.Marshal(plain(f))
+	if err != nil || len(f.CustomFields) == 0 {
+		return data, err
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for id, value := range f.CustomFields {
+		fields[id] = value
+	}
+	return json.Marshal(fields)
+}
+
+// JiraUpdateIssueRequest represents the request to edit an issue. Fields
+// replaces values outright; a nil value clears the field.
+type JiraUpdateIssueRequest struct {
+	Fields map[string]interface{}              `json:"fields,omitempty"`
+	Update map[string][]map[string]interface{} `json:"update,omitempty"`
 }
 
 // JiraProjectReference for creating issues
@@ -114,12 +219,14 @@ type JiraIssueTypeRef struct {
 
 // JiraPriorityRef for creating issues
 type JiraPriorityRef struct {
-	ID string `json:"id"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"` // When the project metadata is unavailable
 }
 
 // JiraUserRef for creating issues
 type JiraUserRef struct {
-	AccountID string `json:"accountId"`
+	AccountID string `json:"accountId,omitempty"`
+	Name      string `json:"name,omitempty"` // Server and Data Center
 }
 
 // JiraParentRef for creating subtasks
@@ -127,6 +234,49 @@ type JiraParentRef struct {
 	Key string `json:"key"`
 }
 
+// JiraNameRef refers to a version or component by name
+type JiraNameRef struct {
+	Name string `json:"name"`
+}
+
+// JiraVersion represents a project version
+type JiraVersion struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+// JiraComponent represents a project component
+type JiraComponent struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// JiraBulkCreateRequest creates several issues in one request
+type JiraBulkCreateRequest struct {
+	IssueUpdates []JiraCreateIssueRequest `json:"issueUpdates"`
+}
+
+// JiraBulkCreateResponse lists the issues a bulk create made, in request
+// order, and the requests that failed
+type JiraBulkCreateResponse struct {
+	Issues []JiraCreateIssueResponse `json:"issues"`
+	Errors []JiraBulkCreateError     `json:"errors"`
+}
+
+// JiraBulkCreateError describes why one request in a bulk create failed.
+// FailedElementNumber is the request's index.
+type JiraBulkCreateError struct {
+	Status              int       `json:"status"`
+	ElementErrors       JiraError `json:"elementErrors"`
+	FailedElementNumber int       `json:"failedElementNumber"`
+}
+
 // JiraCreateIssueResponse represents the response from creating an issue
 type JiraCreateIssueResponse struct {
 	ID   string `json:"id"`
@@ -138,23 +288,116 @@ type JiraCreateIssueResponse struct {
 type JiraComment struct {
 	ID           string    `json:"id"`
 	Author       JiraUser  `json:"author"`
-	Body         string    `json:"body"`
-	Created      time.Time `json:"created"`
-	Updated      time.Time `json:"updated"`
+	Body         *RichText `json:"body"`
+	Created      JiraTime  `json:"created"`
+	Updated      JiraTime  `json:"updated"`
 	UpdateAuthor JiraUser  `json:"updateAuthor"`
 }
 
-// JiraCreateCommentRequest represents the request to create a comment
+// JiraCreateCommentRequest represents the request to create or update a comment
 type JiraCreateCommentRequest struct {
-	Body string `json:"body"`
+	Body *RichText `json:"body"`
+}
+
+// JiraCommentsResponse represents a page of an issue's comments
+type JiraCommentsResponse struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Comments   []JiraComment `json:"comments"`
 }
 
 // JiraSearchResponse represents the response from a JQL search
 type JiraSearchResponse struct {
-	StartAt    int         `json:"startAt"`
-	MaxResults int         `json:"maxResults"`
-	Total      int         `json:"total"`
-	Issues     []JiraIssue `json:"issues"`
+	StartAt       int         `json:"startAt"`
+	MaxResults    int         `json:"maxResults"`
+	Total         int         `json:"total"`
+	Issues        []JiraIssue `json:"issues"`
+	NextPageToken string      `json:"nextPageToken,omitempty"`
+	IsLast        bool        `json:"isLast,omitempty"`
+}
+
+// JiraField represents a system or custom field definition
+type JiraField struct {
+	ID     string           `json:"id"`
+	Key    string           `json:"key"`
+	Name   string           `json:"name"`
+	Custom bool             `json:"custom"`
+	Schema *JiraFieldSchema `json:"schema,omitempty"`
+}
+
+// JiraFieldSchema describes the value type of a field
+type JiraFieldSchema struct {
+	Type     string `json:"type"`
+	Items    string `json:"items,omitempty"`
+	System   string `json:"system,omitempty"`
+	Custom   string `json:"custom,omitempty"`
+	CustomID int    `json:"customId,omitempty"`
+}
+
+// JiraCreateMetaIssueTypesResponse represents a page of issue types available for creation in a project
+type JiraCreateMetaIssueTypesResponse struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	IssueTypes []JiraIssueType `json:"issueTypes"`
+	Values     []JiraIssueType `json:"values"` // Older Cloud releases name the list "values"
+}
+
+// JiraProjectDetails represents a project with its issue types
+type JiraProjectDetails struct {
+	JiraProject
+	IssueTypes []JiraIssueType `json:"issueTypes"`
+}
+
+// JiraTransition represents a workflow transition available on an issue
+type JiraTransition struct {
+	ID        string                         `json:"id"`
+	Name      string                         `json:"name"`
+	To        JiraStatus                     `json:"to"`
+	HasScreen bool                           `json:"hasScreen"`
+	Fields    map[string]JiraTransitionField `json:"fields,omitempty"`
+}
+
+// JiraTransitionField describes a field on a transition screen
+type JiraTransitionField struct {
+	Required        bool               `json:"required"`
+	Name            string             `json:"name"`
+	Schema          *JiraFieldSchema   `json:"schema,omitempty"`
+	HasDefaultValue bool               `json:"hasDefaultValue"`
+	AllowedValues   []JiraAllowedValue `json:"allowedValues,omitempty"`
+}
+
+// JiraAllowedValue is an option for a select-like field
+type JiraAllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// JiraTransitionsResponse represents the transitions available on an issue
+type JiraTransitionsResponse struct {
+	Transitions []JiraTransition `json:"transitions"`
+}
+
+// JiraTransitionRequest represents the request to transition an issue
+type JiraTransitionRequest struct {
+	Transition JiraTransitionRef                   `json:"transition"`
+	Fields     map[string]interface{}              `json:"fields,omitempty"`
+	Update     map[string][]map[string]interface{} `json:"update,omitempty"`
+}
+
+// JiraTransitionRef identifies a transition
+type JiraTransitionRef struct {
+	ID string `json:"id"`
+}
+
+// JiraServerInfo describes the Jira deployment
+type JiraServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	DeploymentType string `json:"deploymentType"` // Cloud, Server or DataCenter
+	ServerTitle    string `json:"serverTitle"`
 }
 
 // JiraError represents a Jira API error
@@ -163,7 +406,209 @@ type JiraError struct {
 	Errors        map[string]string `json:"errors"`
 }
 
+// message joins the error messages, or failing those the field errors
+func (e *JiraError) message() string {
+	if len(e.ErrorMessages) > 0 {
+		return strings.Join(e.ErrorMessages, "; ")
+	}
+
+	var errors []string
+	for field, message := range e.Errors {
+		errors = append(errors, fmt.Sprintf("%s: %s", field, message))
+	}
+	sort.Strings(errors)
+	return strings.Join(errors, "; ")
+}
+
 // JiraErrorResponse represents the full error response
 type JiraErrorResponse struct {
 	ErrorCollection JiraError `json:"errorCollection"`
 }
+
+// JiraIssueLinkType describes a kind of issue link and how it reads in each direction
+type JiraIssueLinkType struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward,omitempty"`
+	Outward string `json:"outward,omitempty"`
+}
+
+// JiraIssueLinkTypesResponse represents the response listing issue link types
+type JiraIssueLinkTypesResponse struct {
+	IssueLinkTypes []JiraIssueLinkType `json:"issueLinkTypes"`
+}
+
+// JiraIssueLink is a link as seen from one of its issues; only the other
+// issue is set, under the direction it sits in
+type JiraIssueLink struct {
+	ID           string            `json:"id"`
+	Type         JiraIssueLinkType `json:"type"`
+	InwardIssue  *JiraLinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *JiraLinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// JiraLinkedIssue is the summary of an issue at the other end of a link
+type JiraLinkedIssue struct {
+	ID     string `json:"id,omitempty"`
+	Key    string `json:"key"`
+	Fields *struct {
+		Summary string     `json:"summary"`
+		Status  JiraStatus `json:"status"`
+	} `json:"fields,omitempty"`
+}
+
+// JiraCreateIssueLinkRequest links two issues so that InwardIssue reads as
+// the subject of the type's outward phrase, e.g. inward "blocks" outward
+type JiraCreateIssueLinkRequest struct {
+	Type         JiraIssueLinkType `json:"type"`
+	InwardIssue  JiraLinkedIssue   `json:"inwardIssue"`
+	OutwardIssue JiraLinkedIssue   `json:"outwardIssue"`
+}
+
+// JiraRemoteLink is a link from an issue to something outside Jira, such as
+// a pull request
+type JiraRemoteLink struct {
+	ID           int                  `json:"id,omitempty"`
+	GlobalID     string               `json:"globalId,omitempty"`
+	Relationship string               `json:"relationship,omitempty"`
+	Object       JiraRemoteLinkObject `json:"object"`
+}
+
+// JiraRemoteLinkObject describes the target of a remote link
+type JiraRemoteLinkObject struct {
+	URL     string              `json:"url"`
+	Title   string              `json:"title"`
+	Summary string              `json:"summary,omitempty"`
+	Icon    *JiraRemoteLinkIcon `json:"icon,omitempty"`
+}
+
+// JiraRemoteLinkIcon is the icon shown next to a remote link
+type JiraRemoteLinkIcon struct {
+	URL16x16 string `json:"url16x16,omitempty"`
+	Title    string `json:"title,omitempty"`
+}
+
+// JiraCreateRemoteLinkResponse represents the response to creating or
+// updating a remote link
+type JiraCreateRemoteLinkResponse struct {
+	ID   int    `json:"id"`
+	Self string `json:"self"`
+}
+
+// JiraTimeFormat is the timestamp layout Jira uses for worklogs
+const JiraTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+// JiraTime is a timestamp in Jira's format, which lacks the colon RFC 3339
+// requires in the zone offset
+type JiraTime struct {
+	time.Time
+}
+
+// MarshalJSON writes the time in Jira's format
+func (t JiraTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Format(JiraTimeFormat))
+}
+
+// UnmarshalJSON accepts Jira's format as well as RFC 3339
+func (t *JiraTime) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.Parse(JiraTimeFormat, value)
+	if err != nil {
+		if parsed, err = time.Parse(time.RFC3339, value); err != nil {
+			return fmt.Errorf("invalid Jira time %q", value)
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+// JiraWorklog represents time logged on an issue
+type JiraWorklog struct {
+	ID               string    `json:"id"`
+	Author           JiraUser  `json:"author"`
+	Comment          *RichText `json:"comment,omitempty"`
+	Started          JiraTime  `json:"started"`
+	TimeSpent        string    `json:"timeSpent"`
+	TimeSpentSeconds int       `json:"timeSpentSeconds"`
+}
+
+// JiraWorklogsResponse represents a page of an issue's worklogs
+type JiraWorklogsResponse struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Worklogs   []JiraWorklog `json:"worklogs"`
+}
+
+// JiraAddWorklogRequest represents the request to log time on an issue
+type JiraAddWorklogRequest struct {
+	Comment          *RichText `json:"comment,omitempty"`
+	Started          JiraTime  `json:"started"`
+	TimeSpentSeconds int       `json:"timeSpentSeconds"`
+}
+
+// JiraAttachment represents a file attached to an issue
+type JiraAttachment struct {
+	ID        string   `json:"id"`
+	Filename  string   `json:"filename"`
+	Author    JiraUser `json:"author"`
+	Created   JiraTime `json:"created"`
+	Size      int64    `json:"size"`
+	MimeType  string   `json:"mimeType"`
+	Content   string   `json:"content"` // Download URL
+	Thumbnail string   `json:"thumbnail,omitempty"`
+}
+
+// JiraBoard represents a Jira Software board
+type JiraBoard struct {
+	ID       int                `json:"id"`
+	Name     string             `json:"name"`
+	Type     string             `json:"type"`
+	Location *JiraBoardLocation `json:"location,omitempty"`
+}
+
+// JiraBoardLocation is the project a board belongs to
+type JiraBoardLocation struct {
+	ProjectKey string `json:"projectKey"`
+}
+
+// JiraBoardsResponse represents a page of boards
+type JiraBoardsResponse struct {
+	StartAt    int         `json:"startAt"`
+	MaxResults int         `json:"maxResults"`
+	IsLast     bool        `json:"isLast"`
+	Values     []JiraBoard `json:"values"`
+}
+
+// JiraSprint represents a sprint, as the Agile API and the sprint field return it
+type JiraSprint struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	State         string   `json:"state"`
+	OriginBoardID int      `json:"originBoardId,omitempty"`
+	BoardID       int      `json:"boardId,omitempty"` // The sprint field's name for OriginBoardID
+	Goal          string   `json:"goal,omitempty"`
+	StartDate     JiraTime `json:"startDate,omitempty"`
+	EndDate       JiraTime `json:"endDate,omitempty"`
+}
+
+// JiraSprintsResponse represents a page of a board's sprints
+type JiraSprintsResponse struct {
+	StartAt    int          `json:"startAt"`
+	MaxResults int          `json:"maxResults"`
+	IsLast     bool         `json:"isLast"`
+	Values     []JiraSprint `json:"values"`
+}
+
+// JiraMoveToSprintRequest represents the request to move issues into a sprint
+type JiraMoveToSprintRequest struct {
+	Issues []string `json:"issues"`
+}
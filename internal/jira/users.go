@@ -0,0 +1,335 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// usersCacheName is the cache entry holding the user directory
+const usersCacheName = "users"
+
+// userSearchLimit caps the users returned by a single search
+const userSearchLimit = 20
+
+// SearchUsers finds users whose name or email matches query
+func (c *Client) SearchUsers(ctx context.Context, query string) ([]JiraUser, error) {
+	// Server and Data Center search by username rather than free text
+	param := "query"
+	if c.Flavor(ctx) == FlavorServer {
+		param = "username"
+	}
+
+	endpoint := fmt.Sprintf("/user/search?%s=%s&maxResults=%d", param, url.QueryEscape(query), userSearchLimit)
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var users []JiraUser
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return users, nil
+}
+
+// AssignIssue sets an issue's assignee; a nil user unassigns it
+func (c *Client) AssignIssue(ctx context.Context, issueKey string, user *JiraUserRef) error {
+	var request interface{} = user
+	if user == nil {
+		field := "accountId"
+		if c.Flavor(ctx) == FlavorServer {
+			field = "name"
+		}
+		request = map[string]interface{}{field: nil}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s/assignee", issueKey)
+	resp, err := c.doRequest(ctx, "PUT", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 {
+		return c.parseErrorResponse(resp)
+	}
+
+	return nil
+}
+
+// userDirectoryCache is the persisted form of the user directory
+type userDirectoryCache struct {
+	Me    string       `json:"me,omitempty"` // ID of the authenticated user
+	Users []types.User `json:"users"`
+}
+
+// UserDirectory resolves emails and display names to Jira users, keeping
+// every user it sees in a local cache for offline lookups and completion
+type UserDirectory struct {
+	client *Client
+	cache  Cache
+	mu     sync.Mutex
+	loaded bool
+	me     string
+	users  map[string]types.User
+}
+
+// NewUserDirectory creates a user directory. cache may be nil, in which case
+// users are only kept in memory for the life of the process.
+func NewUserDirectory(client *Client, cache Cache) *UserDirectory {
+	return &UserDirectory{
+		client: client,
+		cache:  cache,
+		users:  make(map[string]types.User),
+	}
+}
+
+// Resolve finds the user who, given as an account ID, username, email or
+// display name. "me" is the authenticated user. Known users are resolved
+// without asking Jira.
+func (ud *UserDirectory) Resolve(ctx context.Context, who string) (*types.User, error) {
+	who = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(who), "@"))
+	if who == "" {
+		return nil, fmt.Errorf("no user given")
+	}
+	if strings.EqualFold(who, "me") {
+		return ud.Me(ctx)
+	}
+
+	if user := ud.Lookup(who); user != nil {
+		return user, nil
+	}
+
+	users, err := ud.Search(ctx, who)
+	if err != nil {
+		return nil, err
+	}
+	if user := matchUser(users, who); user != nil {
+		return user, nil
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, fmt.Errorf("no Jira user matches %q", who)
+	case 1:
+		return &users[0], nil
+	default:
+		var names []string
+		for i := range users {
+			names = append(names, users[i].String())
+		}
+		return nil, fmt.Errorf("%q matches several users: %s", who, strings.Join(names, "; "))
+	}
+}
+
+// Lookup finds a user in the directory without asking Jira
+func (ud *UserDirectory) Lookup(who string) *types.User {
+	ud.mu.Lock()
+	defer ud.mu.Unlock()
+
+	ud.load()
+	return matchUser(ud.knownLocked(), strings.TrimPrefix(strings.TrimSpace(who), "@"))
+}
+
+// Me returns the authenticated user
+func (ud *UserDirectory) Me(ctx context.Context) (*types.User, error) {
+	ud.mu.Lock()
+	ud.load()
+	if user, ok := ud.users[ud.me]; ok && ud.me != "" {
+		ud.mu.Unlock()
+		return &user, nil
+	}
+	ud.mu.Unlock()
+
+	jiraUser, err := ud.client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current user: %v", err)
+	}
+
+	user := convertUser(jiraUser)
+
+	ud.mu.Lock()
+	defer ud.mu.Unlock()
+	ud.me = user.ID
+	ud.users[user.ID] = user
+	ud.saveLocked()
+
+	return &user, nil
+}
+
+// Search asks Jira for users matching query and remembers them
+func (ud *UserDirectory) Search(ctx context.Context, query string) ([]types.User, error) {
+	jiraUsers, err := ud.client.SearchUsers(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %v", err)
+	}
+
+	var users []types.User
+	for i := range jiraUsers {
+		if user := convertUser(&jiraUsers[i]); user.ID != "" {
+			users = append(users, user)
+		}
+	}
+
+	ud.Remember(users...)
+	return users, nil
+}
+
+// Remember adds users to the directory
+func (ud *UserDirectory) Remember(users ...types.User) {
+	if len(users) == 0 {
+		return
+	}
+
+	ud.mu.Lock()
+	defer ud.mu.Unlock()
+
+	ud.load()
+	changed := false
+	for _, user := range users {
+		existing, ok := ud.users[user.ID]
+
+		// Keep an email learned earlier when a later response hides it
+		if user.Email == "" {
+			user.Email = existing.Email
+		}
+		if !ok || existing != user {
+			ud.users[user.ID] = user
+			changed = true
+		}
+	}
+	if changed {
+		ud.saveLocked()
+	}
+}
+
+// Known returns the users in the directory ordered by display name
+func (ud *UserDirectory) Known() []types.User {
+	ud.mu.Lock()
+	defer ud.mu.Unlock()
+
+	ud.load()
+	return ud.knownLocked()
+}
+
+// knownLocked lists the directory; callers must hold ud.mu
+func (ud *UserDirectory) knownLocked() []types.User {
+	users := make([]types.User, 0, len(ud.users))
+	for _, user := range ud.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].DisplayName) < strings.ToLower(users[j].DisplayName)
+	})
+	return users
+}
+
+// load reads the cache once; callers must hold ud.mu
+func (ud *UserDirectory) load() {
+	if ud.loaded || ud.cache == nil {
+		ud.loaded = true
+		return
+	}
+	ud.loaded = true
+
+	var cached userDirectoryCache
+	if found, err := ud.cache.LoadCache(usersCacheName, &cached); err != nil || !found {
+		return
+	}
+	ud.me = cached.Me
+	for _, user := range cached.Users {
+		if _, ok := ud.users[user.ID]; !ok {
+			ud.users[user.ID] = user
+		}
+	}
+}
+
+// saveLocked writes the directory to the cache; callers must hold ud.mu.
+// A cache that cannot be written only costs a later lookup.
+func (ud *UserDirectory) saveLocked() {
+	if ud.cache == nil {
+		return
+	}
+	ud.cache.SaveCache(usersCacheName, userDirectoryCache{Me: ud.me, Users: ud.knownLocked()})
+}
+
+// matchUser finds the one user who is exactly named by who, or nil when
+// none or several are
+func matchUser(users []types.User, who string) *types.User {
+	var match *types.User
+	for i := range users {
+		user := &users[i]
+		if user.ID == who || (user.Email != "" && strings.EqualFold(user.Email, who)) || strings.EqualFold(user.DisplayName, who) {
+			if match != nil && match.ID != user.ID {
+				return nil
+			}
+			match = user
+		}
+	}
+	return match
+}
+
+// convertUser converts a Jira user to our internal format
+func convertUser(jiraUser *JiraUser) types.User {
+	id := jiraUser.AccountID
+	if id == "" {
+		id = jiraUser.Name
+	}
+
+	return types.User{
+		ID:          id,
+		DisplayName: jiraUser.DisplayName,
+		Email:       jiraUser.Email,
+		Active:      jiraUser.Active,
+	}
+}
+
+// AssignTicket assigns a ticket to a user; a nil user unassigns it
+func (ts *TicketService) AssignTicket(ctx context.Context, ticketKey string, user *types.User) error {
+	var ref *JiraUserRef
+	if user != nil {
+		ref = ts.client.userRef(ctx, user.ID)
+	}
+
+	if err := ts.client.AssignIssue(ctx, ticketKey, ref); err != nil {
+		return fmt.Errorf("failed to assign %s: %v", ticketKey, err)
+	}
+
+	return nil
+}
+
+// assigneeRef references the assignee of a ticket. Emails, display names
+// and "me" are resolved through the user directory, and are an error when
+// no single user matches; anything else is taken as the ID Jira knows the
+// user by.
+func (ts *TicketService) assigneeRef(ctx context.Context, assignee string) (*JiraUserRef, error) {
+	if user := ts.users.Lookup(assignee); user != nil {
+		return ts.client.userRef(ctx, user.ID), nil
+	}
+	if strings.Contains(assignee, "@") || strings.Contains(assignee, " ") || strings.EqualFold(assignee, "me") {
+		user, err := ts.users.Resolve(ctx, assignee)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee: %v", err)
+		}
+		return ts.client.userRef(ctx, user.ID), nil
+	}
+	return ts.client.userRef(ctx, assignee), nil
+}
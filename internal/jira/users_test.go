@@ -0,0 +1,135 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// newUserServer serves user search, /myself and assignment for TEST-1
+func newUserServer(t *testing.T, searches *[]string, assigned *map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/user/search"):
+			query := r.URL.Query().Get("query") + r.URL.Query().Get("username")
+			*searches = append(*searches, query)
+			var users []JiraUser
+			for _, user := range []JiraUser{
+				{AccountID: "acc-jane", DisplayName: "Jane Doe", Email: "jane@example.com", Active: true},
+				{AccountID: "acc-janet", DisplayName: "Janet Roe", Active: true},
+			} {
+				if strings.Contains(strings.ToLower(user.DisplayName+" "+user.Email), strings.ToLower(query)) {
+					users = append(users, user)
+				}
+			}
+			json.NewEncoder(w).Encode(users)
+		case strings.HasSuffix(r.URL.Path, "/myself"):
+			json.NewEncoder(w).Encode(JiraUser{AccountID: "acc-me", DisplayName: "Me Myself"})
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/issue/TEST-1/assignee"):
+			if err := json.NewDecoder(r.Body).Decode(assigned); err != nil {
+				t.Fatalf("Failed to decode request: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestUserDirectoryResolve(t *testing.T) {
+	var searches []string
+	server := newUserServer(t, &searches, nil)
+	defer server.Close()
+
+	cache := &memoryCache{entries: make(map[string][]byte)}
+	directory := NewUserDirectory(NewClient(&types.JiraConfig{URL: server.URL}), cache)
+
+	user, err := directory.Resolve(context.Background(), "jane@example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user.ID != "acc-jane" || user.DisplayName != "Jane Doe" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	// "jan" matches two users
+	if _, err := directory.Resolve(context.Background(), "jan"); err == nil || !strings.Contains(err.Error(), "several users") {
+		t.Errorf("Expected ambiguity error, got %v", err)
+	}
+
+	if _, err := directory.Resolve(context.Background(), "nobody"); err == nil {
+		t.Error("Expected error for unknown user")
+	}
+
+	me, err := directory.Resolve(context.Background(), "me")
+	if err != nil || me.ID != "acc-me" {
+		t.Fatalf("Expected current user, got %+v (%v)", me, err)
+	}
+
+	// A new directory on the same cache resolves known users without Jira
+	searches = nil
+	cached := NewUserDirectory(NewClient(&types.JiraConfig{URL: server.URL}), cache)
+	for _, who := range []string{"Janet Roe", "@jane@example.com", "acc-jane", "me"} {
+		if _, err := cached.Resolve(context.Background(), who); err != nil {
+			t.Errorf("Failed to resolve %q from cache: %v", who, err)
+		}
+	}
+	if len(searches) != 0 {
+		t.Errorf("Expected no searches, got %v", searches)
+	}
+	if known := cached.Known(); len(known) != 3 || known[0].DisplayName != "Jane Doe" {
+		t.Errorf("Unexpected known users: %+v", known)
+	}
+}
+
+func TestAssignTicket(t *testing.T) {
+	var searches []string
+	var assigned map[string]interface{}
+	server := newUserServer(t, &searches, &assigned)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	if err := service.AssignTicket(context.Background(), "TEST-1", &types.User{ID: "acc-jane"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if assigned["accountId"] != "acc-jane" {
+		t.Errorf("Expected accountId acc-jane, got %v", assigned)
+	}
+
+	if err := service.AssignTicket(context.Background(), "TEST-1", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value, ok := assigned["accountId"]; !ok || value != nil {
+		t.Errorf("Expected null accountId to unassign, got %v", assigned)
+	}
+}
+
+func TestAssigneeRefResolvesEmails(t *testing.T) {
+	var searches []string
+	server := newUserServer(t, &searches, nil)
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	if ref, err := service.assigneeRef(context.Background(), "jane@example.com"); err != nil || ref.AccountID != "acc-jane" {
+		t.Errorf("Expected email resolved to acc-jane, got %+v (%v)", ref, err)
+	}
+
+	// IDs are used as they are
+	searches = nil
+	if ref, err := service.assigneeRef(context.Background(), "acc-other"); err != nil || ref.AccountID != "acc-other" || len(searches) != 0 {
+		t.Errorf("Expected ID used without search, got %+v after %v (%v)", ref, searches, err)
+	}
+
+	// Names that match nobody are an error rather than a made-up ID
+	if ref, err := service.assigneeRef(context.Background(), "nobody@example.com"); err == nil || !strings.Contains(err.Error(), `no Jira user matches "nobody@example.com"`) {
+		t.Errorf("Expected an unknown email to fail, got %+v (%v)", ref, err)
+	}
+}
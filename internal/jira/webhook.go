@@ -0,0 +1,224 @@
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// Webhook events the receiver acts on
+const (
+	WebhookIssueCreated   = "jira:issue_created"
+	WebhookIssueUpdated   = "jira:issue_updated"
+	WebhookIssueDeleted   = "jira:issue_deleted"
+	WebhookCommentCreated = "comment_created"
+)
+
+// MaxWebhookSize limits the size of a webhook payload
+const MaxWebhookSize = 10 << 20
+
+// webhookSignatureHeader carries the HMAC-SHA256 signature Jira Cloud sends
+// for webhooks registered with a secret
+const webhookSignatureHeader = "X-Hub-Signature"
+
+// JiraWebhookEvent is the payload Jira posts to a webhook
+type JiraWebhookEvent struct {
+	Timestamp    int64        `json:"timestamp"`
+	WebhookEvent string       `json:"webhookEvent"`
+	Issue        *JiraIssue   `json:"issue,omitempty"`
+	Comment      *JiraComment `json:"comment,omitempty"`
+}
+
+// WebhookStore is the local ticket store a WebhookHandler keeps fresh
+type WebhookStore interface {
+	Exists(key string) bool
+	UpdateTicket(key string, update func(current *types.Ticket) (*types.Ticket, error)) error
+	DeleteTicket(key string) error
+}
+
+// WebhookHandler receives Jira webhooks and applies them to tracked tickets.
+// New issues are tracked when their parent is; other issues are ignored.
+type WebhookHandler struct {
+	service *TicketService
+	store   WebhookStore
+	secret  string
+
+	// Logf, when set, reports what each webhook did
+	Logf func(format string, args ...interface{})
+
+	// Webhooks are applied one at a time, in the order they arrive
+	mu sync.Mutex
+}
+
+// NewWebhookHandler creates a handler that accepts webhooks carrying secret
+func NewWebhookHandler(service *TicketService, store WebhookStore, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		store:   store,
+		secret:  secret,
+	}
+}
+
+// ServeHTTP verifies and applies one webhook
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxWebhookSize+1))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > MaxWebhookSize {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !h.verify(r, body) {
+		h.logf("rejected webhook from %s: bad secret", r.RemoteAddr)
+		http.Error(w, "invalid secret", http.StatusUnauthorized)
+		return
+	}
+
+	var event JiraWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Apply(r.Context(), &event); err != nil {
+		h.logf("%s failed: %v", event.WebhookEvent, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the webhook's HMAC signature or, for sites that cannot sign
+// webhooks, the secret given as a "secret" query parameter
+func (h *WebhookHandler) verify(r *http.Request, body []byte) bool {
+	if h.secret == "" {
+		return false
+	}
+
+	if signature := r.Header.Get(webhookSignatureHeader); signature != "" {
+		method, digest, ok := strings.Cut(signature, "=")
+		if !ok || method != "sha256" {
+			return false
+		}
+		expected, err := hex.DecodeString(digest)
+		if err != nil {
+			return false
+		}
+		return hmac.Equal(expected, WebhookSignature(h.secret, body))
+	}
+
+	return hmac.Equal([]byte(r.URL.Query().Get("secret")), []byte(h.secret))
+}
+
+// WebhookSignature returns the HMAC-SHA256 of a webhook body under secret
+func WebhookSignature(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Apply updates the local store for a webhook event
+func (h *WebhookHandler) Apply(ctx context.Context, event *JiraWebhookEvent) error {
+	if event.Issue == nil || event.Issue.Key == "" {
+		h.logf("ignored %s without an issue", event.WebhookEvent)
+		return nil
+	}
+	key := event.Issue.Key
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch event.WebhookEvent {
+	case WebhookIssueCreated, WebhookIssueUpdated:
+		return h.saveIssue(event.WebhookEvent, h.service.convertJiraIssueToTicket(ctx, event.Issue))
+	case WebhookIssueDeleted:
+		if !h.store.Exists(key) {
+			return nil
+		}
+		if err := h.store.DeleteTicket(key); err != nil {
+			return err
+		}
+		h.logf("%s deleted", key)
+		return nil
+	case WebhookCommentCreated:
+		// Comment payloads carry only a summary of the issue
+		if !h.store.Exists(key) {
+			return nil
+		}
+		ticket, err := h.service.GetTicket(ctx, key)
+		if err != nil {
+			return err
+		}
+		return h.saveIssue(event.WebhookEvent, ticket)
+	}
+
+	h.logf("ignored %s for %s", event.WebhookEvent, key)
+	return nil
+}
+
+// saveIssue stores a ticket from Jira if it is tracked, or if its parent is.
+// Local state is kept, and tickets with unpushed edits are left alone so
+// the edits are not lost. An issue last updated before the stored copy is
+// a late delivery and is ignored.
+func (h *WebhookHandler) saveIssue(event string, ticket *types.Ticket) error {
+	parentTracked := ticket.Relationships.ParentKey != "" && h.store.Exists(ticket.Relationships.ParentKey)
+
+	action := ""
+	err := h.store.UpdateTicket(ticket.Key, func(current *types.Ticket) (*types.Ticket, error) {
+		switch {
+		case current == nil:
+			if !parentTracked {
+				return nil, nil
+			}
+			action = "tracked"
+		case current.LocalData.LocalChanges:
+			action = "kept (unpushed local changes)"
+			return nil, nil
+		case !ticket.Metadata.Updated.IsZero() && ticket.Metadata.Updated.Before(current.Metadata.Updated):
+			// Jira retries deliveries and does not keep them in order
+			action = "kept (event older than the stored copy)"
+			return nil, nil
+		default:
+			ticket.LocalData = current.LocalData
+			action = "updated"
+		}
+
+		ticket.LocalData.LastSync = time.Now()
+		return ticket, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if action != "" {
+		h.logf("%s %s (%s)", ticket.Key, action, event)
+	}
+	return nil
+}
+
+// logf reports through Logf when it is set
+func (h *WebhookHandler) logf(format string, args ...interface{}) {
+	if h.Logf != nil {
+		h.Logf(format, args...)
+	}
+}
@@ -0,0 +1,156 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/internal/storage"
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+const testWebhookSecret = "s3cret"
+
+// postWebhook posts a fixture from testdata/webhooks, signed with secret
+func postWebhook(t *testing.T, url, fixture, secret string) int {
+	body, err := os.ReadFile(filepath.Join("testdata", "webhooks", fixture))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(WebhookSignature(secret, body)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to post %s: %v", fixture, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestWebhookHandler(t *testing.T) {
+	// Jira serves the issue refetched after a comment
+	jira := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/PROJ-1" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"key": "PROJ-1", "fields": {"summary": "Roll out MFA", "status": {"name": "In Progress"}, "issuetype": {"name": "Epic"}}}`)
+	}))
+	defer jira.Close()
+
+	store, err := storage.NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	epic := types.NewTicket("PROJ-1", "Roll out MFA", types.TicketTypeEpic)
+	task := types.NewTicket("PROJ-2", "Add TOTP enrollment", types.TicketTypeTask)
+	task.Relationships.ParentKey = "PROJ-1"
+	task.LocalData.AIEnhanced = true
+	task.Metadata.Updated = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	for _, ticket := range []*types.Ticket{epic, task} {
+		if err := store.SaveTicket(ticket); err != nil {
+			t.Fatalf("Failed to save ticket: %v", err)
+		}
+	}
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: jira.URL}))
+	server := httptest.NewServer(NewWebhookHandler(service, store, testWebhookSecret))
+	defer server.Close()
+
+	// Unsigned and wrongly signed webhooks are rejected
+	if status := postWebhook(t, server.URL, "issue_updated.json", ""); status != http.StatusUnauthorized {
+		t.Errorf("Expected unsigned webhook to be rejected, got %d", status)
+	}
+	if status := postWebhook(t, server.URL, "issue_updated.json", "wrong"); status != http.StatusUnauthorized {
+		t.Errorf("Expected wrongly signed webhook to be rejected, got %d", status)
+	}
+	if updated, _ := store.LoadTicket("PROJ-2"); updated.Title != "Add TOTP enrollment" {
+		t.Fatalf("Expected rejected webhooks to change nothing, got %q", updated.Title)
+	}
+
+	// An update replaces the Jira fields and keeps local state
+	if status := postWebhook(t, server.URL, "issue_updated.json", testWebhookSecret); status != http.StatusNoContent {
+		t.Fatalf("Expected update to be accepted, got %d", status)
+	}
+	updated, _ := store.LoadTicket("PROJ-2")
+	if updated.Title != "Add TOTP enrollment (QR code)" || updated.Status != "In Progress" || updated.Description != "Users scan a **QR code**." {
+		t.Errorf("Unexpected updated ticket: %q %q %q", updated.Title, updated.Status, updated.Description)
+	}
+	if !updated.LocalData.AIEnhanced {
+		t.Error("Expected local state to be kept")
+	}
+
+	// A delivery of an earlier update arriving late does not roll it back
+	if status := postWebhook(t, server.URL, "issue_updated_stale.json", testWebhookSecret); status != http.StatusNoContent {
+		t.Fatalf("Expected stale update to be accepted, got %d", status)
+	}
+	if updated, _ := store.LoadTicket("PROJ-2"); updated.Title != "Add TOTP enrollment (QR code)" || updated.Status != "In Progress" {
+		t.Errorf("Expected the newer update to be kept, got %q %q", updated.Title, updated.Status)
+	}
+
+	// A new issue under a tracked epic is tracked; the query secret works too
+	if status := postWebhook(t, server.URL+"?secret="+testWebhookSecret, "issue_created.json", ""); status != http.StatusNoContent {
+		t.Fatalf("Expected create to be accepted, got %d", status)
+	}
+	epic, _ = store.LoadTicket("PROJ-1")
+	if !store.Exists("PROJ-3") || fmt.Sprint(epic.Relationships.Children) != "[PROJ-2 PROJ-3]" {
+		t.Errorf("Expected PROJ-3 tracked under PROJ-1, got children %v", epic.Relationships.Children)
+	}
+
+	// A comment refetches the issue
+	if status := postWebhook(t, server.URL, "comment_created.json", testWebhookSecret); status != http.StatusNoContent {
+		t.Fatalf("Expected comment to be accepted, got %d", status)
+	}
+	if epic, _ = store.LoadTicket("PROJ-1"); epic.Status != "In Progress" {
+		t.Errorf("Expected the epic to be refetched, got status %q", epic.Status)
+	}
+
+	// A deletion removes the ticket and its link from the parent
+	if status := postWebhook(t, server.URL, "issue_deleted.json", testWebhookSecret); status != http.StatusNoContent {
+		t.Fatalf("Expected delete to be accepted, got %d", status)
+	}
+	epic, _ = store.LoadTicket("PROJ-1")
+	if store.Exists("PROJ-2") || fmt.Sprint(epic.Relationships.Children) != "[PROJ-3]" {
+		t.Errorf("Expected PROJ-2 removed, got children %v", epic.Relationships.Children)
+	}
+}
+
+func TestWebhookHandlerKeepsLocalChanges(t *testing.T) {
+	store, err := storage.NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	task := types.NewTicket("PROJ-2", "My unpushed title", types.TicketTypeTask)
+	task.LocalData.LocalChanges = true
+	if err := store.SaveTicket(task); err != nil {
+		t.Fatalf("Failed to save ticket: %v", err)
+	}
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: "http://127.0.0.1:0"}))
+	server := httptest.NewServer(NewWebhookHandler(service, store, testWebhookSecret))
+	defer server.Close()
+
+	if status := postWebhook(t, server.URL, "issue_updated.json", testWebhookSecret); status != http.StatusNoContent {
+		t.Fatalf("Expected update to be accepted, got %d", status)
+	}
+	if kept, _ := store.LoadTicket("PROJ-2"); kept.Title != "My unpushed title" {
+		t.Errorf("Expected unpushed edits to be kept, got %q", kept.Title)
+	}
+}
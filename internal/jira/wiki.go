@@ -0,0 +1,683 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Jira Server and Data Center store descriptions and comments as wiki
+// markup. These converters translate between wiki markup and ADF so that
+// ADF remains the single intermediate format for markdown conversion.
+
+var (
+	wikiHeadingPattern = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	wikiCodePattern    = regexp.MustCompile(`^\{(code|noformat)(:[^}]*)?\}(.*)$`)
+	wikiListPattern    = regexp.MustCompile(`^([*#]+|-)\s+(.*)$`)
+	wikiRulePattern    = regexp.MustCompile(`^-{4,}$`)
+	wikiImagePattern   = regexp.MustCompile(`^!([^!|\s]+)(\|[^!]*)?!$`)
+	wikiPanelPattern   = regexp.MustCompile(`^\{(quote|panel)(:[^}]*)?\}(.*)$`)
+)
+
+// wikiMarks maps single-character wiki delimiters to ADF mark types
+var wikiMarks = map[byte]string{
+	'*': "strong",
+	'_': "em",
+	'-': "strike",
+	'+': "underline",
+}
+
+// WikiToADF converts Jira wiki markup into an ADF document.
+// Returns nil for blank input.
+func WikiToADF(wiki string) *ADFNode {
+	wiki = strings.ReplaceAll(wiki, "\r\n", "\n")
+	if strings.TrimSpace(wiki) == "" {
+		return nil
+	}
+
+	p := &wikiParser{}
+	return NewADFDocument(p.parseBlocks(strings.Split(wiki, "\n"))...)
+}
+
+// wikiParser converts wiki markup into ADF nodes
+type wikiParser struct{}
+
+// parseBlocks parses a sequence of lines into block nodes
+func (p *wikiParser) parseBlocks(lines []string) []*ADFNode {
+	var blocks []*ADFNode
+
+	for i := 0; i < len(lines); {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			i++
+		case wikiCodePattern.MatchString(trimmed):
+			var node *ADFNode
+			node, i = p.parseCodeBlock(lines, i)
+			blocks = append(blocks, node)
+		case wikiPanelPattern.MatchString(trimmed):
+			var node *ADFNode
+			node, i = p.parseQuote(lines, i)
+			blocks = append(blocks, node)
+		case strings.HasPrefix(trimmed, "bq. "):
+			blocks = append(blocks, &ADFNode{
+				Type:    "blockquote",
+				Content: []*ADFNode{{Type: "paragraph", Content: p.parseInline(trimmed[4:], nil)}},
+			})
+			i++
+		case wikiHeadingPattern.MatchString(trimmed):
+			match := wikiHeadingPattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, &ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]interface{}{"level": int(match[1][0] - '0')},
+				Content: p.parseInline(match[2], nil),
+			})
+			i++
+		case wikiRulePattern.MatchString(trimmed):
+			blocks = append(blocks, &ADFNode{Type: "rule"})
+			i++
+		case strings.HasPrefix(trimmed, "|"):
+			var node *ADFNode
+			node, i = p.parseTable(lines, i)
+			blocks = append(blocks, node)
+		case wikiListPattern.MatchString(trimmed):
+			var node *ADFNode
+			node, i = p.parseList(lines, i)
+			blocks = append(blocks, node)
+		case wikiImagePattern.MatchString(trimmed):
+			match := wikiImagePattern.FindStringSubmatch(trimmed)
+			blocks = append(blocks, mediaSingle("", wikiMediaSource(match[1])))
+			i++
+		default:
+			var node *ADFNode
+			node, i = p.parseParagraph(lines, i)
+			blocks = append(blocks, node)
+		}
+	}
+
+	return blocks
+}
+
+// parseCodeBlock parses {code}/{noformat} blocks, which may open and close on one line
+func (p *wikiParser) parseCodeBlock(lines []string, start int) (*ADFNode, int) {
+	match := wikiCodePattern.FindStringSubmatch(strings.TrimSpace(lines[start]))
+	tag, params, rest := match[1], strings.TrimPrefix(match[2], ":"), match[3]
+	closing := "{" + tag + "}"
+
+	language := ""
+	if tag == "code" {
+		for _, param := range strings.Split(params, "|") {
+			if param != "" && !strings.Contains(param, "=") {
+				language = param
+			}
+		}
+	}
+
+	var code []string
+	i := start + 1
+	if end := strings.Index(rest, closing); end >= 0 {
+		code = append(code, rest[:end])
+	} else {
+		if rest != "" {
+			code = append(code, rest)
+		}
+		for ; i < len(lines); i++ {
+			if end := strings.Index(lines[i], closing); end >= 0 {
+				if end > 0 {
+					code = append(code, lines[i][:end])
+				}
+				i++
+				break
+			}
+			code = append(code, lines[i])
+		}
+	}
+
+	node := &ADFNode{Type: "codeBlock"}
+	if language != "" {
+		node.Attrs = map[string]interface{}{"language": language}
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []*ADFNode{{Type: "text", Text: text}}
+	}
+	return node, i
+}
+
+// parseQuote parses {quote} and {panel} blocks into a blockquote
+func (p *wikiParser) parseQuote(lines []string, start int) (*ADFNode, int) {
+	match := wikiPanelPattern.FindStringSubmatch(strings.TrimSpace(lines[start]))
+	closing := "{" + match[1] + "}"
+
+	var inner []string
+	rest := match[3]
+	i := start + 1
+	if end := strings.Index(rest, closing); end >= 0 {
+		inner = append(inner, rest[:end])
+	} else {
+		inner = append(inner, rest)
+		for ; i < len(lines); i++ {
+			if end := strings.Index(lines[i], closing); end >= 0 {
+				inner = append(inner, lines[i][:end])
+				i++
+				break
+			}
+			inner = append(inner, lines[i])
+		}
+	}
+
+	return &ADFNode{Type: "blockquote", Content: p.parseBlocks(inner)}, i
+}
+
+// parseTable parses consecutive || header and | cell rows
+func (p *wikiParser) parseTable(lines []string, start int) (*ADFNode, int) {
+	table := &ADFNode{Type: "table"}
+
+	i := start
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "|") {
+			break
+		}
+
+		cellType := "tableCell"
+		separator := "|"
+		if strings.HasPrefix(line, "||") {
+			cellType = "tableHeader"
+			separator = "||"
+		}
+
+		row := &ADFNode{Type: "tableRow"}
+		for _, cell := range splitWikiRow(line, separator) {
+			row.Content = append(row.Content, &ADFNode{
+				Type:    cellType,
+				Content: []*ADFNode{{Type: "paragraph", Content: p.parseInline(strings.TrimSpace(cell), nil)}},
+			})
+		}
+		table.Content = append(table.Content, row)
+	}
+
+	return table, i
+}
+
+// parseList parses nested * (bullet), # (numbered) and - (bullet) lists
+func (p *wikiParser) parseList(lines []string, start int) (*ADFNode, int) {
+	type frame struct {
+		marker byte
+		list   *ADFNode
+	}
+
+	var stack []frame
+	var root *ADFNode
+
+	i := start
+	for ; i < len(lines); i++ {
+		match := wikiListPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if match == nil {
+			break
+		}
+
+		markers := match[1]
+		if markers == "-" {
+			markers = "*"
+		}
+		if root != nil && markers[0] != stack[0].marker {
+			break
+		}
+
+		// Close deeper lists and lists whose marker changed at this depth
+		depth := len(markers)
+		for len(stack) > depth || (len(stack) > 0 && len(stack) == depth && stack[depth-1].marker != markers[depth-1]) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 && root != nil {
+			break
+		}
+
+		// Open lists down to the item depth
+		for len(stack) < depth {
+			list := &ADFNode{Type: wikiListType(markers[len(stack)])}
+			if len(stack) == 0 {
+				root = list
+			} else {
+				parent := stack[len(stack)-1].list
+				if len(parent.Content) == 0 {
+					parent.Content = append(parent.Content, &ADFNode{Type: "listItem"})
+				}
+				item := parent.Content[len(parent.Content)-1]
+				item.Content = append(item.Content, list)
+			}
+			stack = append(stack, frame{marker: markers[len(stack)], list: list})
+		}
+
+		list := stack[len(stack)-1].list
+		list.Content = append(list.Content, &ADFNode{
+			Type:    "listItem",
+			Content: []*ADFNode{{Type: "paragraph", Content: p.parseInline(match[2], nil)}},
+		})
+	}
+
+	return root, i
+}
+
+// parseParagraph collects lines until a blank line or the start of another block
+func (p *wikiParser) parseParagraph(lines []string, start int) (*ADFNode, int) {
+	var text []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || (i > start && startsWikiBlock(trimmed)) {
+			break
+		}
+		text = append(text, trimmed)
+	}
+
+	return &ADFNode{Type: "paragraph", Content: p.parseInline(strings.Join(text, "\n"), nil)}, i
+}
+
+// parseInline parses inline wiki markup into text nodes with marks
+func (p *wikiParser) parseInline(text string, marks []*ADFMark) []*ADFNode {
+	var nodes []*ADFNode
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = appendInline(nodes, &ADFNode{Type: "text", Text: buf.String(), Marks: marks})
+			buf.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+
+		switch {
+		case strings.HasPrefix(rest, `\\`):
+			flush()
+			nodes = append(nodes, &ADFNode{Type: "hardBreak"})
+			i += 2
+			for i < len(text) && (text[i] == ' ' || text[i] == '\n') {
+				i++
+			}
+			continue
+
+		case c == '\\' && i+1 < len(text):
+			buf.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '\n':
+			flush()
+			nodes = append(nodes, &ADFNode{Type: "hardBreak"})
+			i++
+			continue
+
+		case strings.HasPrefix(rest, "{{"):
+			if end := strings.Index(rest[2:], "}}"); end > 0 {
+				flush()
+				nodes = appendInline(nodes, &ADFNode{Type: "text", Text: rest[2 : 2+end], Marks: codeMarks(marks)})
+				i += end + 4
+				continue
+			}
+
+		case c == '[':
+			if end := strings.IndexByte(rest, ']'); end > 1 {
+				flush()
+				nodes = appendInline(nodes, p.parseLink(rest[1:end], marks)...)
+				i += end + 1
+				continue
+			}
+
+		case wikiMarks[c] != "" && wikiLeftBoundary(text, i):
+			if end := findWikiClose(text, i+1, c); end > i+1 {
+				flush()
+				nodes = appendInline(nodes, p.parseInline(text[i+1:end], addMark(marks, &ADFMark{Type: wikiMarks[c]}))...)
+				i = end + 1
+				continue
+			}
+		}
+
+		buf.WriteByte(c)
+		i++
+	}
+
+	flush()
+	return nodes
+}
+
+// parseLink converts the inside of [...] into a mention or linked text
+func (p *wikiParser) parseLink(inner string, marks []*ADFMark) []*ADFNode {
+	if strings.HasPrefix(inner, "~") {
+		id := strings.TrimPrefix(inner[1:], mentionRefPrefix)
+		return []*ADFNode{{
+			Type:  "mention",
+			Attrs: map[string]interface{}{"id": id, "text": "@" + id},
+		}}
+	}
+
+	label, href := inner, inner
+	if bar := strings.LastIndexByte(inner, '|'); bar >= 0 {
+		label, href = inner[:bar], inner[bar+1:]
+	}
+
+	link := &ADFMark{Type: "link", Attrs: map[string]interface{}{"href": href}}
+	if label == href {
+		return []*ADFNode{{Type: "text", Text: label, Marks: addMark(marks, link)}}
+	}
+	return p.parseInline(label, addMark(marks, link))
+}
+
+// ADFToWiki converts an ADF document (or any ADF node) into Jira wiki markup
+func ADFToWiki(node *ADFNode) string {
+	if node == nil {
+		return ""
+	}
+
+	r := &wikiRenderer{}
+	if node.Type == "doc" {
+		return strings.TrimSpace(r.renderBlocks(node.Content))
+	}
+	return strings.TrimSpace(r.renderBlock(node))
+}
+
+// wikiRenderer renders ADF nodes as wiki markup
+type wikiRenderer struct{}
+
+// renderBlocks renders block nodes separated by blank lines
+func (r *wikiRenderer) renderBlocks(nodes []*ADFNode) string {
+	var blocks []string
+	for _, node := range nodes {
+		if rendered := r.renderBlock(node); rendered != "" {
+			blocks = append(blocks, rendered)
+		}
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// renderBlock renders a single block node
+func (r *wikiRenderer) renderBlock(node *ADFNode) string {
+	switch node.Type {
+	case "paragraph":
+		return r.renderInline(node.Content)
+	case "heading":
+		return "h" + string(rune('0'+attrInt(node.Attrs, "level", 1))) + ". " + r.renderInline(node.Content)
+	case "codeBlock":
+		open := "{code}"
+		if language := attrString(node.Attrs, "language"); language != "" {
+			open = "{code:" + language + "}"
+		}
+		return open + "\n" + plainText(node) + "\n{code}"
+	case "blockquote", "panel":
+		return "{quote}\n" + r.renderBlocks(node.Content) + "\n{quote}"
+	case "rule":
+		return "----"
+	case "bulletList", "orderedList", "taskList":
+		return r.renderList(node, "")
+	case "table":
+		return r.renderTable(node)
+	case "mediaSingle", "mediaGroup":
+		var media []string
+		for _, child := range node.Content {
+			media = append(media, renderWikiMedia(child))
+		}
+		return strings.Join(media, "\n\n")
+	case "media":
+		return renderWikiMedia(node)
+	case "expand", "nestedExpand":
+		title := attrString(node.Attrs, "title")
+		content := r.renderBlocks(node.Content)
+		if title == "" {
+			return content
+		}
+		return "*" + escapeWiki(title) + "*\n\n" + content
+	case "text", "hardBreak", "mention", "emoji", "inlineCard", "status", "date":
+		return r.renderInline([]*ADFNode{node})
+	default:
+		return r.renderBlocks(node.Content)
+	}
+}
+
+// renderList renders nested lists using accumulated * and # markers
+func (r *wikiRenderer) renderList(node *ADFNode, prefix string) string {
+	marker := "*"
+	if node.Type == "orderedList" {
+		marker = "#"
+	}
+	prefix += marker
+
+	var lines []string
+	for _, item := range node.Content {
+		if isListNode(item) {
+			lines = append(lines, r.renderList(item, prefix))
+			continue
+		}
+
+		var text []string
+		var nested []string
+		if item.Type == "taskItem" {
+			// Wiki markup has no checkboxes, so show the state as literal text
+			box := "\\[ \\] "
+			if attrString(item.Attrs, "state") == "DONE" {
+				box = "\\[x\\] "
+			}
+			text = append(text, box+r.renderInline(item.Content))
+		}
+		for _, child := range item.Content {
+			switch {
+			case item.Type == "taskItem":
+			case isListNode(child):
+				nested = append(nested, r.renderList(child, prefix))
+			default:
+				text = append(text, strings.ReplaceAll(r.renderBlock(child), "\n", " \\\\ "))
+			}
+		}
+		lines = append(lines, prefix+" "+strings.Join(text, " \\\\ "))
+		lines = append(lines, nested...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTable renders header cells with || and body cells with |
+func (r *wikiRenderer) renderTable(node *ADFNode) string {
+	var lines []string
+	for _, row := range node.Content {
+		var line strings.Builder
+		separator := "|"
+		for _, cell := range row.Content {
+			separator = "|"
+			if cell.Type == "tableHeader" {
+				separator = "||"
+			}
+			var parts []string
+			for _, child := range cell.Content {
+				parts = append(parts, r.renderInline(inlineContent(child)))
+			}
+			text := strings.ReplaceAll(strings.Join(parts, " "), "\n", " ")
+			if text == "" {
+				text = " "
+			}
+			line.WriteString(separator + text)
+		}
+		line.WriteString(separator)
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderInline renders inline nodes, wrapping each text node in its marks
+func (r *wikiRenderer) renderInline(nodes []*ADFNode) string {
+	var out strings.Builder
+
+	for _, node := range nodes {
+		switch node.Type {
+		case "text":
+			if node.Text == "" {
+				continue
+			}
+			out.WriteString(renderWikiText(node))
+		case "hardBreak":
+			out.WriteString("\n")
+		case "mention":
+			out.WriteString("[~" + attrString(node.Attrs, "id") + "]")
+		case "emoji":
+			if text := attrString(node.Attrs, "text"); text != "" {
+				out.WriteString(text)
+			} else {
+				out.WriteString(attrString(node.Attrs, "shortName"))
+			}
+		case "inlineCard", "blockCard":
+			out.WriteString("[" + attrString(node.Attrs, "url") + "]")
+		case "status":
+			out.WriteString("{{" + attrString(node.Attrs, "text") + "}}")
+		case "date":
+			out.WriteString(renderDate(attrString(node.Attrs, "timestamp")))
+		default:
+			out.WriteString(r.renderInline(node.Content))
+		}
+	}
+
+	return out.String()
+}
+
+// renderWikiText renders a text node with its marks, keeping surrounding
+// spaces outside the delimiters as wiki markup requires
+func renderWikiText(node *ADFNode) string {
+	text := node.Text
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+
+	if hasMark(node, "code") {
+		trimmed = "{{" + trimmed + "}}"
+	} else {
+		trimmed = escapeWiki(trimmed)
+	}
+
+	marks := stackMarks(node)
+	for i := len(marks) - 1; i >= 0; i-- {
+		switch marks[i].Type {
+		case "strong":
+			trimmed = "*" + trimmed + "*"
+		case "em":
+			trimmed = "_" + trimmed + "_"
+		case "strike":
+			trimmed = "-" + trimmed + "-"
+		case "underline":
+			trimmed = "+" + trimmed + "+"
+		case "link":
+			href := attrString(marks[i].Attrs, "href")
+			if trimmed == escapeWiki(href) {
+				trimmed = "[" + href + "]"
+			} else {
+				trimmed = "[" + trimmed + "|" + href + "]"
+			}
+		}
+	}
+
+	return leading + trimmed + trailing
+}
+
+// renderWikiMedia renders a media node as a wiki image
+func renderWikiMedia(node *ADFNode) string {
+	if attrString(node.Attrs, "type") == "external" {
+		return "!" + attrString(node.Attrs, "url") + "!"
+	}
+	if alt := attrString(node.Attrs, "alt"); alt != "" {
+		return "!" + alt + "!"
+	}
+	return "!" + attrString(node.Attrs, "id") + "!"
+}
+
+// escapeWiki escapes characters that would otherwise start wiki markup
+func escapeWiki(text string) string {
+	var out strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte("{}[]|", c) >= 0:
+			out.WriteByte('\\')
+		case c == '!' && i+1 < len(text) && text[i+1] != ' ' && strings.IndexByte(text[i+1:], '!') >= 0:
+			out.WriteByte('\\')
+		case wikiMarks[c] != "" && wikiLeftBoundary(text, i) && findWikiClose(text, i+1, c) > i+1:
+			out.WriteByte('\\')
+		}
+		out.WriteByte(c)
+	}
+	return out.String()
+}
+
+// findWikiClose finds the closing delimiter of a wiki mark starting at from
+func findWikiClose(text string, from int, delim byte) int {
+	if from >= len(text) || text[from] == ' ' {
+		return -1
+	}
+	for j := from; j < len(text); j++ {
+		if text[j] == '\n' {
+			return -1
+		}
+		if text[j] == delim && text[j-1] != ' ' && text[j-1] != '\\' && (j+1 == len(text) || !isAlphaNum(text[j+1])) {
+			return j
+		}
+	}
+	return -1
+}
+
+// wikiLeftBoundary reports whether a mark may open at text[i]
+func wikiLeftBoundary(text string, i int) bool {
+	return i == 0 || !isAlphaNum(text[i-1])
+}
+
+// splitWikiRow splits a table row on its cell separator, ignoring separators inside links
+func splitWikiRow(line, separator string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, separator), separator)
+
+	var cells []string
+	depth := 0
+	last := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '|':
+			if depth == 0 && strings.HasPrefix(line[i:], separator) {
+				cells = append(cells, line[last:i])
+				i += len(separator) - 1
+				last = i + 1
+			}
+		}
+	}
+	return append(cells, line[last:])
+}
+
+// startsWikiBlock reports whether a trimmed line begins a new block
+func startsWikiBlock(trimmed string) bool {
+	return wikiHeadingPattern.MatchString(trimmed) ||
+		wikiCodePattern.MatchString(trimmed) ||
+		wikiPanelPattern.MatchString(trimmed) ||
+		wikiListPattern.MatchString(trimmed) ||
+		wikiRulePattern.MatchString(trimmed) ||
+		strings.HasPrefix(trimmed, "|") ||
+		strings.HasPrefix(trimmed, "bq. ")
+}
+
+// wikiListType returns the ADF list type for a wiki list marker
+func wikiListType(marker byte) string {
+	if marker == '#' {
+		return "orderedList"
+	}
+	return "bulletList"
+}
+
+// wikiMediaSource converts a wiki image reference into a markdown image source
+func wikiMediaSource(src string) string {
+	if strings.Contains(src, "://") || strings.HasPrefix(src, mediaRefPrefix) {
+		return src
+	}
+	return mediaRefPrefix + src
+}
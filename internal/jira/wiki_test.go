@@ -0,0 +1,125 @@
+package jira
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWikiToADF(t *testing.T) {
+	tests := []struct {
+		name     string
+		wiki     string
+		expected string
+	}{
+		{
+			name:     "heading and marks",
+			wiki:     "h2. Title\n\nSome *bold*, _italic_, -gone- and {{code}}",
+			expected: "## Title\n\nSome **bold**, _italic_, ~~gone~~ and `code`",
+		},
+		{
+			name:     "link and mention",
+			wiki:     "See [the docs|https://example.com] and ask [~jdoe]",
+			expected: "See [the docs](https://example.com) and ask [@jdoe](accountid:jdoe)",
+		},
+		{
+			name:     "nested lists",
+			wiki:     "* one\n** nested\n# first\n# second",
+			expected: "- one\n  - nested\n\n1. first\n2. second",
+		},
+		{
+			name:     "code block keeps markup verbatim",
+			wiki:     "{code:go}\nfmt.Println(\"*x*\")\n{code}",
+			expected: "```go\nfmt.Println(\"*x*\")\n```",
+		},
+		{
+			name:     "table",
+			wiki:     "||Name||Value||\n|a|[b|https://example.com]|",
+			expected: "| Name | Value |\n| --- | --- |\n| a | [b](https://example.com) |",
+		},
+		{
+			name:     "quote",
+			wiki:     "{quote}\nquoted text\n{quote}",
+			expected: "> quoted text",
+		},
+		{
+			name:     "forced line break",
+			wiki:     "line one\\\\line two",
+			expected: "line one\nline two",
+		},
+		{
+			name:     "escaped delimiters",
+			wiki:     "not \\*bold\\*",
+			expected: "not \\*bold\\*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ADFToMarkdown(WikiToADF(tt.wiki)); got != tt.expected {
+				t.Errorf("WikiToADF(%q)\ngot:\n%s\nwant:\n%s", tt.wiki, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWikiToADFEmpty(t *testing.T) {
+	if doc := WikiToADF("  \n"); doc != nil {
+		t.Errorf("Expected nil document for blank wiki markup, got %+v", doc)
+	}
+}
+
+func TestADFToWiki(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "heading and marks",
+			markdown: "## Title\n\nSome **bold** and `code`",
+			expected: "h2. Title\n\nSome *bold* and {{code}}",
+		},
+		{
+			name:     "lists",
+			markdown: "- one\n  - nested\n\n1. first",
+			expected: "* one\n** nested\n\n# first",
+		},
+		{
+			name:     "link",
+			markdown: "[docs](https://example.com)",
+			expected: "[docs|https://example.com]",
+		},
+		{
+			name:     "literal wiki characters are escaped",
+			markdown: "use {braces} and a|pipe",
+			expected: "use \\{braces\\} and a\\|pipe",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ADFToWiki(MarkdownToADF(tt.markdown)); got != tt.expected {
+				t.Errorf("ADFToWiki(%q)\ngot:\n%s\nwant:\n%s", tt.markdown, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWikiFixtureRoundTrip(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "adf", "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(filepath.Base(fixture), func(t *testing.T) {
+			doc := loadADFFixture(t, fixture)
+
+			wiki := ADFToWiki(doc)
+			again := ADFToWiki(WikiToADF(wiki))
+			if again != wiki {
+				t.Errorf("Wiki markup not stable\nfirst:\n%s\n\nsecond:\n%s", wiki, again)
+			}
+		})
+	}
+}
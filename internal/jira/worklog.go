@@ -0,0 +1,125 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// MinimumWorklog is the shortest time Jira accepts in a worklog
+const MinimumWorklog = time.Minute
+
+// AddWorklog logs time on an issue
+func (c *Client) AddWorklog(ctx context.Context, issueKey string, request *JiraAddWorklogRequest) (*JiraWorklog, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	endpoint := fmt.Sprintf("/issue/%s/worklog", issueKey)
+	resp, err := c.doRequest(ctx, "POST", endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		return nil, c.parseErrorResponse(resp)
+	}
+
+	var worklog JiraWorklog
+	if err := json.NewDecoder(resp.Body).Decode(&worklog); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	return &worklog, nil
+}
+
+// GetWorklogs fetches all worklogs on an issue
+func (c *Client) GetWorklogs(ctx context.Context, issueKey string) ([]JiraWorklog, error) {
+	var worklogs []JiraWorklog
+
+	for {
+		endpoint := fmt.Sprintf("/issue/%s/worklog?startAt=%d", issueKey, len(worklogs))
+		resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != 200 {
+			err := c.parseErrorResponse(resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var page JiraWorklogsResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+
+		worklogs = append(worklogs, page.Worklogs...)
+		if len(page.Worklogs) == 0 || len(worklogs) >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
+
+// AddWorklog pushes a worklog to Jira and returns it as recorded there.
+// Time spent is rounded to the minute, and never below MinimumWorklog.
+func (ts *TicketService) AddWorklog(ctx context.Context, worklog *types.Worklog) (*types.Worklog, error) {
+	timeSpent := worklog.TimeSpent.Round(time.Minute)
+	if timeSpent < MinimumWorklog {
+		timeSpent = MinimumWorklog
+	}
+
+	request := &JiraAddWorklogRequest{
+		Started:          JiraTime{worklog.Started},
+		TimeSpentSeconds: int(timeSpent / time.Second),
+	}
+	if strings.TrimSpace(worklog.Comment) != "" {
+		request.Comment = ts.client.richText(ctx, worklog.Comment)
+	}
+
+	created, err := ts.client.AddWorklog(ctx, worklog.TicketKey, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log time on %s: %v", worklog.TicketKey, err)
+	}
+
+	return convertWorklog(worklog.TicketKey, created), nil
+}
+
+// GetWorklogs fetches the worklogs on a ticket
+func (ts *TicketService) GetWorklogs(ctx context.Context, ticketKey string) ([]*types.Worklog, error) {
+	jiraWorklogs, err := ts.client.GetWorklogs(ctx, ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch worklogs for %s: %v", ticketKey, err)
+	}
+
+	worklogs := make([]*types.Worklog, 0, len(jiraWorklogs))
+	for i := range jiraWorklogs {
+		worklogs = append(worklogs, convertWorklog(ticketKey, &jiraWorklogs[i]))
+	}
+
+	return worklogs, nil
+}
+
+// convertWorklog converts a Jira worklog to our internal format
+func convertWorklog(ticketKey string, jiraWorklog *JiraWorklog) *types.Worklog {
+	worklog := &types.Worklog{
+		ID:        jiraWorklog.ID,
+		TicketKey: ticketKey,
+		Author:    jiraWorklog.Author.DisplayName,
+		Started:   jiraWorklog.Started.Time,
+		TimeSpent: time.Duration(jiraWorklog.TimeSpentSeconds) * time.Second,
+	}
+	if jiraWorklog.Comment != nil {
+		worklog.Comment = jiraWorklog.Comment.Markdown()
+	}
+	return worklog
+}
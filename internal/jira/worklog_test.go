@@ -0,0 +1,127 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestJiraTime(t *testing.T) {
+	var parsed struct {
+		Jira    JiraTime `json:"jira"`
+		RFC3339 JiraTime `json:"rfc3339"`
+	}
+	err := json.Unmarshal([]byte(`{"jira": "2024-03-01T09:30:00.000+0100", "rfc3339": "2024-03-01T08:30:00Z"}`), &parsed)
+	if err != nil {
+		t.Fatalf("Failed to decode times: %v", err)
+	}
+	if !parsed.Jira.Equal(parsed.RFC3339.Time) {
+		t.Errorf("Expected equal times, got %v and %v", parsed.Jira, parsed.RFC3339)
+	}
+
+	started := JiraTime{time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)}
+	data, err := json.Marshal(started)
+	if err != nil {
+		t.Fatalf("Failed to encode time: %v", err)
+	}
+	if string(data) != `"2024-03-01T08:30:00.000+0000"` {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+}
+
+func TestAddWorklog(t *testing.T) {
+	var sent map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/3/issue/TEST-1/worklog" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "100", "author": {"displayName": "Dev"}, "started": "2024-03-01T08:30:00.000+0000", "timeSpentSeconds": 5400}`))
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	worklog, err := service.AddWorklog(context.Background(), &types.Worklog{
+		TicketKey: "TEST-1",
+		Started:   time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC),
+		TimeSpent: 89*time.Minute + 40*time.Second,
+		Comment:   "Pairing on **auth**",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if string(sent["timeSpentSeconds"]) != "5400" {
+		t.Errorf("Expected time rounded to 5400 seconds, got %s", sent["timeSpentSeconds"])
+	}
+	if string(sent["started"]) != `"2024-03-01T08:30:00.000+0000"` {
+		t.Errorf("Unexpected started: %s", sent["started"])
+	}
+	var comment ADFNode
+	if err := json.Unmarshal(sent["comment"], &comment); err != nil || comment.Type != "doc" {
+		t.Errorf("Expected ADF comment, got %s", sent["comment"])
+	}
+
+	if worklog.ID != "100" || worklog.Author != "Dev" || worklog.TimeSpent != 90*time.Minute {
+		t.Errorf("Unexpected worklog: %+v", worklog)
+	}
+}
+
+func TestAddWorklogMinimum(t *testing.T) {
+	var sent JiraAddWorklogRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "101", "timeSpentSeconds": 60}`))
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	_, err := service.AddWorklog(context.Background(), &types.Worklog{
+		TicketKey: "TEST-1",
+		Started:   time.Now(),
+		TimeSpent: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sent.TimeSpentSeconds != 60 || sent.Comment != nil {
+		t.Errorf("Expected a one minute worklog without comment, got %+v", sent)
+	}
+}
+
+func TestGetWorklogsPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/TEST-1/worklog" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		startAt := r.URL.Query().Get("startAt")
+		fmt.Fprintf(w, `{"startAt": %s, "total": 3, "worklogs": [{"id": "w%s", "timeSpentSeconds": 600}`, startAt, startAt)
+		if startAt == "0" {
+			fmt.Fprint(w, `, {"id": "w1", "timeSpentSeconds": 600}`)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+	defer server.Close()
+
+	service := NewTicketService(NewClient(&types.JiraConfig{URL: server.URL}))
+
+	worklogs, err := service.GetWorklogs(context.Background(), "TEST-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(worklogs) != 3 || worklogs[2].ID != "w2" || worklogs[0].TicketKey != "TEST-1" {
+		t.Errorf("Unexpected worklogs: %+v", worklogs)
+	}
+}
@@ -0,0 +1,218 @@
+// Package network builds the HTTP transport shared by every outbound client,
+// from the network section of the config
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// Timeouts used when the config does not set them
+const (
+	DefaultConnectTimeout  = 10 * time.Second
+	DefaultResponseTimeout = 30 * time.Second
+	DefaultRequestTimeout  = 2 * time.Minute
+)
+
+// insecureWarning is printed once when certificate verification is off
+const insecureWarning = `
+!!! WARNING: TLS certificate verification is DISABLED (network.insecure_skip_verify).
+!!! Anyone on the network path can read and change traffic to Jira, including
+!!! your credentials. Configure network.ca_bundle instead and turn this off.
+
+`
+
+var (
+	// warningOutput receives the insecure-skip-verify warning; replaced in tests
+	warningOutput io.Writer = os.Stderr
+	warnOnce      sync.Once
+)
+
+// NewTransport builds an HTTP transport with the configured proxy, CA
+// bundle, client certificate and timeouts. A nil config gives the defaults:
+// the proxy from the environment, the system's CAs and jit's timeouts.
+func NewTransport(config *types.NetworkConfig) (*http.Transport, error) {
+	if config == nil {
+		config = &types.NetworkConfig{}
+	}
+
+	connectTimeout := config.ConnectTimeout
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeout
+	}
+	responseTimeout := config.ResponseTimeout
+	if responseTimeout <= 0 {
+		responseTimeout = DefaultResponseTimeout
+	}
+
+	proxy, err := proxyFunc(config)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.DialContext = (&net.Dialer{Timeout: connectTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = connectTimeout
+	transport.ResponseHeaderTimeout = responseTimeout
+	transport.TLSClientConfig = tlsConfig
+
+	if config.InsecureSkipVerify {
+		warnOnce.Do(func() {
+			fmt.Fprint(warningOutput, insecureWarning)
+		})
+	}
+
+	return transport, nil
+}
+
+// RequestTimeout returns how long each attempt at a request may take,
+// reading the response included. Transports cannot enforce this, so
+// clients apply it to their requests.
+func RequestTimeout(config *types.NetworkConfig) time.Duration {
+	if config == nil || config.RequestTimeout <= 0 {
+		return DefaultRequestTimeout
+	}
+	return config.RequestTimeout
+}
+
+// DefaultTransport returns the transport NewTransport builds without a
+// config, for clients created without one
+func DefaultTransport() *http.Transport {
+	transport, err := NewTransport(nil)
+	if err != nil {
+		// The defaults read no files and parse no URLs
+		panic(err)
+	}
+	return transport
+}
+
+// proxyFunc chooses the proxy for each request: proxy_url when set,
+// otherwise the environment's, skipping hosts on the no_proxy list
+func proxyFunc(config *types.NetworkConfig) (func(*http.Request) (*url.URL, error), error) {
+	proxy := http.ProxyFromEnvironment
+	if config.ProxyURL != "" {
+		raw := config.ProxyURL
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		proxyURL, err := url.Parse(raw)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid network.proxy_url %q", config.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(config.NoProxy) == 0 {
+		return proxy, nil
+	}
+
+	noProxy := config.NoProxy
+	return func(req *http.Request) (*url.URL, error) {
+		if BypassProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		return proxy(req)
+	}, nil
+}
+
+// BypassProxy reports whether host is reached directly under a no-proxy
+// list. Entries are "*", host names, domains matching their subdomains
+// (example.com or .example.com), IP addresses and CIDR ranges; ports on
+// entries are ignored.
+func BypassProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+			continue
+		case entry == "*":
+			return true
+		}
+
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+
+		domain := strings.TrimPrefix(entry, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTLSConfig trusts the CA bundle alongside the system's CAs and presents
+// the client certificate, when configured
+func newTLSConfig(config *types.NetworkConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+
+	if config.CABundle != "" {
+		path := expandHome(config.CABundle)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read network.ca_bundle: %v", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in network.ca_bundle %s", path)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, fmt.Errorf("network.client_cert and network.client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(expandHome(config.ClientCert), expandHome(config.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
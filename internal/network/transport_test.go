@@ -0,0 +1,275 @@
+package network
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// testCert is a certificate and key, parsed and as PEM
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate from parent, or a self-signed CA when
+// parent is nil
+func newTestCert(t *testing.T, parent *testCert, template *x509.Certificate) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// newMTLSServer starts a server whose certificate and required client
+// certificates are issued by a private CA, and writes the CA, a client
+// certificate and its key to dir
+func newMTLSServer(t *testing.T, dir string) *httptest.Server {
+	ca := newTestCert(t, nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Corp CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	})
+	server := newTestCert(t, ca, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "jira.test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	client := newTestCert(t, ca, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "jit"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	for name, data := range map[string][]byte{"ca.pem": ca.certPEM, "client.pem": client.certPEM, "client-key.pem": client.keyPEM} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	serverCert, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load server certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello " + r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	ts.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	ts.StartTLS()
+	return ts
+}
+
+func TestNewTransportWithCABundleAndClientCert(t *testing.T) {
+	dir := t.TempDir()
+	server := newMTLSServer(t, dir)
+	defer server.Close()
+
+	get := func(config *types.NetworkConfig) (string, error) {
+		transport, err := NewTransport(config)
+		if err != nil {
+			t.Fatalf("NewTransport failed: %v", err)
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return body.String(), nil
+	}
+
+	body, err := get(&types.NetworkConfig{
+		CABundle:   filepath.Join(dir, "ca.pem"),
+		ClientCert: filepath.Join(dir, "client.pem"),
+		ClientKey:  filepath.Join(dir, "client-key.pem"),
+	})
+	if err != nil {
+		t.Fatalf("Expected the mTLS request to succeed, got %v", err)
+	}
+	if body != "hello jit" {
+		t.Errorf("Expected the server to see the client certificate, got %q", body)
+	}
+
+	// Without the CA bundle the server's certificate is not trusted
+	if _, err := get(&types.NetworkConfig{ClientCert: filepath.Join(dir, "client.pem"), ClientKey: filepath.Join(dir, "client-key.pem")}); err == nil {
+		t.Error("Expected an untrusted server certificate to fail")
+	}
+
+	// Without the client certificate the server refuses the handshake
+	if _, err := get(&types.NetworkConfig{CABundle: filepath.Join(dir, "ca.pem")}); err == nil {
+		t.Error("Expected the request without a client certificate to fail")
+	}
+}
+
+func TestNewTransportInsecureSkipVerifyWarns(t *testing.T) {
+	var warning bytes.Buffer
+	warningOutput, warnOnce = &warning, sync.Once{}
+	defer func() { warningOutput = os.Stderr }()
+
+	transport, err := NewTransport(&types.NetworkConfig{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("Expected certificate verification to be off")
+	}
+	if !strings.Contains(warning.String(), "verification is DISABLED") {
+		t.Errorf("Expected a loud warning, got %q", warning.String())
+	}
+
+	// The warning is printed once per process
+	warning.Reset()
+	NewTransport(&types.NetworkConfig{InsecureSkipVerify: true})
+	if warning.Len() != 0 {
+		t.Errorf("Expected a single warning, got another: %q", warning.String())
+	}
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	transport, err := NewTransport(&types.NetworkConfig{
+		ProxyURL: "proxy.corp.example:3128",
+		NoProxy:  []string{".corp.example", "10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+
+	tests := []struct {
+		url   string
+		proxy string
+	}{
+		{"https://example.atlassian.net/rest/api/3/myself", "http://proxy.corp.example:3128"},
+		{"https://jira.corp.example/rest/api/2/myself", ""},
+		{"https://10.1.2.3/rest/api/2/myself", ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest("GET", tt.url, nil)
+		proxy, err := transport.Proxy(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.url, err)
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != tt.proxy {
+			t.Errorf("%s: expected proxy %q, got %q", tt.url, tt.proxy, got)
+		}
+	}
+}
+
+func TestNewTransportDefaults(t *testing.T) {
+	transport, err := NewTransport(nil)
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if transport.ResponseHeaderTimeout != DefaultResponseTimeout || transport.TLSHandshakeTimeout != DefaultConnectTimeout {
+		t.Errorf("Expected default timeouts, got %v and %v", transport.ResponseHeaderTimeout, transport.TLSHandshakeTimeout)
+	}
+
+	transport, _ = NewTransport(&types.NetworkConfig{ResponseTimeout: time.Minute})
+	if transport.ResponseHeaderTimeout != time.Minute {
+		t.Errorf("Expected configured response timeout, got %v", transport.ResponseHeaderTimeout)
+	}
+
+	if RequestTimeout(nil) != DefaultRequestTimeout || RequestTimeout(&types.NetworkConfig{RequestTimeout: time.Minute}) != time.Minute {
+		t.Error("Expected the request timeout to default unless configured")
+	}
+}
+
+func TestNewTransportRejectsBadConfig(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "bundle.txt")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0600)
+
+	tests := map[string]*types.NetworkConfig{
+		"missing CA bundle":   {CABundle: filepath.Join(dir, "missing.pem")},
+		"CA bundle not PEM":   {CABundle: notPEM},
+		"cert without key":    {ClientCert: filepath.Join(dir, "client.pem")},
+		"unreadable key pair": {ClientCert: notPEM, ClientKey: notPEM},
+		"proxy without host":  {ProxyURL: "http://"},
+	}
+	for name, config := range tests {
+		if _, err := NewTransport(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{"localhost", ".corp.example", "atlassian.net:443", "192.168.0.0/16", " "}
+
+	tests := map[string]bool{
+		"localhost":               true,
+		"jira.corp.example":       true,
+		"corp.example":            true,
+		"notcorp.example":         false,
+		"acme.atlassian.net":      true,
+		"192.168.1.20":            true,
+		"192.169.1.20":            false,
+		"api.openai.com":          false,
+		"JIRA.CORP.EXAMPLE.":      true,
+		"example.atlassian.net.x": false,
+	}
+	for host, want := range tests {
+		if got := BypassProxy(host, noProxy); got != want {
+			t.Errorf("%s: expected %v, got %v", host, want, got)
+		}
+	}
+
+	if !BypassProxy("anything.example", []string{"*"}) {
+		t.Error("Expected * to bypass the proxy for every host")
+	}
+}
@@ -10,7 +10,8 @@ import (
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
 
-// SaveTicket saves a ticket to JSON file
+// SaveTicket saves a ticket to JSON file, keeping the parent and children
+// links of the tickets around it consistent with it
 func (s *JSONStorage) SaveTicket(ticket *types.Ticket) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -23,20 +24,57 @@ func (s *JSONStorage) SaveTicket(ticket *types.Ticket) error {
 		return fmt.Errorf("ticket key cannot be empty")
 	}
 
-	path := s.GetTicketPath(ticket.Key)
+	previous, _ := s.readTicket(ticket.Key)
+	return s.saveTicket(ticket, previous)
+}
 
-	// Marshal ticket to JSON
-	data, err := json.MarshalIndent(ticket, "", "  ")
+// UpdateTicket replaces a stored ticket with what update makes of it, in one
+// step no other save can interleave with. update is given nil when the
+// ticket is not stored, and returns nil to leave the store unchanged.
+func (s *JSONStorage) UpdateTicket(key string, update func(current *types.Ticket) (*types.Ticket, error)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == "" {
+		return fmt.Errorf("ticket key cannot be empty")
+	}
+
+	current, err := s.readTicket(key)
 	if err != nil {
-		return fmt.Errorf("failed to marshal ticket: %v", err)
+		return err
 	}
 
-	// Write atomically
-	if err := s.atomicWrite(path, data); err != nil {
-		return fmt.Errorf("failed to write ticket: %v", err)
+	ticket, err := update(current)
+	if err != nil || ticket == nil {
+		return err
+	}
+	if ticket.Key != key {
+		return fmt.Errorf("cannot update %s with ticket %s", key, ticket.Key)
 	}
 
-	return nil
+	// Reread so the relationship merge sees the stored ticket, not the one
+	// update may have changed in place
+	previous, _ := s.readTicket(key)
+	return s.saveTicket(ticket, previous)
+}
+
+// saveTicket writes a ticket over previous, its stored version if any, and
+// links the tickets around it; callers must hold s.mu
+func (s *JSONStorage) saveTicket(ticket, previous *types.Ticket) error {
+	// Children tracked earlier stay children while they still point here
+	if previous != nil {
+		for _, childKey := range previous.Relationships.Children {
+			if child, _ := s.readTicket(childKey); child != nil && child.Relationships.ParentKey == ticket.Key {
+				ticket.Relationships.Children = appendKey(ticket.Relationships.Children, childKey)
+			}
+		}
+	}
+
+	if err := s.writeTicket(ticket); err != nil {
+		return err
+	}
+
+	return s.linkRelationships(ticket, previous)
 }
 
 // LoadTicket loads a ticket from JSON file
@@ -48,29 +86,18 @@ func (s *JSONStorage) LoadTicket(key string) (*types.Ticket, error) {
 		return nil, fmt.Errorf("ticket key cannot be empty")
 	}
 
-	path := s.GetTicketPath(key)
-
-	// Check if file exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, fmt.Errorf("ticket %s not found", key)
-	}
-
-	// Read file
-	data, err := os.ReadFile(path)
+	ticket, err := s.readTicket(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read ticket: %v", err)
+		return nil, err
 	}
-
-	// Unmarshal JSON
-	var ticket types.Ticket
-	if err := json.Unmarshal(data, &ticket); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal ticket: %v", err)
+	if ticket == nil {
+		return nil, fmt.Errorf("ticket %s not found", key)
 	}
 
-	return &ticket, nil
+	return ticket, nil
 }
 
-// DeleteTicket deletes a ticket file
+// DeleteTicket deletes a ticket file and removes it from its parent's children
 func (s *JSONStorage) DeleteTicket(key string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -86,14 +113,146 @@ func (s *JSONStorage) DeleteTicket(key string) error {
 		return fmt.Errorf("ticket %s not found", key)
 	}
 
+	ticket, _ := s.readTicket(key)
+
 	// Delete file
 	if err := os.Remove(path); err != nil {
 		return fmt.Errorf("failed to delete ticket: %v", err)
 	}
 
+	// Comments go with the ticket
+	if err := os.Remove(s.GetCommentsPath(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete comments: %v", err)
+	}
+
+	if ticket != nil && ticket.Relationships.ParentKey != "" {
+		return s.removeChild(ticket.Relationships.ParentKey, key)
+	}
+
 	return nil
 }
 
+// readTicket reads a ticket, returning nil when it is not stored; callers
+// must hold s.mu
+func (s *JSONStorage) readTicket(key string) (*types.Ticket, error) {
+	data, err := os.ReadFile(s.GetTicketPath(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ticket: %v", err)
+	}
+
+	var ticket types.Ticket
+	if err := json.Unmarshal(data, &ticket); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ticket: %v", err)
+	}
+
+	return &ticket, nil
+}
+
+// writeTicket writes a ticket; callers must hold s.mu
+func (s *JSONStorage) writeTicket(ticket *types.Ticket) error {
+	// Marshal ticket to JSON
+	data, err := json.MarshalIndent(ticket, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticket: %v", err)
+	}
+
+	// Write atomically
+	if err := s.atomicWrite(s.GetTicketPath(ticket.Key), data); err != nil {
+		return fmt.Errorf("failed to write ticket: %v", err)
+	}
+
+	return nil
+}
+
+// linkRelationships updates the stored tickets around a saved ticket: its
+// parent lists it as a child, a parent it has left no longer does, and its
+// children point back at it. Callers must hold s.mu.
+func (s *JSONStorage) linkRelationships(ticket, previous *types.Ticket) error {
+	parentKey := ticket.Relationships.ParentKey
+
+	if parentKey != "" && parentKey != ticket.Key {
+		parent, err := s.readTicket(parentKey)
+		if err != nil {
+			return err
+		}
+		if parent != nil && !containsKey(parent.Relationships.Children, ticket.Key) {
+			parent.Relationships.Children = appendKey(parent.Relationships.Children, ticket.Key)
+			if err := s.writeTicket(parent); err != nil {
+				return err
+			}
+		}
+	}
+
+	if previous != nil && previous.Relationships.ParentKey != "" && previous.Relationships.ParentKey != parentKey {
+		if err := s.removeChild(previous.Relationships.ParentKey, ticket.Key); err != nil {
+			return err
+		}
+	}
+
+	for _, childKey := range ticket.Relationships.Children {
+		child, err := s.readTicket(childKey)
+		if err != nil {
+			return err
+		}
+		if child == nil || child.Relationships.ParentKey == ticket.Key {
+			continue
+		}
+
+		oldParent := child.Relationships.ParentKey
+		child.Relationships.ParentKey = ticket.Key
+		if err := s.writeTicket(child); err != nil {
+			return err
+		}
+		if oldParent != "" && oldParent != ticket.Key {
+			if err := s.removeChild(oldParent, childKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeChild removes a child from a stored parent's children; callers must
+// hold s.mu
+func (s *JSONStorage) removeChild(parentKey, childKey string) error {
+	parent, err := s.readTicket(parentKey)
+	if err != nil || parent == nil || !containsKey(parent.Relationships.Children, childKey) {
+		return err
+	}
+
+	children := []string{}
+	for _, key := range parent.Relationships.Children {
+		if key != childKey {
+			children = append(children, key)
+		}
+	}
+	parent.Relationships.Children = children
+
+	return s.writeTicket(parent)
+}
+
+// containsKey reports whether keys holds key
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// appendKey appends key unless keys already holds it
+func appendKey(keys []string, key string) []string {
+	if containsKey(keys, key) {
+		return keys
+	}
+	return append(keys, key)
+}
+
 // ListTickets returns a list of all ticket keys
 func (s *JSONStorage) ListTickets() ([]string, error) {
 	s.mu.RLock()
@@ -124,6 +283,58 @@ func (s *JSONStorage) ListTickets() ([]string, error) {
 	return keys, nil
 }
 
+// SaveComments replaces the comments stored for a ticket
+func (s *JSONStorage) SaveComments(key string, comments []*types.Comment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == "" {
+		return fmt.Errorf("ticket key cannot be empty")
+	}
+
+	path := s.GetCommentsPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create comments directory: %v", err)
+	}
+
+	// Marshal comments to JSON
+	data, err := json.MarshalIndent(comments, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal comments for %s: %v", key, err)
+	}
+
+	// Write atomically
+	if err := s.atomicWrite(path, data); err != nil {
+		return fmt.Errorf("failed to write comments for %s: %v", key, err)
+	}
+
+	return nil
+}
+
+// LoadComments loads the comments stored for a ticket, or nil when none
+// have been fetched
+func (s *JSONStorage) LoadComments(key string) ([]*types.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Read file
+	data, err := os.ReadFile(s.GetCommentsPath(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comments for %s: %v", key, err)
+	}
+
+	// Unmarshal JSON
+	var comments []*types.Comment
+	if err := json.Unmarshal(data, &comments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal comments for %s: %v", key, err)
+	}
+
+	return comments, nil
+}
+
 // SaveContext saves context to JSON file
 func (s *JSONStorage) SaveContext(context *types.Context) error {
 	s.mu.Lock()
@@ -176,3 +387,247 @@ func (s *JSONStorage) LoadContext() (*types.Context, error) {
 
 	return &context, nil
 }
+
+// SaveTimer saves the running timer to JSON file
+func (s *JSONStorage) SaveTimer(timer *types.Timer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if timer == nil {
+		return fmt.Errorf("timer cannot be nil")
+	}
+
+	// Marshal timer to JSON
+	data, err := json.MarshalIndent(timer, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timer: %v", err)
+	}
+
+	// Write atomically
+	if err := s.atomicWrite(s.GetTimerPath(), data); err != nil {
+		return fmt.Errorf("failed to write timer: %v", err)
+	}
+
+	return nil
+}
+
+// LoadTimer loads the running timer, returning nil if none is running
+func (s *JSONStorage) LoadTimer() (*types.Timer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Read file
+	data, err := os.ReadFile(s.GetTimerPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timer: %v", err)
+	}
+
+	// Unmarshal JSON
+	var timer types.Timer
+	if err := json.Unmarshal(data, &timer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal timer: %v", err)
+	}
+
+	return &timer, nil
+}
+
+// DeleteTimer removes the running timer. Deleting a missing timer is not an error.
+func (s *JSONStorage) DeleteTimer() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.GetTimerPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete timer: %v", err)
+	}
+
+	return nil
+}
+
+// SaveWorklogQueue replaces the worklogs waiting to be pushed to Jira
+func (s *JSONStorage) SaveWorklogQueue(worklogs []*types.Worklog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.GetWorklogQueuePath()
+
+	// An empty queue leaves no file behind
+	if len(worklogs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear worklog queue: %v", err)
+		}
+		return nil
+	}
+
+	// Marshal worklogs to JSON
+	data, err := json.MarshalIndent(worklogs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal worklog queue: %v", err)
+	}
+
+	// Write atomically
+	if err := s.atomicWrite(path, data); err != nil {
+		return fmt.Errorf("failed to write worklog queue: %v", err)
+	}
+
+	return nil
+}
+
+// LoadWorklogQueue loads the worklogs waiting to be pushed to Jira
+func (s *JSONStorage) LoadWorklogQueue() ([]*types.Worklog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Read file
+	data, err := os.ReadFile(s.GetWorklogQueuePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worklog queue: %v", err)
+	}
+
+	// Unmarshal JSON
+	var worklogs []*types.Worklog
+	if err := json.Unmarshal(data, &worklogs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal worklog queue: %v", err)
+	}
+
+	return worklogs, nil
+}
+
+// SaveCache saves a named cache entry to JSON file
+func (s *JSONStorage) SaveCache(name string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("cache name cannot be empty")
+	}
+
+	path := s.GetCachePath(name)
+
+	// Ensure the cache directory exists, it may predate this storage layout
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+
+	// Marshal value to JSON
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache %s: %v", name, err)
+	}
+
+	// Write atomically
+	if err := s.atomicWrite(path, data); err != nil {
+		return fmt.Errorf("failed to write cache %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// LoadCache loads a named cache entry into value. It reports false without
+// an error when the entry has never been written.
+func (s *JSONStorage) LoadCache(name string, value interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if name == "" {
+		return false, fmt.Errorf("cache name cannot be empty")
+	}
+
+	// Read file
+	data, err := os.ReadFile(s.GetCachePath(name))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache %s: %v", name, err)
+	}
+
+	// Unmarshal JSON
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to unmarshal cache %s: %v", name, err)
+	}
+
+	return true, nil
+}
+
+// SaveCredentials saves a named secret, readable only by the current user
+func (s *JSONStorage) SaveCredentials(name string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("credentials name cannot be empty")
+	}
+
+	path := s.GetCredentialsPath(name)
+
+	// Keep the directory private as well as the file
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %v", err)
+	}
+
+	// Marshal value to JSON
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials %s: %v", name, err)
+	}
+
+	// Write atomically; temp files are created with owner-only permissions
+	if err := s.atomicWrite(path, data); err != nil {
+		return fmt.Errorf("failed to write credentials %s: %v", name, err)
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to restrict credentials %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// LoadCredentials loads a named secret into value. It reports false without
+// an error when no secret has been saved.
+func (s *JSONStorage) LoadCredentials(name string, value interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if name == "" {
+		return false, fmt.Errorf("credentials name cannot be empty")
+	}
+
+	// Read file
+	data, err := os.ReadFile(s.GetCredentialsPath(name))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read credentials %s: %v", name, err)
+	}
+
+	// Unmarshal JSON
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to unmarshal credentials %s: %v", name, err)
+	}
+
+	return true, nil
+}
+
+// DeleteCredentials removes a named secret. Deleting a missing secret is not an error.
+func (s *JSONStorage) DeleteCredentials(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("credentials name cannot be empty")
+	}
+
+	if err := os.Remove(s.GetCredentialsPath(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete credentials %s: %v", name, err)
+	}
+
+	return nil
+}
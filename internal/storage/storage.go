@@ -16,11 +16,32 @@ type Storage interface {
 	LoadTicket(key string) (*types.Ticket, error)
 	DeleteTicket(key string) error
 	ListTickets() ([]string, error)
+	UpdateTicket(key string, update func(current *types.Ticket) (*types.Ticket, error)) error
+
+	// Comment operations
+	SaveComments(key string, comments []*types.Comment) error
+	LoadComments(key string) ([]*types.Comment, error)
 
 	// Context operations
 	SaveContext(context *types.Context) error
 	LoadContext() (*types.Context, error)
 
+	// Cache operations
+	SaveCache(name string, value interface{}) error
+	LoadCache(name string, value interface{}) (bool, error)
+
+	// Time tracking operations
+	SaveTimer(timer *types.Timer) error
+	LoadTimer() (*types.Timer, error)
+	DeleteTimer() error
+	SaveWorklogQueue(worklogs []*types.Worklog) error
+	LoadWorklogQueue() ([]*types.Worklog, error)
+
+	// Credential operations
+	SaveCredentials(name string, value interface{}) error
+	LoadCredentials(name string, value interface{}) (bool, error)
+	DeleteCredentials(name string) error
+
 	// Utility operations
 	Exists(key string) bool
 	GetTicketPath(key string) string
@@ -53,16 +74,51 @@ func NewJSONStorage(dataDir string) (*JSONStorage, error) {
 	}, nil
 }
 
+// RemoteDataDir returns the data directory of a remote. The default remote
+// uses dataDir itself; named remotes get their own directory under it, so
+// tickets, focus, caches and credentials from different Jira sites never mix.
+func RemoteDataDir(dataDir, remote string) string {
+	if remote == "" || remote == types.DefaultRemoteName {
+		return dataDir
+	}
+	return filepath.Join(dataDir, "remotes", remote)
+}
+
 // GetTicketPath returns the file path for a ticket
 func (s *JSONStorage) GetTicketPath(key string) string {
 	return filepath.Join(s.dataDir, "tickets", key+".json")
 }
 
+// GetCommentsPath returns the file path for a ticket's comments
+func (s *JSONStorage) GetCommentsPath(key string) string {
+	return filepath.Join(s.dataDir, "comments", key+".json")
+}
+
 // GetContextPath returns the file path for context
 func (s *JSONStorage) GetContextPath() string {
 	return filepath.Join(s.dataDir, "context.json")
 }
 
+// GetTimerPath returns the file path for the running timer
+func (s *JSONStorage) GetTimerPath() string {
+	return filepath.Join(s.dataDir, "timer.json")
+}
+
+// GetWorklogQueuePath returns the file path for worklogs waiting to be pushed
+func (s *JSONStorage) GetWorklogQueuePath() string {
+	return filepath.Join(s.dataDir, "worklog_queue.json")
+}
+
+// GetCachePath returns the file path for a named cache entry
+func (s *JSONStorage) GetCachePath(name string) string {
+	return filepath.Join(s.dataDir, "cache", name+".json")
+}
+
+// GetCredentialsPath returns the file path for a named credential entry
+func (s *JSONStorage) GetCredentialsPath(name string) string {
+	return filepath.Join(s.dataDir, "credentials", name+".json")
+}
+
 // Exists checks if a ticket exists
 func (s *JSONStorage) Exists(key string) bool {
 	path := s.GetTicketPath(key)
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/lunchboxsushi/jit/pkg/types"
 )
@@ -30,6 +31,36 @@ func TestNewJSONStorage(t *testing.T) {
 	}
 }
 
+func TestRemoteDataDir(t *testing.T) {
+	dataDir := filepath.Join("data", "jit")
+
+	if got := RemoteDataDir(dataDir, ""); got != dataDir {
+		t.Errorf("Expected no remote to use the data directory, got %s", got)
+	}
+	if got := RemoteDataDir(dataDir, types.DefaultRemoteName); got != dataDir {
+		t.Errorf("Expected the default remote to use the data directory, got %s", got)
+	}
+	if got, want := RemoteDataDir(dataDir, "work"), filepath.Join(dataDir, "remotes", "work"); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	// The same key saved for two remotes stays apart
+	base := t.TempDir()
+	home, _ := NewJSONStorage(RemoteDataDir(base, ""))
+	work, _ := NewJSONStorage(RemoteDataDir(base, "work"))
+	home.SaveTicket(types.NewTicket("PROJ-1", "Home ticket", types.TicketTypeTask))
+	work.SaveTicket(types.NewTicket("PROJ-1", "Work ticket", types.TicketTypeTask))
+
+	loaded, err := home.LoadTicket("PROJ-1")
+	if err != nil || loaded.Title != "Home ticket" {
+		t.Errorf("Expected the default remote's ticket, got %+v (%v)", loaded, err)
+	}
+	loaded, err = work.LoadTicket("PROJ-1")
+	if err != nil || loaded.Title != "Work ticket" {
+		t.Errorf("Expected the work remote's ticket, got %+v (%v)", loaded, err)
+	}
+}
+
 func TestSaveAndLoadTicket(t *testing.T) {
 	tempDir := t.TempDir()
 	storage, err := NewJSONStorage(tempDir)
@@ -129,6 +160,121 @@ func TestDeleteTicket(t *testing.T) {
 	}
 }
 
+func TestUpdateTicket(t *testing.T) {
+	storage, err := NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	// A missing ticket is passed as nil, and returning nil stores nothing
+	err = storage.UpdateTicket("TEST-1", func(current *types.Ticket) (*types.Ticket, error) {
+		if current != nil {
+			t.Errorf("Expected no stored ticket, got %s", current.Key)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to update ticket: %v", err)
+	}
+	if storage.Exists("TEST-1") {
+		t.Fatal("Ticket should not be stored when update returns nil")
+	}
+
+	if err := storage.SaveTicket(types.NewTicket("TEST-1", "Original", types.TicketTypeTask)); err != nil {
+		t.Fatalf("Failed to save ticket: %v", err)
+	}
+
+	err = storage.UpdateTicket("TEST-1", func(current *types.Ticket) (*types.Ticket, error) {
+		current.Title = current.Title + " (updated)"
+		return current, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to update ticket: %v", err)
+	}
+	if ticket, _ := storage.LoadTicket("TEST-1"); ticket.Title != "Original (updated)" {
+		t.Errorf("Expected updated title, got %q", ticket.Title)
+	}
+
+	// The update cannot store a different ticket
+	err = storage.UpdateTicket("TEST-1", func(current *types.Ticket) (*types.Ticket, error) {
+		return types.NewTicket("TEST-2", "Other", types.TicketTypeTask), nil
+	})
+	if err == nil {
+		t.Error("Expected an error for a mismatched key")
+	}
+}
+
+func TestTicketRelationships(t *testing.T) {
+	storage, err := NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	children := func(key string) []string {
+		ticket, err := storage.LoadTicket(key)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", key, err)
+		}
+		return ticket.Relationships.Children
+	}
+	parent := func(key string) string {
+		ticket, err := storage.LoadTicket(key)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", key, err)
+		}
+		return ticket.Relationships.ParentKey
+	}
+	save := func(ticket *types.Ticket) {
+		if err := storage.SaveTicket(ticket); err != nil {
+			t.Fatalf("Failed to save %s: %v", ticket.Key, err)
+		}
+	}
+
+	save(types.NewTicket("TEST-1", "Epic", types.TicketTypeEpic))
+	save(types.NewTicket("TEST-2", "Other epic", types.TicketTypeEpic))
+
+	// A saved child is added to its parent
+	task := types.NewTicket("TEST-10", "Task", types.TicketTypeTask)
+	task.Relationships.ParentKey = "TEST-1"
+	save(task)
+	if got := children("TEST-1"); len(got) != 1 || got[0] != "TEST-10" {
+		t.Errorf("Expected TEST-1 children [TEST-10], got %v", got)
+	}
+
+	// Saving the parent again, as fetched from Jira, keeps its children
+	save(types.NewTicket("TEST-1", "Epic", types.TicketTypeEpic))
+	if got := children("TEST-1"); len(got) != 1 {
+		t.Errorf("Expected TEST-1 to keep its child, got %v", got)
+	}
+
+	// Moving the child updates both parents
+	task.Relationships.ParentKey = "TEST-2"
+	save(task)
+	if got := children("TEST-1"); len(got) != 0 {
+		t.Errorf("Expected TEST-1 to lose its child, got %v", got)
+	}
+	if got := children("TEST-2"); len(got) != 1 || got[0] != "TEST-10" {
+		t.Errorf("Expected TEST-2 children [TEST-10], got %v", got)
+	}
+
+	// A parent listing a child points the child back at it
+	subtask := types.NewTicket("TEST-11", "Subtask", types.TicketTypeSubtask)
+	save(subtask)
+	task.Relationships.Children = []string{"TEST-11"}
+	save(task)
+	if got := parent("TEST-11"); got != "TEST-10" {
+		t.Errorf("Expected TEST-11 parent TEST-10, got %q", got)
+	}
+
+	// Deleting a child removes it from its parent
+	if err := storage.DeleteTicket("TEST-10"); err != nil {
+		t.Fatalf("Failed to delete ticket: %v", err)
+	}
+	if got := children("TEST-2"); len(got) != 0 {
+		t.Errorf("Expected TEST-2 to lose its deleted child, got %v", got)
+	}
+}
+
 func TestListTickets(t *testing.T) {
 	tempDir := t.TempDir()
 	storage, err := NewJSONStorage(tempDir)
@@ -182,6 +328,46 @@ func TestListTickets(t *testing.T) {
 	}
 }
 
+func TestSaveAndLoadComments(t *testing.T) {
+	storage, err := NewJSONStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	// Nothing is stored before comments are fetched
+	comments, err := storage.LoadComments("TEST-1")
+	if err != nil || comments != nil {
+		t.Fatalf("Expected no comments, got %v (%v)", comments, err)
+	}
+
+	if err := storage.SaveTicket(types.NewTicket("TEST-1", "Test Ticket", types.TicketTypeTask)); err != nil {
+		t.Fatalf("Failed to save ticket: %v", err)
+	}
+	saved := []*types.Comment{
+		{ID: "10", TicketKey: "TEST-1", Author: "Dev", Body: "First", Created: time.Now().Truncate(time.Second)},
+		{ID: "11", TicketKey: "TEST-1", Author: "Dev", Body: "Second", Created: time.Now().Truncate(time.Second)},
+	}
+	if err := storage.SaveComments("TEST-1", saved); err != nil {
+		t.Fatalf("Failed to save comments: %v", err)
+	}
+
+	comments, err = storage.LoadComments("TEST-1")
+	if err != nil {
+		t.Fatalf("Failed to load comments: %v", err)
+	}
+	if len(comments) != 2 || comments[1].Body != "Second" || !comments[0].Created.Equal(saved[0].Created) {
+		t.Errorf("Unexpected comments: %+v", comments)
+	}
+
+	// Comments are deleted with their ticket
+	if err := storage.DeleteTicket("TEST-1"); err != nil {
+		t.Fatalf("Failed to delete ticket: %v", err)
+	}
+	if comments, _ := storage.LoadComments("TEST-1"); comments != nil {
+		t.Errorf("Expected comments to be deleted, got %+v", comments)
+	}
+}
+
 func TestSaveAndLoadContext(t *testing.T) {
 	tempDir := t.TempDir()
 	storage, err := NewJSONStorage(tempDir)
@@ -247,6 +433,151 @@ func TestLoadDefaultContext(t *testing.T) {
 	}
 }
 
+func TestSaveAndLoadCache(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewJSONStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	var missing map[string]string
+	found, err := storage.LoadCache("meta-TEST", &missing)
+	if err != nil {
+		t.Fatalf("Failed to load missing cache: %v", err)
+	}
+	if found {
+		t.Error("Expected missing cache entry to be reported as not found")
+	}
+
+	entry := map[string]string{"Epic": "10000", "Task": "10001"}
+	if err := storage.SaveCache("meta-TEST", entry); err != nil {
+		t.Fatalf("Failed to save cache: %v", err)
+	}
+
+	var loaded map[string]string
+	found, err = storage.LoadCache("meta-TEST", &loaded)
+	if err != nil {
+		t.Fatalf("Failed to load cache: %v", err)
+	}
+	if !found {
+		t.Fatal("Expected cache entry to be found")
+	}
+	if loaded["Epic"] != "10000" || loaded["Task"] != "10001" {
+		t.Errorf("Unexpected cache contents: %v", loaded)
+	}
+}
+
+func TestTimer(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewJSONStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	timer, err := storage.LoadTimer()
+	if err != nil || timer != nil {
+		t.Fatalf("Expected no running timer: timer=%v err=%v", timer, err)
+	}
+
+	if err := storage.SaveTimer(types.NewTimer("TEST-101")); err != nil {
+		t.Fatalf("Failed to save timer: %v", err)
+	}
+
+	timer, err = storage.LoadTimer()
+	if err != nil || timer == nil {
+		t.Fatalf("Failed to load timer: timer=%v err=%v", timer, err)
+	}
+	if timer.TicketKey != "TEST-101" || timer.Started.IsZero() {
+		t.Errorf("Unexpected timer: %+v", timer)
+	}
+
+	if err := storage.DeleteTimer(); err != nil {
+		t.Fatalf("Failed to delete timer: %v", err)
+	}
+	if timer, _ := storage.LoadTimer(); timer != nil {
+		t.Errorf("Expected deleted timer to be missing, got %+v", timer)
+	}
+	if err := storage.DeleteTimer(); err != nil {
+		t.Errorf("Deleting missing timer should succeed, got %v", err)
+	}
+}
+
+func TestWorklogQueue(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewJSONStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	queue := []*types.Worklog{
+		{TicketKey: "TEST-101", TimeSpent: 90 * time.Minute, Comment: "Pairing"},
+		{TicketKey: "TEST-102", TimeSpent: 15 * time.Minute},
+	}
+	if err := storage.SaveWorklogQueue(queue); err != nil {
+		t.Fatalf("Failed to save worklog queue: %v", err)
+	}
+
+	loaded, err := storage.LoadWorklogQueue()
+	if err != nil {
+		t.Fatalf("Failed to load worklog queue: %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].TimeSpent != 90*time.Minute || loaded[0].Comment != "Pairing" {
+		t.Errorf("Unexpected worklog queue: %+v", loaded)
+	}
+
+	// Saving an empty queue removes it
+	if err := storage.SaveWorklogQueue(nil); err != nil {
+		t.Fatalf("Failed to clear worklog queue: %v", err)
+	}
+	if _, err := os.Stat(storage.GetWorklogQueuePath()); !os.IsNotExist(err) {
+		t.Errorf("Expected worklog queue file to be removed, got %v", err)
+	}
+	if loaded, err := storage.LoadWorklogQueue(); err != nil || len(loaded) != 0 {
+		t.Errorf("Expected empty worklog queue: %v %v", loaded, err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	tempDir := t.TempDir()
+	storage, err := NewJSONStorage(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+
+	secret := map[string]string{"access_token": "abc"}
+	if err := storage.SaveCredentials("oauth-token", secret); err != nil {
+		t.Fatalf("Failed to save credentials: %v", err)
+	}
+
+	info, err := os.Stat(storage.GetCredentialsPath("oauth-token"))
+	if err != nil {
+		t.Fatalf("Credentials file not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected credentials to be private (0600), got %o", perm)
+	}
+
+	var loaded map[string]string
+	found, err := storage.LoadCredentials("oauth-token", &loaded)
+	if err != nil || !found {
+		t.Fatalf("Failed to load credentials: found=%v err=%v", found, err)
+	}
+	if loaded["access_token"] != "abc" {
+		t.Errorf("Unexpected credentials: %v", loaded)
+	}
+
+	if err := storage.DeleteCredentials("oauth-token"); err != nil {
+		t.Fatalf("Failed to delete credentials: %v", err)
+	}
+	found, err = storage.LoadCredentials("oauth-token", &loaded)
+	if err != nil || found {
+		t.Errorf("Expected deleted credentials to be missing: found=%v err=%v", found, err)
+	}
+	if err := storage.DeleteCredentials("oauth-token"); err != nil {
+		t.Errorf("Deleting missing credentials should succeed, got %v", err)
+	}
+}
+
 func TestContextManager(t *testing.T) {
 	tempDir := t.TempDir()
 	storage, err := NewJSONStorage(tempDir)
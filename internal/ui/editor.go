@@ -11,6 +11,9 @@ import (
 // Editor provides functionality to open and edit files
 type Editor struct {
 	editor string
+
+	// CustomFields are the custom field names offered in the field block
+	CustomFields []string
 }
 
 // NewEditor creates a new editor instance
@@ -49,7 +52,8 @@ func (e *Editor) EditFile(filePath string) error {
 	return cmd.Run()
 }
 
-// EditTemplate opens a template file for editing
+// EditTemplate opens a template file for editing, preceded by a field block
+// for the editor's custom fields when it has any
 func (e *Editor) EditTemplate(templatePath, outputPath string) error {
 	// Read the template
 	templateContent, err := os.ReadFile(templatePath)
@@ -57,6 +61,14 @@ func (e *Editor) EditTemplate(templatePath, outputPath string) error {
 		return fmt.Errorf("failed to read template: %v", err)
 	}
 
+	if len(e.CustomFields) > 0 {
+		fields, err := e.renderCustomFields()
+		if err != nil {
+			return err
+		}
+		templateContent = append([]byte(fields), templateContent...)
+	}
+
 	// Ensure the output directory exists
 	dir := filepath.Dir(outputPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -82,23 +94,70 @@ func (e *Editor) ReadFile(filePath string) (string, error) {
 	return string(content), nil
 }
 
-// ParseMarkdownTicket parses markdown content into ticket fields
+// ParseNewTicket parses a ticket written from a template: an optional field
+// block of fix versions, components and custom fields followed by the
+// markdown ParseMarkdownTicket reads
+func (e *Editor) ParseNewTicket(content string) (*TicketEdit, error) {
+	var fields struct {
+		FixVersions []string               `yaml:"fix_versions"`
+		Components  []string               `yaml:"components"`
+		Custom      map[string]interface{} `yaml:",inline"`
+	}
+	body, err := parseFrontMatter(content, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	title, description, err := e.ParseMarkdownTicket(body)
+	if err != nil {
+		return nil, err
+	}
+
+	custom, err := e.parseCustomFields(fields.Custom)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TicketEdit{
+		Title:        title,
+		Description:  description,
+		FixVersions:  trimNames(fields.FixVersions),
+		Components:   trimNames(fields.Components),
+		CustomFields: custom,
+	}, nil
+}
+
+// ParseMarkdownTicket parses markdown content into ticket fields.
+// Indentation and fenced code blocks in the description are preserved so that
+// nested lists and code survive conversion to Jira's rich text format.
 func (e *Editor) ParseMarkdownTicket(content string) (string, string, error) {
+	title, desc := parseTitleAndDescription(content)
+
+	// Validate
+	if title == "" {
+		return "", "", fmt.Errorf("title is required")
+	}
+
+	if desc == "" {
+		return "", "", fmt.Errorf("description is required")
+	}
+
+	return title, desc, nil
+}
+
+// parseTitleAndDescription splits ticket markdown into the "# " title line
+// and the description that follows it
+func parseTitleAndDescription(content string) (string, string) {
 	lines := strings.Split(content, "\n")
 
 	var title string
 	var description strings.Builder
 
 	inDescription := false
-	skipNext := false
-
-	for i, line := range lines {
-		if skipNext {
-			skipNext = false
-			continue
-		}
+	inCodeBlock := false
 
-		line = strings.TrimSpace(line)
+	for i, rawLine := range lines {
+		line := strings.TrimSpace(rawLine)
 
 		// Skip empty lines at the beginning
 		if title == "" && line == "" {
@@ -118,6 +177,16 @@ func (e *Editor) ParseMarkdownTicket(content string) (string, string, error) {
 
 		// Add to description
 		if inDescription {
+			// Keep fenced code blocks verbatim
+			if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+				inCodeBlock = !inCodeBlock
+			}
+			if inCodeBlock || strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+				description.WriteString(strings.TrimRight(rawLine, " \t\r"))
+				description.WriteString("\n")
+				continue
+			}
+
 			// Skip section headers
 			if strings.HasPrefix(line, "## ") {
 				description.WriteString("\n\n")
@@ -134,25 +203,18 @@ func (e *Editor) ParseMarkdownTicket(content string) (string, string, error) {
 				continue
 			}
 
-			// Add the line to description
+			// Add the line to description, keeping its indentation
 			if line != "" || (i < len(lines)-1 && strings.TrimSpace(lines[i+1]) != "") {
-				description.WriteString(line)
-				description.WriteString("\n")
+				if line == "" {
+					description.WriteString("\n")
+				} else {
+					description.WriteString(strings.TrimRight(rawLine, " \t\r"))
+					description.WriteString("\n")
+				}
 			}
 		}
 	}
 
 	// Clean up description
-	desc := strings.TrimSpace(description.String())
-
-	// Validate
-	if title == "" {
-		return "", "", fmt.Errorf("title is required")
-	}
-
-	if desc == "" {
-		return "", "", fmt.Errorf("description is required")
-	}
-
-	return title, desc, nil
+	return title, strings.TrimSpace(description.String())
 }
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// ParsePlanYAML parses a plan written as YAML and checks its custom fields
+// against the ones the editor offers
+func (e *Editor) ParsePlanYAML(content string) (*types.Plan, error) {
+	var plan types.Plan
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&plan); err != nil {
+		return nil, fmt.Errorf("invalid plan: %v", err)
+	}
+
+	return e.checkPlan(&plan)
+}
+
+// ParsePlanMarkdown parses a plan written as a markdown outline: "# " headings
+// are epics, "## " headings tasks and "### " headings subtasks. Lines such as
+// "priority: High" directly under a heading set fields, and the text after
+// them is the description. An optional field block at the top may set the
+// project.
+//
+//	# Roll out MFA
+//	priority: High
+//	Let users protect their accounts with a second factor.
+//
+//	## Add TOTP enrollment
+//	story_points: 5
+//	### Build the QR code screen
+func (e *Editor) ParsePlanMarkdown(content string) (*types.Plan, error) {
+	var plan types.Plan
+	var fields struct {
+		Project string `yaml:"project"`
+	}
+	body, err := parseFrontMatter(content, &fields)
+	if err != nil {
+		return nil, err
+	}
+	plan.Project = fields.Project
+
+	var epic, task, current *types.PlanEntry
+	var description strings.Builder
+	inFields, inCodeBlock := false, false
+
+	// finish stores the description gathered for the current entry
+	finish := func() {
+		if current != nil {
+			current.Description = strings.TrimSpace(description.String())
+		}
+		description.Reset()
+	}
+
+	for i, rawLine := range strings.Split(body, "\n") {
+		line := strings.TrimSpace(rawLine)
+
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCodeBlock = !inCodeBlock
+		}
+
+		if level, title := outlineHeading(line); level > 0 && !inCodeBlock {
+			finish()
+			entry := &types.PlanEntry{Title: title}
+
+			switch level {
+			case 1:
+				plan.Epics = append(plan.Epics, entry)
+				epic, task = entry, nil
+			case 2:
+				if epic != nil {
+					epic.Tasks = append(epic.Tasks, entry)
+				} else {
+					plan.Tasks = append(plan.Tasks, entry)
+				}
+				task = entry
+			case 3:
+				if task == nil {
+					return nil, fmt.Errorf("line %d: subtask %q is not under a task", i+1, title)
+				}
+				task.Subtasks = append(task.Subtasks, entry)
+			}
+
+			current, inFields = entry, true
+			continue
+		}
+
+		if current == nil {
+			if line != "" {
+				return nil, fmt.Errorf("line %d: text before the first heading", i+1)
+			}
+			continue
+		}
+
+		if inFields {
+			if ok, err := e.parseOutlineField(current, line); err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			} else if ok {
+				continue
+			}
+			inFields = false
+		}
+
+		description.WriteString(strings.TrimRight(rawLine, " \t\r"))
+		description.WriteString("\n")
+	}
+	finish()
+
+	return e.checkPlan(&plan)
+}
+
+// outlineHeading returns the level and text of an epic, task or subtask
+// heading, or 0 for any other line
+func outlineHeading(line string) (int, string) {
+	for level, prefix := range []string{"# ", "## ", "### "} {
+		if strings.HasPrefix(line, prefix) {
+			return level + 1, strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return 0, ""
+}
+
+// parseOutlineField sets a field from a "name: value" line under a heading.
+// It reports false for lines that are not fields, which start the description.
+func (e *Editor) parseOutlineField(entry *types.PlanEntry, line string) (bool, error) {
+	name, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return false, nil
+	}
+	name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+
+	switch name {
+	case "key":
+		entry.Key = value
+	case "priority":
+		entry.Priority = value
+	case "assignee":
+		entry.Assignee = value
+	case "labels":
+		entry.Labels = nil
+		for _, label := range strings.Split(strings.Trim(value, "[]"), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				entry.Labels = append(entry.Labels, label)
+			}
+		}
+	default:
+		if !e.offersCustomField(name) {
+			return false, nil
+		}
+
+		// Read values the way YAML would, so numbers and lists keep their type
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+			return false, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]interface{})
+		}
+		entry.Fields[name] = parsed
+	}
+	return true, nil
+}
+
+// offersCustomField reports whether name is one of the editor's custom fields
+func (e *Editor) offersCustomField(name string) bool {
+	for _, field := range e.CustomFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPlan validates a parsed plan and its entries' custom fields
+func (e *Editor) checkPlan(plan *types.Plan) (*types.Plan, error) {
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range plan.Entries() {
+		fields, err := e.parseCustomFields(entry.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", entry.Title, err)
+		}
+		entry.Fields = fields
+	}
+	return plan, nil
+}
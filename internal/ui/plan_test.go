@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// describePlan lists a parsed plan's entries, indented by depth, with the
+// fields that were set on them
+func describePlan(plan *types.Plan) []string {
+	var lines []string
+	for _, entry := range plan.Entries() {
+		line := fmt.Sprintf("%s%s %q", strings.Repeat("  ", entry.Depth()), strings.ToLower(entry.Type), entry.Title)
+		if entry.Key != "" {
+			line += " key=" + entry.Key
+		}
+		if entry.Priority != "" {
+			line += " priority=" + entry.Priority
+		}
+		if len(entry.Labels) > 0 {
+			line += " labels=" + strings.Join(entry.Labels, ",")
+		}
+		var names []string
+		for name := range entry.Fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			line += fmt.Sprintf(" %s=%v", name, entry.Fields[name])
+		}
+		if entry.Description != "" {
+			line += fmt.Sprintf(" desc=%q", entry.Description)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestParsePlanMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		project string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "heading levels",
+			content: "# Roll out MFA\n## Add TOTP\n### QR screen\n### Recovery codes\n## Add WebAuthn\n# Audit log\n",
+			want: []string{
+				`epic "Roll out MFA"`,
+				`  task "Add TOTP"`,
+				`    subtask "QR screen"`,
+				`    subtask "Recovery codes"`,
+				`  task "Add WebAuthn"`,
+				`epic "Audit log"`,
+			},
+		},
+		{
+			name:    "tasks without an epic",
+			content: "## Rotate keys\n### Staging\n## Patch hosts\n",
+			want: []string{
+				`task "Rotate keys"`,
+				`  subtask "Staging"`,
+				`task "Patch hosts"`,
+			},
+		},
+		{
+			name:    "deeper and unspaced headings are description",
+			content: "## Rotate keys\n#### Notes\n#Not a heading\n",
+			want:    []string{`task "Rotate keys" desc="#### Notes\n#Not a heading"`},
+		},
+		{
+			name:    "field lines then description",
+			content: "## Rotate keys\nkey: OPS-7\npriority: High\nlabels: [security, ops]\nstory_points: 5\nNote: keep the old keys a week\npriority: Low\n",
+			want:    []string{`task "Rotate keys" key=OPS-7 priority=High labels=security,ops story_points=5 desc="Note: keep the old keys a week\npriority: Low"`},
+		},
+		{
+			name:    "blank line ends the fields",
+			content: "## Rotate keys\n\npriority: High\n",
+			want:    []string{`task "Rotate keys" desc="priority: High"`},
+		},
+		{
+			name:    "unoffered field starts the description",
+			content: "## Rotate keys\nowner: me\npriority: High\n",
+			want:    []string{`task "Rotate keys" desc="owner: me\npriority: High"`},
+		},
+		{
+			name:    "headings inside code fences",
+			content: "## Rotate keys\nRun:\n```bash\n# not an epic\n## not a task\n```\n~~~\n### not a subtask\n~~~\n### Staging\n",
+			want: []string{
+				"task \"Rotate keys\" desc=\"Run:\\n```bash\\n# not an epic\\n## not a task\\n```\\n~~~\\n### not a subtask\\n~~~\"",
+				`  subtask "Staging"`,
+			},
+		},
+		{
+			name:    "code fence straight after the heading",
+			content: "## Rotate keys\n```\n# not an epic\n```\n",
+			want:    []string{"task \"Rotate keys\" desc=\"```\\n# not an epic\\n```\""},
+		},
+		{
+			name:    "front matter project",
+			content: "---\nproject: OPS\n---\n## Rotate keys\n",
+			project: "OPS",
+			want:    []string{`task "Rotate keys"`},
+		},
+		{
+			name:    "front matter with CRLF line endings",
+			content: "---\r\nproject: OPS\r\n---\r\n## Rotate keys\r\npriority: High\r\n",
+			project: "OPS",
+			want:    []string{`task "Rotate keys" priority=High`},
+		},
+		{
+			name:    "subtask with no task above it",
+			content: "### Staging\n",
+			wantErr: `line 1: subtask "Staging" is not under a task`,
+		},
+		{
+			name:    "subtask directly under an epic",
+			content: "# Roll out MFA\n### QR screen\n",
+			wantErr: `line 2: subtask "QR screen" is not under a task`,
+		},
+		{
+			name:    "text before the first heading",
+			content: "\nIntro\n## Rotate keys\n",
+			wantErr: "line 2: text before the first heading",
+		},
+		{
+			name:    "invalid field value",
+			content: "## Rotate keys\nstory_points: [5\n",
+			wantErr: "line 2: invalid story_points",
+		},
+		{
+			name:    "no tickets",
+			content: "---\nproject: OPS\n---\n",
+			wantErr: "plan has no epics or tasks",
+		},
+	}
+
+	editor := &Editor{CustomFields: []string{"story_points"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := editor.ParsePlanMarkdown(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePlanMarkdown failed: %v", err)
+			}
+
+			if plan.Project != tt.project {
+				t.Errorf("Expected project %q, got %q", tt.project, plan.Project)
+			}
+			if got := describePlan(plan); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Unexpected plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParsePlanYAML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		project string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "single epic",
+			content: `project: OPS
+epic:
+  title: Roll out MFA
+  priority: High
+  tasks:
+    - title: Add TOTP
+      fields: {story_points: 5}
+      subtasks:
+        - title: QR screen
+`,
+			project: "OPS",
+			want: []string{
+				`epic "Roll out MFA" priority=High`,
+				`  task "Add TOTP" story_points=5`,
+				`    subtask "QR screen"`,
+			},
+		},
+		{
+			name: "epic folded in before epics",
+			content: `epic: {title: First}
+epics:
+  - title: Second
+tasks:
+  - key: OPS-7
+    labels: [ops]
+    description: |
+      Existing ticket
+`,
+			want: []string{
+				`epic "First"`,
+				`epic "Second"`,
+				`task "" key=OPS-7 labels=ops desc="Existing ticket\n"`,
+			},
+		},
+		{
+			name:    "dates become Jira dates",
+			content: "tasks:\n  - title: Rotate keys\n    fields: {due: 2024-05-01}\n",
+			want:    []string{`task "Rotate keys" due=2024-05-01`},
+		},
+		{
+			name:    "unknown key",
+			content: "tasks:\n  - title: Rotate keys\n    owner: me\n",
+			wantErr: "invalid plan",
+		},
+		{
+			name:    "unoffered custom field",
+			content: "tasks:\n  - title: Rotate keys\n    fields: {team: sre}\n",
+			wantErr: `Rotate keys: unknown field "team"`,
+		},
+		{
+			name:    "subtasks under an epic",
+			content: "epics:\n  - title: Roll out MFA\n    subtasks:\n      - title: QR screen\n",
+			wantErr: "epics[0]: epics hold tasks, not subtasks",
+		},
+		{
+			name:    "missing title",
+			content: "tasks:\n  - priority: High\n",
+			wantErr: "tasks[0]: title is required",
+		},
+	}
+
+	editor := &Editor{CustomFields: []string{"story_points", "due"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := editor.ParsePlanYAML(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePlanYAML failed: %v", err)
+			}
+
+			if plan.Project != tt.project {
+				t.Errorf("Expected project %q, got %q", tt.project, plan.Project)
+			}
+			if got := describePlan(plan); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Unexpected plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
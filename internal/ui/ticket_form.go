@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+// frontMatterDelimiter opens and closes the field block above the ticket markdown
+const frontMatterDelimiter = "---"
+
+// TicketEdit holds the ticket fields that can be changed in the editor
+type TicketEdit struct {
+	Title       string
+	Description string
+	Priority    string
+	Assignee    string
+	Labels      []string
+	FixVersions []string
+	Components  []string
+
+	// Custom field values keyed by configured name; nil clears a field
+	CustomFields map[string]interface{}
+}
+
+// ticketFrontMatter is the YAML block rendered above the title. Custom
+// fields follow the built-in ones under their configured names.
+type ticketFrontMatter struct {
+	Priority    string                 `yaml:"priority"`
+	Assignee    string                 `yaml:"assignee"`
+	Labels      []string               `yaml:"labels,flow"`
+	FixVersions []string               `yaml:"fix_versions,flow"`
+	Components  []string               `yaml:"components,flow"`
+	Custom      map[string]interface{} `yaml:",inline"`
+}
+
+// RenderTicket renders a ticket for editing: a YAML front matter block with
+// priority, assignee, labels, fix versions, components and the editor's
+// custom fields, followed by the same "# Title" markdown that ticket
+// creation parses
+func (e *Editor) RenderTicket(ticket *types.Ticket) (string, error) {
+	labels := ticket.Metadata.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	// Show who the assignee is rather than the ID Jira knows them by
+	assignee := ticket.Metadata.AssigneeName
+	if assignee == "" {
+		assignee = ticket.Metadata.Assignee
+	}
+
+	fields, err := yaml.Marshal(ticketFrontMatter{
+		Priority:    ticket.Priority,
+		Assignee:    assignee,
+		Labels:      labels,
+		FixVersions: nonNil(ticket.Metadata.FixVersions),
+		Components:  nonNil(ticket.Metadata.Components),
+		Custom:      e.customFieldValues(ticket.JiraData.CustomFields),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render ticket fields: %v", err)
+	}
+
+	var out strings.Builder
+	out.WriteString(frontMatterDelimiter + "\n")
+	fmt.Fprintf(&out, "# Editing %s. Only changed fields are sent to Jira.\n", ticket.Key)
+	out.Write(fields)
+	out.WriteString(frontMatterDelimiter + "\n")
+	fmt.Fprintf(&out, "# %s\n\n", ticket.Title)
+	if ticket.Description != "" {
+		out.WriteString(ticket.Description)
+		out.WriteString("\n")
+	}
+
+	return out.String(), nil
+}
+
+// ParseTicket parses content produced by RenderTicket after editing.
+// Unlike ParseMarkdownTicket, the description may be left empty.
+func (e *Editor) ParseTicket(content string) (*TicketEdit, error) {
+	var fields ticketFrontMatter
+	body, err := parseFrontMatter(content, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	custom, err := e.parseCustomFields(fields.Custom)
+	if err != nil {
+		return nil, err
+	}
+
+	title, description := parseEditedBody(body)
+	if title == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	// Jira labels cannot contain spaces
+	var labels []string
+	for _, label := range fields.Labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if strings.ContainsAny(label, " \t") {
+			return nil, fmt.Errorf("label %q cannot contain spaces", label)
+		}
+		labels = append(labels, label)
+	}
+
+	return &TicketEdit{
+		Title:        title,
+		Description:  description,
+		Priority:     strings.TrimSpace(fields.Priority),
+		Assignee:     strings.TrimSpace(fields.Assignee),
+		Labels:       labels,
+		FixVersions:  trimNames(fields.FixVersions),
+		Components:   trimNames(fields.Components),
+		CustomFields: custom,
+	}, nil
+}
+
+// trimNames trims version or component names and drops blank ones
+func trimNames(names []string) []string {
+	var trimmed []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			trimmed = append(trimmed, name)
+		}
+	}
+	return trimmed
+}
+
+// nonNil renders a missing list as an empty one rather than null
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+// parseEditedBody splits the markdown below the field block into the
+// "# " title line and the description. Unlike parseTitleAndDescription it
+// strips no template placeholders and reformats nothing: the description is
+// kept as written apart from the blank lines around it, so saving an
+// unchanged ticket changes nothing.
+func parseEditedBody(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, "# ") {
+			return "", ""
+		}
+		title := strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+		description := strings.Join(lines[i+1:], "\n")
+		return title, strings.TrimRight(strings.TrimLeft(description, "\n"), " \t\n")
+	}
+
+	return "", ""
+}
+
+// parseFrontMatter decodes the YAML block opening content, if there is one,
+// into fields and returns the markdown that follows it. Windows line endings
+// are read as plain newlines.
+func parseFrontMatter(content string, fields interface{}) (string, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	trimmed := strings.TrimLeft(content, " \t\r\n")
+	if !strings.HasPrefix(trimmed, frontMatterDelimiter+"\n") {
+		return content, nil
+	}
+
+	rest := trimmed[len(frontMatterDelimiter)+1:]
+	end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+	if end < 0 {
+		return "", fmt.Errorf("field block is not closed with %s", frontMatterDelimiter)
+	}
+	if err := yaml.Unmarshal([]byte(rest[:end]), fields); err != nil {
+		return "", fmt.Errorf("invalid field block: %v", err)
+	}
+	return rest[end+len(frontMatterDelimiter)+2:], nil
+}
+
+// renderCustomFields renders a field block offering the editor's custom
+// fields without values
+func (e *Editor) renderCustomFields() (string, error) {
+	fields, err := yaml.Marshal(e.customFieldValues(nil))
+	if err != nil {
+		return "", fmt.Errorf("failed to render custom fields: %v", err)
+	}
+
+	var out strings.Builder
+	out.WriteString(frontMatterDelimiter + "\n")
+	out.WriteString("# Custom fields; leave null to skip.\n")
+	out.Write(fields)
+	out.WriteString(frontMatterDelimiter + "\n")
+	return out.String(), nil
+}
+
+// customFieldValues lists every custom field the editor offers, empty when
+// the ticket has no value for it
+func (e *Editor) customFieldValues(values map[string]interface{}) map[string]interface{} {
+	custom := make(map[string]interface{}, len(e.CustomFields))
+	for _, name := range e.CustomFields {
+		custom[name] = values[name]
+	}
+	return custom
+}
+
+// parseCustomFields checks edited custom fields against the ones the editor
+// offers. Dates YAML reads as times are kept as dates.
+func (e *Editor) parseCustomFields(fields map[string]interface{}) (map[string]interface{}, error) {
+	known := make(map[string]bool, len(e.CustomFields))
+	for _, name := range e.CustomFields {
+		known[name] = true
+	}
+
+	custom := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		if !known[name] {
+			return nil, fmt.Errorf("unknown field %q; custom fields are configured under jira.custom_fields", name)
+		}
+		if t, ok := value.(time.Time); ok {
+			if t.Equal(t.Truncate(24 * time.Hour)) {
+				value = t.Format("2006-01-02")
+			} else {
+				value = t.Format(time.RFC3339)
+			}
+		}
+		custom[name] = value
+	}
+	return custom, nil
+}
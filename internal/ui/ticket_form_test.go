@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lunchboxsushi/jit/pkg/types"
+)
+
+func TestRenderAndParseTicketRoundTrip(t *testing.T) {
+	descriptions := map[string]string{
+		"links":      "See [Testing notes](https://x.example/notes) and [Describe the API](https://x.example/api).",
+		"task list":  "- [ ] [List of hosts] to drain\n- [x] [Enter maintenance] announced\n  - [ ] nested [Criterion 1]",
+		"headings":   "Intro line\n## Acceptance Criteria\n- works\n\n### Details\nMore text",
+		"code fence": "Run this:\n\n```bash\n  echo \"[Technical debt]\"\n\n## not a heading\n```\n\nDone.",
+		"indented":   "    indented code\n\ttabbed line",
+	}
+
+	editor := &Editor{CustomFields: []string{"story_points"}}
+	for name, description := range descriptions {
+		ticket := types.NewTicket("TEST-1", "Round trip", types.TicketTypeTask)
+		ticket.Description = description
+		ticket.Priority = "High"
+		ticket.Metadata.Labels = []string{"backend"}
+
+		content, err := editor.RenderTicket(ticket)
+		if err != nil {
+			t.Fatalf("%s: RenderTicket failed: %v", name, err)
+		}
+
+		edit, err := editor.ParseTicket(content)
+		if err != nil {
+			t.Fatalf("%s: ParseTicket failed: %v", name, err)
+		}
+		if edit.Title != ticket.Title {
+			t.Errorf("%s: expected title %q, got %q", name, ticket.Title, edit.Title)
+		}
+		if edit.Description != description {
+			t.Errorf("%s: description changed in the round trip:\nwant %q\ngot  %q", name, description, edit.Description)
+		}
+		if edit.Priority != "High" || len(edit.Labels) != 1 {
+			t.Errorf("%s: unexpected fields %+v", name, edit)
+		}
+	}
+}
+
+func TestParseTicketCRLF(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"priority: Low",
+		"labels: [ops]",
+		"---",
+		"# Windows title",
+		"",
+		"First line",
+		"Second line",
+		"",
+	}, "\r\n")
+
+	edit, err := (&Editor{}).ParseTicket(content)
+	if err != nil {
+		t.Fatalf("ParseTicket failed: %v", err)
+	}
+	if edit.Priority != "Low" || len(edit.Labels) != 1 || edit.Labels[0] != "ops" {
+		t.Errorf("Expected the field block to be read, got %+v", edit)
+	}
+	if edit.Title != "Windows title" || edit.Description != "First line\nSecond line" {
+		t.Errorf("Unexpected title %q and description %q", edit.Title, edit.Description)
+	}
+}
+
+func TestParseTicketRequiresTitle(t *testing.T) {
+	if _, err := (&Editor{}).ParseTicket("---\npriority: Low\n---\nNo heading here\n"); err == nil {
+		t.Error("Expected a missing title to fail")
+	}
+}
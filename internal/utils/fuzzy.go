@@ -49,6 +49,20 @@ func FuzzySearch(query string, tickets []TicketInfo) []SearchResult {
 	return results
 }
 
+// FuzzyScore scores how well query matches the best of the given strings,
+// using the same ranking as ticket search; 0 means no match
+func FuzzyScore(query string, candidates ...string) int {
+	query = strings.ToLower(strings.TrimSpace(query))
+
+	best := 0
+	for _, candidate := range candidates {
+		if score, _ := calculateScore(query, candidate, ""); score > best {
+			best = score
+		}
+	}
+	return best
+}
+
 // calculateScore calculates a fuzzy match score
 func calculateScore(query, key, title string) (int, string) {
 	keyLower := strings.ToLower(key)
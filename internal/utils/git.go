@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// GitRepo is the git repository in the current directory, as seen on the
+// web host its remote points to
+type GitRepo struct {
+	Host   string // e.g. github.com
+	Path   string // e.g. owner/repo
+	Branch string // Current branch
+}
+
+// CurrentGitRepo reads the current branch and the web location of its
+// remote: the branch's upstream remote, or origin when it has none
+func CurrentGitRepo() (*GitRepo, error) {
+	if _, err := git("rev-parse", "--git-dir"); err != nil {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+
+	branch, err := git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("not on a branch")
+	}
+
+	remote := "origin"
+	if upstream, err := git("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		if name, _, ok := strings.Cut(upstream, "/"); ok {
+			remote = name
+		}
+	}
+
+	remoteURL, err := git("remote", "get-url", remote)
+	if err != nil {
+		return nil, fmt.Errorf("no remote %q", remote)
+	}
+
+	host, path, err := ParseGitRemote(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitRepo{Host: host, Path: path, Branch: branch}, nil
+}
+
+// ParseGitRemote splits a remote URL in any of git's forms (https://,
+// ssh://, git@host:path) into the host and repository path
+func ParseGitRemote(remote string) (string, string, error) {
+	remote = strings.TrimSpace(remote)
+
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid remote %q: %v", remote, err)
+		}
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, ":"); ok {
+		// scp-like syntax: [user@]host:path
+		if _, h, ok := strings.Cut(at, "@"); ok {
+			at = h
+		}
+		host, path = at, rest
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || path == "" {
+		return "", "", fmt.Errorf("cannot tell the web address of remote %q", remote)
+	}
+	return host, path, nil
+}
+
+// WebURL returns the repository's web address
+func (r *GitRepo) WebURL() string {
+	return fmt.Sprintf("https://%s/%s", r.Host, r.Path)
+}
+
+// BranchURL returns the web address of the current branch, in the form the
+// host uses
+func (r *GitRepo) BranchURL() string {
+	// Branch names may hold characters such as # and %; slashes separate
+	// path segments on every host
+	segments := strings.Split(r.Branch, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	branch := strings.Join(segments, "/")
+
+	switch {
+	case strings.Contains(r.Host, "gitlab"):
+		return fmt.Sprintf("%s/-/tree/%s", r.WebURL(), branch)
+	case strings.Contains(r.Host, "bitbucket"):
+		return fmt.Sprintf("%s/branch/%s", r.WebURL(), branch)
+	default:
+		return fmt.Sprintf("%s/tree/%s", r.WebURL(), branch)
+	}
+}
+
+// IsGitHub reports whether the repository is hosted on GitHub
+func (r *GitRepo) IsGitHub() bool {
+	return r.Host == "github.com"
+}
+
+// git runs a git command and returns its trimmed output
+func git(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,14 @@
+package types
+
+import "time"
+
+// Attachment is a file attached to a ticket in Jira
+type Attachment struct {
+	ID        string    `json:"id"`
+	TicketKey string    `json:"ticket_key"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	MimeType  string    `json:"mime_type"`
+	Author    string    `json:"author,omitempty"`
+	Created   time.Time `json:"created"`
+}
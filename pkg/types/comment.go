@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// Comment is a comment on a ticket in Jira, with its body in markdown
+type Comment struct {
+	ID        string    `json:"id"`
+	TicketKey string    `json:"ticket_key"`
+	Author    string    `json:"author,omitempty"`
+	AuthorID  string    `json:"author_id,omitempty"` // Account ID on Cloud, username on Server
+	Body      string    `json:"body"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Edited reports whether the comment was changed after it was posted
+func (c *Comment) Edited() bool {
+	return c.Updated.Sub(c.Created) > time.Second
+}
@@ -1,19 +1,112 @@
 package types
 
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
 // Config represents the application configuration
 type Config struct {
-	Jira JiraConfig `yaml:"jira" json:"jira"`
-	AI   AIConfig   `yaml:"ai" json:"ai"`
-	App  AppConfig  `yaml:"app" json:"app"`
+	Jira    JiraConfig    `yaml:"jira" json:"jira"`
+	AI      AIConfig      `yaml:"ai" json:"ai"`
+	App     AppConfig     `yaml:"app" json:"app"`
+	Network NetworkConfig `yaml:"network,omitempty" json:"network,omitempty"`
+
+	// Further Jira sites by name, used with --remote; the jira section is the
+	// remote named "default"
+	Remotes       map[string]JiraConfig `yaml:"remotes,omitempty" json:"remotes,omitempty"`
+	DefaultRemote string                `yaml:"default_remote,omitempty" json:"default_remote,omitempty"` // Remote used without --remote (default: the jira section)
+
+	// ActiveRemote names the remote whose settings are in Jira
+	ActiveRemote string `yaml:"-" json:"-"`
+}
+
+// DefaultRemoteName is the name of the remote configured in the jira section
+const DefaultRemoteName = "default"
+
+// RemoteNames lists the configured remotes: the default one, then the named
+// ones in alphabetical order
+func (c *Config) RemoteNames() []string {
+	names := make([]string, 0, len(c.Remotes))
+	for name := range c.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultRemoteName}, names...)
+}
+
+// Remote returns a remote's Jira settings. An empty name selects the
+// DefaultRemote, or the jira section when that is unset too.
+func (c *Config) Remote(name string) (*JiraConfig, string, error) {
+	if name == "" {
+		name = c.DefaultRemote
+	}
+	if name == "" || name == DefaultRemoteName {
+		return &c.Jira, DefaultRemoteName, nil
+	}
+
+	remote, ok := c.Remotes[name]
+	if !ok {
+		return nil, "", fmt.Errorf("no remote %q; see 'jit remote'", name)
+	}
+	return &remote, name, nil
+}
+
+// ForRemote returns a copy of the config with a remote's settings in Jira
+// and its name in ActiveRemote, selected as by Remote
+func (c *Config) ForRemote(name string) (*Config, error) {
+	remote, name, err := c.Remote(name)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := *c
+	selected.Jira = *remote
+	selected.ActiveRemote = name
+	return &selected, nil
 }
 
 // JiraConfig contains Jira connection settings
 type JiraConfig struct {
 	URL           string `yaml:"url" json:"url"`
+	Flavor        string `yaml:"flavor,omitempty" json:"flavor,omitempty"` // cloud (default), server or auto
 	Username      string `yaml:"username" json:"username"`
 	Token         string `yaml:"token" json:"token"`
 	Project       string `yaml:"project" json:"project"`
 	EpicLinkField string `yaml:"epic_link_field" json:"epic_link_field"`
+	PageSize      int    `yaml:"page_size,omitempty" json:"page_size,omitempty"`           // Issues per search page (default 100)
+	MaxRetries    int    `yaml:"max_retries,omitempty" json:"max_retries,omitempty"`       // Retries for rate-limited or failed requests (default 4, -1 disables)
+	Concurrency   int    `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`       // Requests 'jit track' keeps in flight (default 4)
+	BoardID       int    `yaml:"board_id,omitempty" json:"board_id,omitempty"`             // Board used by 'jit sprint' (default: the project's only scrum board)
+	WebhookSecret string `yaml:"webhook_secret,omitempty" json:"webhook_secret,omitempty"` // Shared secret 'jit serve webhooks' requires of Jira
+
+	// Jira issue type names keyed by jit ticket type (epic, task, subtask)
+	IssueTypes  map[string]string `yaml:"issue_types,omitempty" json:"issue_types,omitempty"`
+	MetadataTTL time.Duration     `yaml:"metadata_ttl,omitempty" json:"metadata_ttl,omitempty"` // How long cached project metadata is trusted (default 24h)
+
+	// Custom fields keyed by the name used in jit, e.g. story_points: customfield_10016.
+	// Values are field IDs or Jira field names.
+	CustomFields map[string]string `yaml:"custom_fields,omitempty" json:"custom_fields,omitempty"`
+
+	// Status aliases keyed by project key ("*" applies to every project), e.g. review: "Code Review"
+	StatusAliases map[string]map[string]string `yaml:"status_aliases,omitempty" json:"status_aliases,omitempty"`
+
+	// OAuth 2.0 (3LO) app credentials; when set, 'jit auth login' replaces the API token
+	OAuth *OAuthConfig `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+}
+
+// OAuthConfig contains the OAuth 2.0 app registration used by 'jit auth login'.
+// Endpoints default to Atlassian's; override them for testing or a proxy.
+type OAuthConfig struct {
+	ClientID     string   `yaml:"client_id" json:"client_id"`
+	ClientSecret string   `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	CallbackPort int      `yaml:"callback_port,omitempty" json:"callback_port,omitempty"` // Loopback port registered as the callback URL (default 8731)
+	AuthURL      string   `yaml:"auth_url,omitempty" json:"auth_url,omitempty"`
+	TokenURL     string   `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+	ResourcesURL string   `yaml:"resources_url,omitempty" json:"resources_url,omitempty"` // Lists the sites a token can access
+	APIURL       string   `yaml:"api_url,omitempty" json:"api_url,omitempty"`             // Gateway that proxies site APIs by cloud ID
 }
 
 // AIConfig contains AI provider settings
@@ -22,6 +115,8 @@ type AIConfig struct {
 	APIKey    string `yaml:"api_key" json:"api_key"`
 	Model     string `yaml:"model" json:"model"`
 	MaxTokens int    `yaml:"max_tokens" json:"max_tokens"`
+
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"` // Whole request to the provider (default 30s)
 }
 
 // AppConfig contains application settings
@@ -31,6 +126,21 @@ type AppConfig struct {
 	ReviewBeforeCreate bool   `yaml:"review_before_create" json:"review_before_create"`
 }
 
+// NetworkConfig controls how jit reaches Jira and the AI provider: proxy,
+// TLS trust, client certificates and timeouts. It applies to every
+// outbound request.
+type NetworkConfig struct {
+	ProxyURL           string        `yaml:"proxy_url,omitempty" json:"proxy_url,omitempty"`                       // Proxy for all requests (default: HTTPS_PROXY/HTTP_PROXY from the environment)
+	NoProxy            []string      `yaml:"no_proxy,omitempty" json:"no_proxy,omitempty"`                         // Hosts, domains (.corp.example) or CIDRs reached directly
+	CABundle           string        `yaml:"ca_bundle,omitempty" json:"ca_bundle,omitempty"`                       // PEM file of CAs trusted in addition to the system's
+	ClientCert         string        `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`                   // PEM client certificate for mTLS
+	ClientKey          string        `yaml:"client_key,omitempty" json:"client_key,omitempty"`                     // PEM private key of the client certificate
+	ConnectTimeout     time.Duration `yaml:"connect_timeout,omitempty" json:"connect_timeout,omitempty"`           // Connecting and the TLS handshake (default 10s)
+	ResponseTimeout    time.Duration `yaml:"response_timeout,omitempty" json:"response_timeout,omitempty"`         // Waiting for response headers, per attempt (default 30s)
+	RequestTimeout     time.Duration `yaml:"request_timeout,omitempty" json:"request_timeout,omitempty"`           // The whole exchange, response body included, per attempt (default 2m)
+	InsecureSkipVerify bool          `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"` // Accept any server certificate; for debugging only
+}
+
 // NewConfig creates a new config with default values
 func NewConfig() *Config {
 	return &Config{
@@ -0,0 +1,49 @@
+package types
+
+import "testing"
+
+func TestConfigForRemote(t *testing.T) {
+	config := &Config{
+		Jira: JiraConfig{URL: "https://home.atlassian.net", Project: "PROJ"},
+		Remotes: map[string]JiraConfig{
+			"work":  {URL: "https://work.atlassian.net", Project: "SRE"},
+			"infra": {URL: "https://jira.infra.example", Project: "OPS"},
+		},
+	}
+
+	if names := config.RemoteNames(); len(names) != 3 || names[0] != DefaultRemoteName || names[1] != "infra" || names[2] != "work" {
+		t.Errorf("Expected the default remote then the named ones in order, got %v", names)
+	}
+
+	tests := []struct {
+		name          string
+		defaultRemote string
+		wantRemote    string
+		wantProject   string
+	}{
+		{"", "", DefaultRemoteName, "PROJ"},
+		{"", "work", "work", "SRE"},
+		{"infra", "work", "infra", "OPS"},
+		{DefaultRemoteName, "work", DefaultRemoteName, "PROJ"},
+	}
+
+	for _, tt := range tests {
+		config.DefaultRemote = tt.defaultRemote
+		selected, err := config.ForRemote(tt.name)
+		if err != nil {
+			t.Fatalf("ForRemote(%q) failed: %v", tt.name, err)
+		}
+		if selected.ActiveRemote != tt.wantRemote || selected.Jira.Project != tt.wantProject {
+			t.Errorf("ForRemote(%q) with default %q = %s (%s), want %s (%s)", tt.name, tt.defaultRemote, selected.ActiveRemote, selected.Jira.Project, tt.wantRemote, tt.wantProject)
+		}
+	}
+
+	// The original config is left as it was
+	if config.Jira.Project != "PROJ" || config.ActiveRemote != "" {
+		t.Errorf("Expected ForRemote to return a copy, config is now %+v", config.Jira)
+	}
+
+	if _, err := config.ForRemote("missing"); err == nil {
+		t.Error("Expected an unknown remote to fail")
+	}
+}
@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+// HistoryEntry is one change to a ticket: the fields one user changed at once
+type HistoryEntry struct {
+	ID      string        `json:"id"`
+	Author  string        `json:"author"`
+	Created time.Time     `json:"created"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is a field's value before and after a change
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plan describes a hierarchy of tickets for 'jit apply' to create: epics
+// with their tasks and subtasks, and standalone tasks
+type Plan struct {
+	Project string       `yaml:"project,omitempty"`
+	Epic    *PlanEntry   `yaml:"epic,omitempty"`
+	Epics   []*PlanEntry `yaml:"epics,omitempty"`
+	Tasks   []*PlanEntry `yaml:"tasks,omitempty"`
+}
+
+// PlanEntry is one ticket in a plan with the tickets under it. An entry
+// with a Key refers to an existing ticket rather than creating one.
+type PlanEntry struct {
+	Key         string                 `yaml:"key,omitempty"`
+	Title       string                 `yaml:"title"`
+	Description string                 `yaml:"description,omitempty"`
+	Priority    string                 `yaml:"priority,omitempty"`
+	Assignee    string                 `yaml:"assignee,omitempty"`
+	Labels      []string               `yaml:"labels,flow,omitempty"`
+	Fields      map[string]interface{} `yaml:"fields,omitempty"`
+	Tasks       []*PlanEntry           `yaml:"tasks,omitempty"`
+	Subtasks    []*PlanEntry           `yaml:"subtasks,omitempty"`
+
+	// Set by Validate
+	Type   string     `yaml:"-"`
+	Parent *PlanEntry `yaml:"-"`
+}
+
+// Children returns the entries directly under e
+func (e *PlanEntry) Children() []*PlanEntry {
+	if e.Type == TicketTypeEpic {
+		return e.Tasks
+	}
+	return e.Subtasks
+}
+
+// Validate checks the plan's structure, folds Epic into Epics and sets each
+// entry's Type and Parent
+func (p *Plan) Validate() error {
+	if p.Epic != nil {
+		p.Epics = append([]*PlanEntry{p.Epic}, p.Epics...)
+		p.Epic = nil
+	}
+	if len(p.Epics) == 0 && len(p.Tasks) == 0 {
+		return fmt.Errorf("plan has no epics or tasks")
+	}
+
+	for i, epic := range p.Epics {
+		if err := epic.validate(fmt.Sprintf("epics[%d]", i), TicketTypeEpic, nil); err != nil {
+			return err
+		}
+	}
+	for i, task := range p.Tasks {
+		if err := task.validate(fmt.Sprintf("tasks[%d]", i), TicketTypeTask, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks an entry and those under it
+func (e *PlanEntry) validate(path, ticketType string, parent *PlanEntry) error {
+	e.Type = ticketType
+	e.Parent = parent
+	e.Title = strings.TrimSpace(e.Title)
+
+	if e.Title == "" && e.Key == "" {
+		return fmt.Errorf("%s: title is required", path)
+	}
+
+	switch ticketType {
+	case TicketTypeEpic:
+		if len(e.Subtasks) > 0 {
+			return fmt.Errorf("%s: epics hold tasks, not subtasks", path)
+		}
+		for i, task := range e.Tasks {
+			if err := task.validate(fmt.Sprintf("%s.tasks[%d]", path, i), TicketTypeTask, e); err != nil {
+				return err
+			}
+		}
+	case TicketTypeTask:
+		if len(e.Tasks) > 0 {
+			return fmt.Errorf("%s: tasks hold subtasks, not tasks", path)
+		}
+		for i, subtask := range e.Subtasks {
+			if err := subtask.validate(fmt.Sprintf("%s.subtasks[%d]", path, i), TicketTypeSubtask, e); err != nil {
+				return err
+			}
+		}
+	default:
+		if len(e.Tasks) > 0 || len(e.Subtasks) > 0 {
+			return fmt.Errorf("%s: subtasks cannot hold other tickets", path)
+		}
+	}
+	return nil
+}
+
+// Levels groups a validated plan's entries in the order they can be
+// created: epics, then tasks, then subtasks, so every parent comes first
+func (p *Plan) Levels() [][]*PlanEntry {
+	var levels [][]*PlanEntry
+	level := append(append([]*PlanEntry{}, p.Epics...), p.Tasks...)
+
+	for len(level) > 0 {
+		// Standalone tasks start out beside the epics; hold them back to
+		// the tasks' level so each level holds a single ticket type
+		var current, next []*PlanEntry
+		for _, entry := range level {
+			if len(current) > 0 && entry.Type != current[0].Type {
+				next = append(next, entry)
+				continue
+			}
+			current = append(current, entry)
+			next = append(next, entry.Children()...)
+		}
+		levels = append(levels, current)
+		level = next
+	}
+
+	return levels
+}
+
+// Entries returns every entry in a validated plan, each followed by the
+// entries under it
+func (p *Plan) Entries() []*PlanEntry {
+	var entries []*PlanEntry
+	var walk func([]*PlanEntry)
+	walk = func(level []*PlanEntry) {
+		for _, entry := range level {
+			entries = append(entries, entry)
+			walk(entry.Children())
+		}
+	}
+	walk(p.Epics)
+	walk(p.Tasks)
+	return entries
+}
+
+// Depth returns how far below the top of the plan an entry is
+func (e *PlanEntry) Depth() int {
+	depth := 0
+	for parent := e.Parent; parent != nil; parent = parent.Parent {
+		depth++
+	}
+	return depth
+}
@@ -0,0 +1,73 @@
+package types
+
+import "testing"
+
+func TestPlanLevels(t *testing.T) {
+	plan := &Plan{
+		Epic: &PlanEntry{Title: "MFA", Tasks: []*PlanEntry{
+			{Title: "TOTP", Subtasks: []*PlanEntry{{Title: "QR code"}, {Title: "Recovery codes"}}},
+			{Title: "WebAuthn"},
+		}},
+		Tasks: []*PlanEntry{{Title: "Docs", Subtasks: []*PlanEntry{{Title: "Screenshots"}}}},
+	}
+	if err := plan.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := [][]string{
+		{"MFA"},
+		{"TOTP", "WebAuthn", "Docs"},
+		{"QR code", "Recovery codes", "Screenshots"},
+	}
+	levels := plan.Levels()
+	if len(levels) != len(expected) {
+		t.Fatalf("Expected %d levels, got %d", len(expected), len(levels))
+	}
+	for i, level := range levels {
+		var titles []string
+		for _, entry := range level {
+			titles = append(titles, entry.Title)
+		}
+		if len(titles) != len(expected[i]) {
+			t.Errorf("Level %d: expected %v, got %v", i, expected[i], titles)
+			continue
+		}
+		for j := range titles {
+			if titles[j] != expected[i][j] {
+				t.Errorf("Level %d: expected %v, got %v", i, expected[i], titles)
+				break
+			}
+		}
+	}
+
+	qr := plan.Epics[0].Tasks[0].Subtasks[0]
+	if qr.Type != TicketTypeSubtask || qr.Parent.Title != "TOTP" || qr.Depth() != 2 {
+		t.Errorf("Unexpected subtask entry: type %s, depth %d", qr.Type, qr.Depth())
+	}
+	if entries := plan.Entries(); len(entries) != 7 || entries[1].Title != "TOTP" || entries[2].Title != "QR code" {
+		t.Errorf("Expected entries in outline order, got %d entries", len(entries))
+	}
+}
+
+func TestPlanValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		plan *Plan
+	}{
+		{"empty", &Plan{}},
+		{"missing title", &Plan{Epics: []*PlanEntry{{Tasks: []*PlanEntry{{Title: " "}}}}}},
+		{"subtask under epic", &Plan{Epic: &PlanEntry{Title: "MFA", Subtasks: []*PlanEntry{{Title: "QR"}}}}},
+		{"task under task", &Plan{Tasks: []*PlanEntry{{Title: "Docs", Tasks: []*PlanEntry{{Title: "More"}}}}}},
+	}
+
+	for _, tt := range tests {
+		if err := tt.plan.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	existing := &Plan{Epics: []*PlanEntry{{Key: "PROJ-1", Tasks: []*PlanEntry{{Title: "TOTP"}}}}}
+	if err := existing.Validate(); err != nil {
+		t.Errorf("Expected an existing epic without a title to be valid, got %v", err)
+	}
+}
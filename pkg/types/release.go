@@ -0,0 +1,31 @@
+package types
+
+import "strings"
+
+// Version is a project version in Jira, the release tickets are fixed in
+type Version struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	StartDate   string `json:"start_date,omitempty"`   // YYYY-MM-DD
+	ReleaseDate string `json:"release_date,omitempty"` // YYYY-MM-DD
+}
+
+// Component is a project component in Jira
+type Component struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// HasFixVersion reports whether the ticket is fixed in the named version
+func (t *Ticket) HasFixVersion(name string) bool {
+	for _, version := range t.Metadata.FixVersions {
+		if strings.EqualFold(version, name) {
+			return true
+		}
+	}
+	return false
+}
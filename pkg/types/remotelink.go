@@ -0,0 +1,12 @@
+package types
+
+// RemoteLink is a link from a ticket to something outside Jira, such as a
+// pull request, branch or design doc
+type RemoteLink struct {
+	ID           string `json:"id"`
+	TicketKey    string `json:"ticket_key"`
+	URL          string `json:"url"`
+	Title        string `json:"title"`
+	Summary      string `json:"summary,omitempty"`
+	Relationship string `json:"relationship,omitempty"` // e.g. "pull request"
+}
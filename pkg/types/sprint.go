@@ -0,0 +1,54 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sprint states as Jira reports them
+const (
+	SprintActive = "active"
+	SprintFuture = "future"
+	SprintClosed = "closed"
+)
+
+// SprintCurrent names the active sprint wherever a sprint is selected
+const SprintCurrent = "current"
+
+// Board is a Jira Software board
+type Board struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"` // scrum, kanban or simple
+	ProjectKey string `json:"project_key,omitempty"`
+}
+
+// Sprint is a Jira Software sprint
+type Sprint struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	State     string    `json:"state"` // SprintActive, SprintFuture or SprintClosed
+	BoardID   int       `json:"board_id,omitempty"`
+	Goal      string    `json:"goal,omitempty"`
+	StartDate time.Time `json:"start_date,omitempty"`
+	EndDate   time.Time `json:"end_date,omitempty"`
+}
+
+// IsActive reports whether the sprint is under way
+func (s *Sprint) IsActive() bool {
+	return strings.EqualFold(s.State, SprintActive)
+}
+
+// Matches reports whether the sprint is the one selected by query: its ID,
+// its name, or "current" for whichever sprint is active
+func (s *Sprint) Matches(query string) bool {
+	query = strings.TrimSpace(query)
+	if strings.EqualFold(query, SprintCurrent) {
+		return s.IsActive()
+	}
+	if id, err := strconv.Atoi(query); err == nil && id == s.ID {
+		return true
+	}
+	return strings.EqualFold(s.Name, query)
+}
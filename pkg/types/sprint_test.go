@@ -0,0 +1,27 @@
+package types
+
+import "testing"
+
+func TestSprintMatches(t *testing.T) {
+	active := &Sprint{ID: 12, Name: "Sprint 7", State: SprintActive}
+	future := &Sprint{ID: 13, Name: "Sprint 8", State: SprintFuture}
+
+	tests := []struct {
+		sprint   *Sprint
+		query    string
+		expected bool
+	}{
+		{active, "current", true},
+		{future, "current", false},
+		{active, "12", true},
+		{active, "sprint 7", true},
+		{active, "Sprint 8", false},
+		{future, " 13 ", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.sprint.Matches(tt.query); got != tt.expected {
+			t.Errorf("%s.Matches(%q) = %v, want %v", tt.sprint.Name, tt.query, got, tt.expected)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Ticket types as constants
 const (
@@ -25,19 +28,42 @@ type Ticket struct {
 
 // TicketMetadata contains metadata about the ticket
 type TicketMetadata struct {
-	Project  string    `json:"project"`
-	Assignee string    `json:"assignee"`
-	Created  time.Time `json:"created"`
-	Updated  time.Time `json:"updated"`
-	Labels   []string  `json:"labels"`
+	Project      string    `json:"project"`
+	Assignee     string    `json:"assignee"`                // Account ID on Cloud, username on Server
+	AssigneeName string    `json:"assignee_name,omitempty"` // Display name of the assignee
+	Created      time.Time `json:"created"`
+	Updated      time.Time `json:"updated"`
+	Labels       []string  `json:"labels"`
+	FixVersions  []string  `json:"fix_versions,omitempty"` // Names of the versions the ticket is fixed in
+	Components   []string  `json:"components,omitempty"`   // Names of the ticket's components
+	Sprint       *Sprint   `json:"sprint,omitempty"`       // Sprint the ticket is planned in, if any
 }
 
-// TicketRelationships defines parent/child relationships
+// TicketRelationships defines parent/child relationships and issue links
 type TicketRelationships struct {
-	ParentKey string   `json:"parent_key"` // Parent task for subtasks, empty for epics and orphan tasks
-	Children  []string `json:"children"`   // Child tickets
+	ParentKey string       `json:"parent_key"`      // Parent task for subtasks, empty for epics and orphan tasks
+	Children  []string     `json:"children"`        // Child tickets
+	Links     []TicketLink `json:"links,omitempty"` // Issue links to other tickets
+}
+
+// TicketLink is an issue link seen from the ticket that holds it
+type TicketLink struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`      // Link type name, e.g. "Blocks"
+	Direction string `json:"direction"` // LinkOutward or LinkInward
+	Relation  string `json:"relation"`  // Phrase read from this ticket, e.g. "is blocked by"
+	Key       string `json:"key"`       // The other ticket
+	Title     string `json:"title,omitempty"`
+	Status    string `json:"status,omitempty"`
+	Done      bool   `json:"done,omitempty"` // The other ticket's status is in the done category
 }
 
+// Issue link directions
+const (
+	LinkOutward = "outward" // This ticket is the subject, e.g. "blocks"
+	LinkInward  = "inward"  // This ticket is the object, e.g. "is blocked by"
+)
+
 // JiraData contains Jira-specific information
 type JiraData struct {
 	URL          string                 `json:"url"`
@@ -95,6 +121,17 @@ func (t *Ticket) IsSubtask() bool {
 	return t.Type == TicketTypeSubtask
 }
 
+// BlockedBy returns the keys of unfinished tickets that block this one
+func (t *Ticket) BlockedBy() []string {
+	var keys []string
+	for _, link := range t.Relationships.Links {
+		if link.Direction == LinkInward && strings.EqualFold(link.Type, "Blocks") && !link.Done {
+			keys = append(keys, link.Key)
+		}
+	}
+	return keys
+}
+
 // IsOrphanTask returns true if the task has no parent (not a subtask)
 func (t *Ticket) IsOrphanTask() bool {
 	return t.Type == TicketTypeTask && t.Relationships.ParentKey == ""
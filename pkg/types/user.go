@@ -0,0 +1,19 @@
+package types
+
+import "fmt"
+
+// User is a Jira user as jit knows them
+type User struct {
+	ID          string `json:"id"` // Account ID on Cloud, username on Server and Data Center
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email,omitempty"` // Often hidden by privacy settings
+	Active      bool   `json:"active"`
+}
+
+// String describes the user for display
+func (u *User) String() string {
+	if u.Email != "" {
+		return fmt.Sprintf("%s <%s>", u.DisplayName, u.Email)
+	}
+	return u.DisplayName
+}
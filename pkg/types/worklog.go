@@ -0,0 +1,57 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timer is a running time tracking session against a ticket
+type Timer struct {
+	TicketKey string    `json:"ticket_key"`
+	Started   time.Time `json:"started"`
+}
+
+// NewTimer starts a timer for a ticket
+func NewTimer(ticketKey string) *Timer {
+	return &Timer{
+		TicketKey: ticketKey,
+		Started:   time.Now(),
+	}
+}
+
+// Elapsed returns the time tracked up to now
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	if now.Before(t.Started) {
+		return 0
+	}
+	return now.Sub(t.Started)
+}
+
+// Worklog is time logged against a ticket. Worklogs waiting to be pushed
+// to Jira have no ID.
+type Worklog struct {
+	ID        string        `json:"id,omitempty"`
+	TicketKey string        `json:"ticket_key"`
+	Author    string        `json:"author,omitempty"`
+	Started   time.Time     `json:"started"`
+	TimeSpent time.Duration `json:"time_spent"`
+	Comment   string        `json:"comment,omitempty"`
+}
+
+// FormatDuration renders a duration the way Jira writes time spent, e.g. "1h 30m"
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		return "0m"
+	}
+
+	hours, minutes := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
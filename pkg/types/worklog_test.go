@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "0m"},
+		{20 * time.Second, "0m"},
+		{40 * time.Second, "1m"},
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{90*time.Minute + 10*time.Second, "1h 30m"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.duration); got != tt.expected {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.duration, got, tt.expected)
+		}
+	}
+}
+
+func TestTimerElapsed(t *testing.T) {
+	timer := NewTimer("TEST-1")
+
+	if elapsed := timer.Elapsed(timer.Started.Add(25 * time.Minute)); elapsed != 25*time.Minute {
+		t.Errorf("Expected 25m elapsed, got %v", elapsed)
+	}
+
+	// Clock changes never produce negative time
+	if elapsed := timer.Elapsed(timer.Started.Add(-time.Minute)); elapsed != 0 {
+		t.Errorf("Expected no elapsed time, got %v", elapsed)
+	}
+}